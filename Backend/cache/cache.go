@@ -0,0 +1,64 @@
+// Package cache keeps JSON encoded responses in Redis or in process memory
+// behind a common interface. Entries can carry tags and be dropped by tag.
+package cache
+
+import (
+	"Gator_blog/config"
+	"log"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// Cache stores values under keys for a while
+type Cache interface {
+	// Get decodes the value under key into dest and reports whether there
+	// was one
+	Get(key string, dest interface{}) (bool, error)
+	// Set stores value under key for ttl, tagged with tags
+	Set(key string, value interface{}, ttl time.Duration, tags ...string) error
+	// Clock tells how far the cache got in dropping tags. Read before a
+	// value is loaded, it is what the value is set at.
+	Clock() (int64, error)
+	// SetAt is Set for a value loaded after the cache was at clock. Nothing
+	// is stored when one of tags was dropped since, the value may predate
+	// it.
+	SetAt(clock int64, key string, value interface{}, ttl time.Duration, tags ...string) error
+	// Delete removes keys, deleting a missing key is not an error
+	Delete(keys ...string) error
+	// DeleteTag removes every entry tagged with one of tags
+	DeleteTag(tags ...string) error
+}
+
+// Default is the cache responses are kept in. Nothing is cached until
+// InitCache runs.
+var Default Cache = None{}
+
+// function to initialise the response cache, in Redis through client when
+// the driver is redis
+func InitCache(cfg config.CacheConfig, client *goredis.Client) {
+	switch cfg.Driver {
+	case "redis":
+		Default = NewRedis(client)
+		log.Println("Caching responses in Redis")
+	case "memory":
+		Default = NewLRU(cfg.Size)
+		log.Println("Caching up to", cfg.Size, "responses in memory")
+	default:
+		Default = None{}
+		log.Println("Response caching disabled")
+	}
+}
+
+// None caches nothing
+type None struct{}
+
+func (None) Get(key string, dest interface{}) (bool, error)                             { return false, nil }
+func (None) Set(key string, value interface{}, ttl time.Duration, tags ...string) error { return nil }
+func (None) Clock() (int64, error)                                                      { return 0, nil }
+func (None) Delete(keys ...string) error                                                { return nil }
+func (None) DeleteTag(tags ...string) error                                             { return nil }
+
+func (None) SetAt(clock int64, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	return nil
+}
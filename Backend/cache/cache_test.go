@@ -0,0 +1,176 @@
+package cache_test
+
+import (
+	"Gator_blog/cache"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+type post struct {
+	ID    uint
+	Title string
+}
+
+// runs test against the Redis and the in-memory cache
+func forEach(t *testing.T, test func(t *testing.T, c cache.Cache)) {
+	t.Run("redis", func(t *testing.T) {
+		mr := miniredis.RunT(t)
+		client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+		t.Cleanup(func() { client.Close() })
+		test(t, cache.NewRedis(client))
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, cache.NewLRU(100))
+	})
+}
+
+func TestGetSetDelete(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		var got post
+		found, err := c.Get("post:1", &got)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		assert.NoError(t, c.Set("post:1", post{ID: 1, Title: "Hello"}, time.Minute))
+		found, err = c.Get("post:1", &got)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "Hello", got.Title)
+
+		assert.NoError(t, c.Delete("post:1", "post:2"))
+		found, _ = c.Get("post:1", &got)
+		assert.False(t, found)
+	})
+}
+
+func TestDeleteTag(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		c.Set("user:1:blogs", []post{{ID: 1}}, time.Minute, "user:1")
+		c.Set("user:1:blogs:title:go", []post{}, time.Minute, "user:1")
+		c.Set("user:2:blogs", []post{{ID: 2}}, time.Minute, "user:2")
+
+		assert.NoError(t, c.DeleteTag("user:1", "unused"))
+		var got []post
+		found, _ := c.Get("user:1:blogs", &got)
+		assert.False(t, found)
+		found, _ = c.Get("user:1:blogs:title:go", &got)
+		assert.False(t, found)
+		found, _ = c.Get("user:2:blogs", &got)
+		assert.True(t, found)
+	})
+}
+
+func TestDeleteTagOnlyDropsEarlierEntries(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		c.Set("blog:1", post{ID: 1}, time.Minute, "blog:1", "author:1")
+		assert.NoError(t, c.DeleteTag("author:1"))
+
+		var got post
+		found, _ := c.Get("blog:1", &got)
+		assert.False(t, found)
+
+		// set again after the tag was dropped, the entry is current
+		c.Set("blog:1", post{ID: 1, Title: "Again"}, time.Minute, "blog:1", "author:1")
+		found, _ = c.Get("blog:1", &got)
+		assert.True(t, found)
+		assert.Equal(t, "Again", got.Title)
+
+		assert.NoError(t, c.DeleteTag("blog:1"))
+		found, _ = c.Get("blog:1", &got)
+		assert.False(t, found)
+	})
+}
+
+func TestSetAtSkipsValuesLoadedBeforeDrop(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		clock, err := c.Clock()
+		assert.NoError(t, err)
+		// the tag is dropped while the value is loaded
+		assert.NoError(t, c.DeleteTag("blog:1"))
+		assert.NoError(t, c.SetAt(clock, "blog:1", post{ID: 1, Title: "Old"}, time.Minute, "blog:1"))
+
+		var got post
+		found, _ := c.Get("blog:1", &got)
+		assert.False(t, found)
+
+		// other tags are not in the way
+		assert.NoError(t, c.SetAt(clock, "blog:2", post{ID: 2}, time.Minute, "blog:2"))
+		found, _ = c.Get("blog:2", &got)
+		assert.True(t, found)
+	})
+}
+
+func TestLRUForgetsOldDrops(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Set("a", 1, 0, "kept")
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, c.DeleteTag(fmt.Sprint("tag:", i)))
+	}
+	assert.LessOrEqual(t, cache.Dropped(c), 4)
+
+	var v int
+	found, _ := c.Get("a", &v)
+	assert.True(t, found)
+
+	// a drop forgotten still keeps values loaded before it out
+	clock, _ := c.Clock()
+	assert.NoError(t, c.DeleteTag("kept"))
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, c.DeleteTag(fmt.Sprint("tag:", i)))
+	}
+	c.SetAt(clock, "a", 2, 0, "kept")
+	found, _ = c.Get("a", &v)
+	assert.False(t, found)
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewLRU(2)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	var v int
+	c.Get("a", &v)
+	c.Set("c", 3, 0, "tag")
+
+	assert.Equal(t, 2, c.Len())
+	found, _ := c.Get("b", &v)
+	assert.False(t, found)
+	found, _ = c.Get("a", &v)
+	assert.True(t, found)
+
+	// dropping a tag leaves stale entries in place until they are read
+	for i := 0; i < 2; i++ {
+		c.Set(fmt.Sprint("k", i), i, 0, "tag")
+	}
+	assert.NoError(t, c.DeleteTag("tag"))
+	assert.Equal(t, 2, c.Len())
+	found, _ = c.Get("k0", &v)
+	assert.False(t, found)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestLRUExpiry(t *testing.T) {
+	c := cache.NewLRU(10)
+	c.Set("short", "v", time.Millisecond)
+	c.Set("long", "v", time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	var v string
+	found, _ := c.Get("short", &v)
+	assert.False(t, found)
+	found, _ = c.Get("long", &v)
+	assert.True(t, found)
+}
+
+func TestNoneCachesNothing(t *testing.T) {
+	var c cache.Cache = cache.None{}
+	assert.NoError(t, c.Set("a", 1, time.Minute))
+	var v int
+	found, err := c.Get("a", &v)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,24 @@
+package cache
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entry encodes value the way Remember stores it, fresh for ttl
+func Entry(value interface{}, ttl time.Duration) interface{} {
+	data, _ := json.Marshal(value)
+	return remembered{Value: data, Expires: time.Now().Add(ttl)}
+}
+
+// WaitForRefreshes blocks until the background refreshes are done
+func WaitForRefreshes() {
+	refreshes.Wait()
+}
+
+// Dropped is the number of dropped tags c remembers
+func Dropped(c *LRU) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.dropped)
+}
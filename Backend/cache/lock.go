@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// Locker is implemented by caches shared between instances, so that only
+// one of them loads a missing entry at a time
+type Locker interface {
+	// Lock takes the lock name for at most ttl unless someone else holds
+	// it. release gives it back if it was not taken over in the meantime.
+	Lock(name string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// deletes the lock only while it still holds our token
+var unlockScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+func lockKey(name string) string {
+	return "lock:" + name
+}
+
+func (r *Redis) Lock(name string, ttl time.Duration) (func(), bool, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, false, err
+	}
+	token := hex.EncodeToString(raw)
+	ok, err := r.client.SetNX(r.ctx, lockKey(name), token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	release := func() {
+		unlockScript.Run(r.ctx, r.client, []string{lockKey(name)}, token)
+	}
+	return release, true, nil
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// LRU keeps up to a fixed number of entries in process memory, dropping the
+// least recently used one when full. Values are stored encoded, so callers
+// never share them. Tags are dropped like in Redis, against a clock, and
+// stale entries are dropped when next read or pushed out.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+	clock   int64
+	dropped map[string]int64 // clock each tag was last dropped at
+	// clock up to which drops were forgotten, tagged values loaded earlier
+	// are not stored
+	forgotten int64
+	forgetAt  int // how many drops DeleteTag remembers before forgetting
+}
+
+type lruEntry struct {
+	key     string
+	data    []byte
+	expires time.Time // zero when the entry does not expire
+	clock   int64
+	tags    []string
+}
+
+func NewLRU(size int) *LRU {
+	if size < 1 {
+		size = 1
+	}
+	return &LRU{size: size, order: list.New(), entries: map[string]*list.Element{}, dropped: map[string]int64{}, forgetAt: 2 * size}
+}
+
+// reports whether a tag of entry was dropped since it was set, c.mu must be
+// held
+func (c *LRU) stale(entry *lruEntry) bool {
+	for _, tag := range entry.tags {
+		if c.dropped[tag] > entry.clock {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *LRU) Get(key string, dest interface{}) (bool, error) {
+	c.mu.Lock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return false, nil
+	}
+	entry := elem.Value.(*lruEntry)
+	if (!entry.expires.IsZero() && time.Now().After(entry.expires)) || c.stale(entry) {
+		c.remove(elem)
+		c.mu.Unlock()
+		return false, nil
+	}
+	c.order.MoveToFront(elem)
+	data := entry.data
+	c.mu.Unlock()
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *LRU) Set(key string, value interface{}, ttl time.Duration, tags ...string) error {
+	clock, _ := c.Clock()
+	return c.SetAt(clock, key, value, ttl, tags...)
+}
+
+func (c *LRU) Clock() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clock, nil
+}
+
+func (c *LRU) SetAt(clock int64, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	entry := &lruEntry{key: key, data: data, clock: clock, tags: tags}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stale(entry) || (len(tags) > 0 && clock < c.forgotten) {
+		return nil
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *LRU) Delete(keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *LRU) DeleteTag(tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	for _, tag := range tags {
+		c.dropped[tag] = c.clock
+	}
+	if len(c.dropped) > c.forgetAt {
+		c.forget()
+	}
+	return nil
+}
+
+// forgets the drops of tags no entry carries, so that the drops remembered
+// grow with the entries rather than with every tag ever dropped. Stale and
+// expired entries are dropped first so they do not hold on to theirs.
+// c.mu must be held.
+func (c *LRU) forget() {
+	now := time.Now()
+	carried := map[string]bool{}
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*lruEntry)
+		if (!entry.expires.IsZero() && now.After(entry.expires)) || c.stale(entry) {
+			c.remove(elem)
+		} else {
+			for _, tag := range entry.tags {
+				carried[tag] = true
+			}
+		}
+		elem = next
+	}
+	for tag, clock := range c.dropped {
+		if !carried[tag] {
+			delete(c.dropped, tag)
+			if clock > c.forgotten {
+				c.forgotten = clock
+			}
+		}
+	}
+	c.forgetAt = 2 * c.size
+	if 2*len(c.dropped) > c.forgetAt {
+		c.forgetAt = 2 * len(c.dropped)
+	}
+}
+
+// Len is the number of entries held, expired and stale ones included until
+// they are next read or pushed out
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// drops an entry, c.mu must be held
+func (c *LRU) remove(elem *list.Element) {
+	entry := c.order.Remove(elem).(*lruEntry)
+	delete(c.entries, entry.key)
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// how long a tag version is kept after it was last used. Entries must not
+// live longer, or one set before the tag was dropped could look current
+// once the version is gone.
+const tagTTL = 24 * time.Hour
+
+// counts the tags dropped so far, the clock of the cache
+const clockKey = "tags:clock"
+
+// Redis keeps a clock counting up with every DeleteTag and stores for each
+// tag the clock it was last dropped at. Entries are stored with the clock
+// they were loaded at and are stale once one of their tags was dropped
+// later, so dropping a tag is a single write however many entries carry it.
+type Redis struct {
+	client *goredis.Client
+	ctx    context.Context
+}
+
+func NewRedis(client *goredis.Client) *Redis {
+	return &Redis{client: client, ctx: context.Background()}
+}
+
+func tagKey(tag string) string {
+	return "tag:" + tag
+}
+
+// what is stored under a key
+type redisEntry struct {
+	Clock int64           `json:"c"`
+	Tags  []string        `json:"t,omitempty"`
+	Data  json.RawMessage `json:"d"`
+}
+
+// stores the entry in KEYS[1] unless one of the tags in the rest of KEYS
+// was dropped after the clock in ARGV[1], checked and written at once
+var setScript = goredis.NewScript(`
+for i = 2, #KEYS do
+	local dropped = redis.call("GET", KEYS[i])
+	if dropped and tonumber(dropped) > tonumber(ARGV[1]) then
+		return 0
+	end
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+else
+	redis.call("SET", KEYS[1], ARGV[2])
+end
+for i = 2, #KEYS do
+	redis.call("EXPIRE", KEYS[i], ARGV[4])
+end
+return 1`)
+
+// moves the clock in KEYS[1] on and marks the tags in the rest of KEYS as
+// dropped at it
+var dropScript = goredis.NewScript(`
+local clock = redis.call("INCR", KEYS[1])
+for i = 2, #KEYS do
+	redis.call("SET", KEYS[i], clock, "EX", ARGV[1])
+end
+return clock`)
+
+func tagKeys(tags []string) []string {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagKey(tag)
+	}
+	return keys
+}
+
+func (r *Redis) Get(key string, dest interface{}) (bool, error) {
+	data, err := r.client.Get(r.ctx, key).Bytes()
+	if err == goredis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var entry redisEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return false, err
+	}
+	if len(entry.Tags) > 0 {
+		dropped, err := r.client.MGet(r.ctx, tagKeys(entry.Tags)...).Result()
+		if err != nil {
+			return false, err
+		}
+		for _, value := range dropped {
+			if s, ok := value.(string); ok {
+				if clock, _ := strconv.ParseInt(s, 10, 64); clock > entry.Clock {
+					return false, nil
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(entry.Data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *Redis) Set(key string, value interface{}, ttl time.Duration, tags ...string) error {
+	clock, err := r.Clock()
+	if err != nil {
+		return err
+	}
+	return r.SetAt(clock, key, value, ttl, tags...)
+}
+
+func (r *Redis) Clock() (int64, error) {
+	clock, err := r.client.Get(r.ctx, clockKey).Int64()
+	if err == goredis.Nil {
+		return 0, nil
+	}
+	return clock, err
+}
+
+func (r *Redis) SetAt(clock int64, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(redisEntry{Clock: clock, Tags: tags, Data: data})
+	if err != nil {
+		return err
+	}
+	keys := append([]string{key}, tagKeys(tags)...)
+	return setScript.Run(r.ctx, r.client, keys, clock, data, ttl.Milliseconds(), int(tagTTL.Seconds())).Err()
+}
+
+func (r *Redis) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.client.Del(r.ctx, keys...).Err()
+}
+
+func (r *Redis) DeleteTag(tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	keys := append([]string{clockKey}, tagKeys(tags)...)
+	return dropScript.Run(r.ctx, r.client, keys, int(tagTTL.Seconds())).Err()
+}
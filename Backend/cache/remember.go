@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"Gator_blog/redis"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Options tell Remember how long a loaded value is kept
+type Options struct {
+	// TTL is how long the value is fresh
+	TTL time.Duration
+	// StaleTTL is how long after TTL the value is still served while a
+	// single request refreshes it in the background
+	StaleTTL time.Duration
+}
+
+// Status tells where Remember got a value from
+type Status string
+
+const (
+	Hit   Status = "HIT"
+	Miss  Status = "MISS"
+	Stale Status = "STALE"
+)
+
+// Loader loads a value missing from the cache and returns the tags to store
+// it under
+type Loader func() (value interface{}, tags []string, err error)
+
+// scales how early an entry may be refreshed before its TTL, relative to
+// how long it took to load. 1 spreads refreshes without wasting many.
+const earlyBeta = 1.0
+
+const (
+	// how long the instance loading a missing entry holds its lock
+	lockTTL = 5 * time.Second
+	// how long other instances wait for that entry before loading it too
+	lockWait = 2 * time.Second
+	lockPoll = 20 * time.Millisecond
+)
+
+// Metrics counts how Remember answered: hits, misses and stale (served past
+// their TTL), early (refreshed ahead of their TTL), coalesced (misses that
+// waited for a load already running here) and errors (failed cache calls).
+// It is published with the other expvars at /debug/vars.
+var Metrics = expvar.NewMap("cache")
+
+var (
+	flights    singleflight.Group
+	refreshing sync.Map // keys being refreshed in the background
+	refreshes  sync.WaitGroup
+)
+
+// what Remember stores under a key
+type remembered struct {
+	Value   json.RawMessage `json:"value"`
+	Expires time.Time       `json:"expires"` // end of the fresh period
+	Delta   time.Duration   `json:"delta"`   // how long loading took
+}
+
+// Remember decodes the value under key into dest, loading and storing it
+// with load when it is missing. Concurrent misses for a key share one load,
+// and across instances only the one holding the Redis lock loads while the
+// others wait for its result. Values past their TTL, and now and then ones
+// close to it, are served while refreshed in the background. Failing cache
+// calls fall back to load, only its errors are returned.
+func Remember(c Cache, key string, dest interface{}, opts Options, load Loader) (Status, error) {
+	var entry remembered
+	found, err := c.Get(key, &entry)
+	countError(err)
+	if found {
+		now := time.Now()
+		status := Hit
+		if now.After(entry.Expires) {
+			status = Stale
+			refresh(c, key, opts, load)
+		} else if expiresEarly(entry, now) {
+			Metrics.Add("early", 1)
+			refresh(c, key, opts, load)
+		}
+		if status == Stale {
+			Metrics.Add("stale", 1)
+		} else {
+			Metrics.Add("hits", 1)
+		}
+		return status, json.Unmarshal(entry.Value, dest)
+	}
+
+	Metrics.Add("misses", 1)
+	data, err, shared := flights.Do(key, func() (interface{}, error) {
+		clock, err := c.Clock()
+		if err != nil {
+			// a value loaded without the clock could not be told from a
+			// stale one, it is served but not stored
+			countError(err)
+			value, _, err := load()
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(value)
+		}
+		return fill(c, key, opts, load, true, clock)
+	})
+	if shared {
+		Metrics.Add("coalesced", 1)
+	}
+	if err != nil {
+		return Miss, err
+	}
+	return Miss, json.Unmarshal(data.([]byte), dest)
+}
+
+// decides at random whether entry is refreshed now rather than at its TTL,
+// the more likely the closer it is and the longer it takes to load
+func expiresEarly(entry remembered, now time.Time) bool {
+	if entry.Delta <= 0 {
+		return false
+	}
+	gap := -float64(entry.Delta) * earlyBeta * math.Log(1-rand.Float64())
+	return now.Add(time.Duration(gap)).After(entry.Expires)
+}
+
+// reloads the entry under key in the background unless that already
+// happens here. The clock is read before going, so a tag dropped while the
+// refresh runs keeps its value out of the cache.
+func refresh(c Cache, key string, opts Options, load Loader) {
+	if _, busy := refreshing.LoadOrStore(key, true); busy {
+		return
+	}
+	clock, err := c.Clock()
+	if err != nil {
+		countError(err)
+		refreshing.Delete(key)
+		return
+	}
+	refreshes.Add(1)
+	go func() {
+		defer refreshes.Done()
+		defer refreshing.Delete(key)
+		if _, err := fill(c, key, opts, load, false, clock); err != nil {
+			log.Println("Error refreshing", key, err)
+		}
+	}()
+}
+
+// loads the value of key and stores it as loaded when the cache was at
+// clock, returning it encoded. When another instance holds the lock of
+// key, wait tells whether to wait for its value or to leave it be and
+// return nil.
+func fill(c Cache, key string, opts Options, load Loader, wait bool, clock int64) ([]byte, error) {
+	if locker, ok := c.(Locker); ok {
+		release, acquired, err := locker.Lock(key, lockTTL)
+		countError(err)
+		switch {
+		case acquired:
+			defer release()
+		case err == nil && !wait:
+			return nil, nil
+		case err == nil:
+			if data, ok := await(c, key); ok {
+				return data, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	value, tags, err := load()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	entry := remembered{Value: data, Expires: time.Now().Add(opts.TTL), Delta: time.Since(start)}
+	countError(c.SetAt(clock, key, entry, opts.TTL+opts.StaleTTL, tags...))
+	return data, nil
+}
+
+// polls for the entry another instance is loading, for at most lockWait
+func await(c Cache, key string) ([]byte, bool) {
+	for deadline := time.Now().Add(lockWait); time.Now().Before(deadline); {
+		time.Sleep(lockPoll)
+		var entry remembered
+		found, err := c.Get(key, &entry)
+		countError(err)
+		if err != nil {
+			return nil, false
+		}
+		if found {
+			return entry.Value, true
+		}
+	}
+	return nil, false
+}
+
+// counts a failed cache call. Calls the breaker refused while Redis is down
+// are expected and not worth a line per request.
+func countError(err error) {
+	if err == nil {
+		return
+	}
+	Metrics.Add("errors", 1)
+	if !errors.Is(err, redis.ErrCircuitOpen) {
+		log.Println("Cache error:", err)
+	}
+}
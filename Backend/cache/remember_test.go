@@ -0,0 +1,180 @@
+package cache_test
+
+import (
+	"Gator_blog/cache"
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+var fresh = cache.Options{TTL: time.Minute, StaleTTL: time.Minute}
+
+func metric(name string) int64 {
+	if v, ok := cache.Metrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestRememberCoalescesMisses(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		var loads int32
+		load := func() (interface{}, []string, error) {
+			atomic.AddInt32(&loads, 1)
+			time.Sleep(50 * time.Millisecond)
+			return post{ID: 1, Title: "Hello"}, []string{"blog:1"}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var got post
+				status, err := cache.Remember(c, "blog:1", &got, fresh, load)
+				assert.NoError(t, err)
+				assert.Equal(t, cache.Miss, status)
+				assert.Equal(t, "Hello", got.Title)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), loads)
+
+		hits := metric("hits")
+		var got post
+		status, _ := cache.Remember(c, "blog:1", &got, fresh, load)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, hits+1, metric("hits"))
+
+		// dropped entries are loaded again, not served stale
+		c.DeleteTag("blog:1")
+		status, _ = cache.Remember(c, "blog:1", &got, fresh, load)
+		assert.Equal(t, cache.Miss, status)
+		assert.Equal(t, int32(2), loads)
+	})
+}
+
+func TestRememberServesStaleWhileRefreshing(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		title := "First"
+		load := func() (interface{}, []string, error) {
+			return post{Title: title}, nil, nil
+		}
+		opts := cache.Options{TTL: 10 * time.Millisecond, StaleTTL: time.Minute}
+
+		var got post
+		cache.Remember(c, "post", &got, opts, load)
+		time.Sleep(20 * time.Millisecond)
+		title = "Second"
+
+		stale := metric("stale")
+		status, err := cache.Remember(c, "post", &got, opts, load)
+		assert.NoError(t, err)
+		assert.Equal(t, cache.Stale, status)
+		assert.Equal(t, "First", got.Title)
+		assert.Equal(t, stale+1, metric("stale"))
+
+		cache.WaitForRefreshes()
+		status, _ = cache.Remember(c, "post", &got, opts, load)
+		assert.Equal(t, cache.Hit, status)
+		assert.Equal(t, "Second", got.Title)
+	})
+}
+
+func TestRememberRefreshKeepsDroppedValuesOut(t *testing.T) {
+	forEach(t, func(t *testing.T, c cache.Cache) {
+		title := "First"
+		loading := make(chan struct{})
+		loaded := make(chan struct{})
+		load := func() (interface{}, []string, error) {
+			if title == "First" {
+				return post{Title: title}, []string{"blog:1"}, nil
+			}
+			// the refresh reads the old row, then the blog is updated
+			close(loading)
+			<-loaded
+			return post{Title: "First"}, []string{"blog:1"}, nil
+		}
+		opts := cache.Options{TTL: 10 * time.Millisecond, StaleTTL: time.Minute}
+
+		var got post
+		cache.Remember(c, "blog:1", &got, opts, load)
+		time.Sleep(20 * time.Millisecond)
+		title = "Slow"
+		status, _ := cache.Remember(c, "blog:1", &got, opts, load)
+		assert.Equal(t, cache.Stale, status)
+
+		<-loading
+		assert.NoError(t, c.DeleteTag("blog:1"))
+		close(loaded)
+		cache.WaitForRefreshes()
+
+		// the old value is not brought back
+		found, err := c.Get("blog:1", &struct{}{})
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestRememberDoesNotStoreErrors(t *testing.T) {
+	c := cache.NewLRU(10)
+	var got post
+	_, err := cache.Remember(c, "post", &got, fresh, func() (interface{}, []string, error) {
+		return nil, nil, errors.New("not found")
+	})
+	assert.Error(t, err)
+	assert.Zero(t, c.Len())
+}
+
+func TestRememberWaitsForOtherInstance(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	c := cache.NewRedis(client)
+
+	// another instance is loading the entry
+	release, ok, err := c.Lock("post", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	_, ok, _ = c.Lock("post", time.Second)
+	assert.False(t, ok)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		c.Set("post", cache.Entry(post{Title: "Theirs"}, time.Minute), 2*time.Minute)
+		release()
+	}()
+
+	var got post
+	status, err := cache.Remember(c, "post", &got, fresh, func() (interface{}, []string, error) {
+		t.Error("loaded although the other instance did")
+		return nil, nil, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, cache.Miss, status)
+	assert.Equal(t, "Theirs", got.Title)
+	assert.False(t, mr.Exists("lock:post"))
+}
+
+func TestLockReleaseKeepsTakenOverLock(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	c := cache.NewRedis(client)
+
+	release, _, _ := c.Lock("post", time.Second)
+	mr.FastForward(2 * time.Second)
+	_, ok, _ := c.Lock("post", time.Second)
+	assert.True(t, ok)
+
+	// the first holder's lock expired, releasing it late must not free the
+	// second one
+	release()
+	assert.True(t, mr.Exists("lock:post"))
+}
@@ -0,0 +1,2523 @@
+// Code generated by cmd/openapi from the OpenAPI document of the API. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AnalyticsTotals struct {
+	Comments      int64 `json:"comments"`
+	Likes         int64 `json:"likes"`
+	UniqueReaders int64 `json:"unique_readers"`
+	Views         int64 `json:"views"`
+}
+
+type Blog struct {
+	ID            int64     `json:"ID"`
+	Post          string    `json:"Post"`
+	Title         string    `json:"Title"`
+	UserName      string    `json:"UserName"`
+	CommentsCount int64     `json:"comments_count"`
+	CoverMediaID  *int64    `json:"cover_media_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	LikesCount    int64     `json:"likes_count"`
+	Tags          []Tag     `json:"tags,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        int64     `json:"user_id"`
+	Version       int64     `json:"version"`
+	ViewsCount    int64     `json:"views_count"`
+}
+
+type BlogDailyStat struct {
+	BlogID        int64  `json:"blog_id"`
+	Comments      int64  `json:"comments"`
+	Day           string `json:"day"`
+	Likes         int64  `json:"likes"`
+	UniqueReaders int64  `json:"unique_readers"`
+	Views         int64  `json:"views"`
+}
+
+type BlogSEO struct {
+	Author        string            `json:"author"`
+	CanonicalURL  string            `json:"canonical_url"`
+	Description   string            `json:"description"`
+	Excerpt       string            `json:"excerpt"`
+	ModifiedTime  time.Time         `json:"modified_time"`
+	OpenGraph     map[string]string `json:"open_graph"`
+	PublishedTime time.Time         `json:"published_time"`
+	Tags          []string          `json:"tags"`
+	Title         string            `json:"title"`
+	Twitter       map[string]string `json:"twitter"`
+}
+
+type BlogV2 struct {
+	CommentsCount int64     `json:"comments_count"`
+	CoverMediaID  *int64    `json:"cover_media_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            int64     `json:"id"`
+	LikesCount    int64     `json:"likes_count"`
+	Post          string    `json:"post"`
+	Tags          []Tag     `json:"tags,omitempty"`
+	Title         string    `json:"title"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	UserID        int64     `json:"user_id"`
+	UserName      string    `json:"user_name"`
+	Version       int64     `json:"version"`
+	ViewsCount    int64     `json:"views_count"`
+}
+
+type BlogWithMeta struct {
+	BookmarkedByMe bool        `json:"bookmarked_by_me"`
+	Comments       []Comment   `json:"comments"`
+	CommentsCount  int64       `json:"comments_count"`
+	CoverImage     *CoverImage `json:"cover_image"`
+	CreatedAt      time.Time   `json:"created_at"`
+	ID             int64       `json:"id"`
+	Likes          int64       `json:"likes"`
+	Post           string      `json:"post"`
+	Title          string      `json:"title"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	UserID         int64       `json:"user_id"`
+	UserName       string      `json:"user_name"`
+}
+
+type Bookmark struct {
+	BlogID    int64     `json:"blog_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+}
+
+type Comment struct {
+	BlogID    int64     `json:"blog_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    int64     `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Version   int64     `json:"version"`
+}
+
+type CommentRequest struct {
+	Content string `json:"content"`
+}
+
+type CoverImage struct {
+	Height   int64  `json:"height"`
+	SmallURL string `json:"small_url"`
+	URL      string `json:"url"`
+	Width    int64  `json:"width"`
+}
+
+type CreateBlogRequest struct {
+	Post  string   `json:"post"`
+	Tags  []string `json:"tags,omitempty"`
+	Title string   `json:"title"`
+}
+
+type CreateReadingListRequest struct {
+	Name   *string `json:"name"`
+	Public *bool   `json:"public,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Rule    string `json:"rule"`
+}
+
+type Follow struct {
+	CreatedAt  time.Time `json:"created_at"`
+	FolloweeID int64     `json:"followee_id"`
+	FollowerID int64     `json:"follower_id"`
+	ID         int64     `json:"id"`
+}
+
+type Like struct {
+	BlogID    int64     `json:"blog_id"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+}
+
+type Media struct {
+	ContentType string         `json:"content_type"`
+	CreatedAt   time.Time      `json:"created_at"`
+	Filename    string         `json:"filename"`
+	Height      int64          `json:"height"`
+	ID          int64          `json:"id"`
+	Purpose     string         `json:"purpose"`
+	Size        int64          `json:"size"`
+	URL         string         `json:"url"`
+	UserID      int64          `json:"user_id"`
+	Variants    []MediaVariant `json:"variants"`
+	Width       int64          `json:"width"`
+}
+
+type MediaVariant struct {
+	Height int64  `json:"height"`
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	URL    string `json:"url"`
+	Width  int64  `json:"width"`
+}
+
+type PostAnalytics struct {
+	Daily  []BlogDailyStat `json:"daily"`
+	ID     int64           `json:"id"`
+	Title  string          `json:"title"`
+	Totals AnalyticsTotals `json:"totals"`
+}
+
+type Problem struct {
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Status   int64        `json:"status"`
+	Title    string       `json:"title"`
+	Type     string       `json:"type"`
+}
+
+type Profile struct {
+	AvatarURL      string    `json:"avatar_url"`
+	FollowersCount int64     `json:"followers_count"`
+	FollowingCount int64     `json:"following_count"`
+	ID             int64     `json:"id"`
+	JoinedAt       time.Time `json:"joined_at"`
+	LikesReceived  int64     `json:"likes_received"`
+	PostsCount     int64     `json:"posts_count"`
+	Username       string    `json:"username"`
+}
+
+type PublicUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type ReadingList struct {
+	CreatedAt  time.Time `json:"created_at"`
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Public     bool      `json:"public"`
+	ShareToken *string   `json:"share_token,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UserID     int64     `json:"user_id"`
+}
+
+type ReadingListItem struct {
+	BlogID        int64     `json:"blog_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            int64     `json:"id"`
+	Position      int64     `json:"position"`
+	ReadingListID int64     `json:"reading_list_id"`
+}
+
+type ReadingListItemRequest struct {
+	BlogID int64 `json:"blog_id"`
+}
+
+type ReorderReadingListRequest struct {
+	BlogIds []int64 `json:"blog_ids,omitempty"`
+}
+
+type ResetCodeRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Email string `json:"email"`
+	// NewPassword must mix at least 2 of lower case letters, upper case letters, digits and symbols
+	NewPassword string `json:"new_password"`
+}
+
+type SignInRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type SignUpRequest struct {
+	Email string `json:"email"`
+	// Password must mix at least 2 of lower case letters, upper case letters, digits and symbols
+	Password string `json:"password"`
+	Username string `json:"username"`
+}
+
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type UpdateBlogRequest struct {
+	Post  *string  `json:"post,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	Title *string  `json:"title,omitempty"`
+}
+
+type UpdateReadingListRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Public *bool   `json:"public,omitempty"`
+}
+
+type VerifyCodeRequest struct {
+	Code  string `json:"code"`
+	Email string `json:"email"`
+}
+
+// ListAllBlogsWithMetaV1 calls GET /api/all-blogs-with-meta
+//
+// List every blog with its counters and latest comments.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListAllBlogsWithMetaV1(ctx context.Context, params *ListAllBlogsWithMetaV1Params, opts ...RequestOption) (*ListAllBlogsWithMetaV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Search != "" {
+			query.Set("search", params.Search)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListAllBlogsWithMetaV1Response
+	if err := c.send(ctx, "GET", "/api/all-blogs-with-meta", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlogsV1 calls GET /api/blogs
+//
+// List the signed in user's blogs.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListBlogsV1(ctx context.Context, params *ListBlogsV1Params, opts ...RequestOption) (*ListBlogsV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Title != "" {
+			query.Set("title", params.Title)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListBlogsV1Response
+	if err := c.send(ctx, "GET", "/api/blogs", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBlogV1 calls POST /api/blogs
+//
+// Publish a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) CreateBlogV1(ctx context.Context, body CreateBlogRequest, opts ...RequestOption) (*CreateBlogV1Response, error) {
+	var out CreateBlogV1Response
+	if err := c.send(ctx, "POST", "/api/blogs", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlogsWithMetaV1 calls GET /api/blogs-with-meta
+//
+// List the signed in user's blogs with their counters and latest comments.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListBlogsWithMetaV1(ctx context.Context, params *ListBlogsWithMetaV1Params, opts ...RequestOption) (*ListBlogsWithMetaV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Search != "" {
+			query.Set("search", params.Search)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListBlogsWithMetaV1Response
+	if err := c.send(ctx, "GET", "/api/blogs-with-meta", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteBlogV1 calls DELETE /api/blogs/{id}
+//
+// Delete a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) DeleteBlogV1(ctx context.Context, id int64, opts ...RequestOption) (*DeleteBlogV1Response, error) {
+	var out DeleteBlogV1Response
+	if err := c.send(ctx, "DELETE", "/api/blogs/"+strconv.FormatInt(id, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBlogV1 calls GET /api/blogs/{id}
+//
+// Fetch a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetBlogV1(ctx context.Context, id int64, params *GetBlogV1Params, opts ...RequestOption) (*GetBlogV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out GetBlogV1Response
+	if err := c.send(ctx, "GET", "/api/blogs/"+strconv.FormatInt(id, 10), query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PatchBlogV1 calls PATCH /api/blogs/{id}
+//
+// Change a blog with a JSON Merge Patch.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) PatchBlogV1(ctx context.Context, id int64, params *PatchBlogV1Params, body UpdateBlogRequest, opts ...RequestOption) (*PatchBlogV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out PatchBlogV1Response
+	if err := c.send(ctx, "PATCH", "/api/blogs/"+strconv.FormatInt(id, 10), query, header, body, "application/merge-patch+json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateBlogV1 calls PUT /api/blogs/{id}
+//
+// Change a blog, fields left out keep their value.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) UpdateBlogV1(ctx context.Context, id int64, params *UpdateBlogV1Params, body UpdateBlogRequest, opts ...RequestOption) (*UpdateBlogV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out UpdateBlogV1Response
+	if err := c.send(ctx, "PUT", "/api/blogs/"+strconv.FormatInt(id, 10), query, header, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveBookmarkV1 calls DELETE /api/blogs/{id}/bookmark
+//
+// Remove a bookmark.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) RemoveBookmarkV1(ctx context.Context, id int64, opts ...RequestOption) (*RemoveBookmarkV1Response, error) {
+	var out RemoveBookmarkV1Response
+	if err := c.send(ctx, "DELETE", "/api/blogs/"+strconv.FormatInt(id, 10)+"/bookmark", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BookmarkBlogV1 calls POST /api/blogs/{id}/bookmark
+//
+// Bookmark a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) BookmarkBlogV1(ctx context.Context, id int64, opts ...RequestOption) (*Bookmark, error) {
+	var out Bookmark
+	if err := c.send(ctx, "POST", "/api/blogs/"+strconv.FormatInt(id, 10)+"/bookmark", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCommentsV1 calls GET /api/blogs/{id}/comments
+//
+// List the comments of a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListCommentsV1(ctx context.Context, id int64, params *ListCommentsV1Params, opts ...RequestOption) ([]Comment, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []Comment
+	err := c.send(ctx, "GET", "/api/blogs/"+strconv.FormatInt(id, 10)+"/comments", query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// AddCommentV1 calls POST /api/blogs/{id}/comments
+//
+// Comment on a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) AddCommentV1(ctx context.Context, id int64, body CommentRequest, opts ...RequestOption) (*Comment, error) {
+	var out Comment
+	if err := c.send(ctx, "POST", "/api/blogs/"+strconv.FormatInt(id, 10)+"/comments", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCommentV1 calls DELETE /api/blogs/{id}/comments/{commentId}
+//
+// Delete one of your comments.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) DeleteCommentV1(ctx context.Context, id int64, commentID int64, opts ...RequestOption) (*DeleteCommentV1Response, error) {
+	var out DeleteCommentV1Response
+	if err := c.send(ctx, "DELETE", "/api/blogs/"+strconv.FormatInt(id, 10)+"/comments/"+strconv.FormatInt(commentID, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EditCommentV1 calls PUT /api/blogs/{id}/comments/{commentId}
+//
+// Change one of your comments.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) EditCommentV1(ctx context.Context, id int64, commentID int64, params *EditCommentV1Params, body CommentRequest, opts ...RequestOption) (*EditCommentV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out EditCommentV1Response
+	if err := c.send(ctx, "PUT", "/api/blogs/"+strconv.FormatInt(id, 10)+"/comments/"+strconv.FormatInt(commentID, 10), query, header, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveBlogCoverV1 calls DELETE /api/blogs/{id}/cover
+//
+// Remove the cover image of one of your blogs.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) RemoveBlogCoverV1(ctx context.Context, id int64, opts ...RequestOption) (*RemoveBlogCoverV1Response, error) {
+	var out RemoveBlogCoverV1Response
+	if err := c.send(ctx, "DELETE", "/api/blogs/"+strconv.FormatInt(id, 10)+"/cover", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetBlogCoverV1 calls PUT /api/blogs/{id}/cover
+//
+// Upload the cover image of one of your blogs.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) SetBlogCoverV1(ctx context.Context, id int64, filename string, file io.Reader, opts ...RequestOption) (*SetBlogCoverV1Response, error) {
+	var out SetBlogCoverV1Response
+	if err := c.upload(ctx, "PUT", "/api/blogs/"+strconv.FormatInt(id, 10)+"/cover", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CountLikesV1 calls GET /api/blogs/{id}/likes
+//
+// Count the likes of a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) CountLikesV1(ctx context.Context, id int64, opts ...RequestOption) (*CountLikesV1Response, error) {
+	var out CountLikesV1Response
+	if err := c.send(ctx, "GET", "/api/blogs/"+strconv.FormatInt(id, 10)+"/likes", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ToggleLikeV1 calls POST /api/blogs/{id}/likes
+//
+// Like a blog, or take the like back when it is already liked.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ToggleLikeV1(ctx context.Context, id int64, opts ...RequestOption) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.send(ctx, "POST", "/api/blogs/"+strconv.FormatInt(id, 10)+"/likes", nil, nil, nil, "", &out, opts)
+	return out, err
+}
+
+// GetBlogMetaV1 calls GET /api/blogs/{id}/meta
+//
+// Search engine and social card metadata of a blog.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetBlogMetaV1(ctx context.Context, id int64, opts ...RequestOption) (*BlogSEO, error) {
+	var out BlogSEO
+	if err := c.send(ctx, "GET", "/api/blogs/"+strconv.FormatInt(id, 10)+"/meta", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDocs calls GET /api/docs
+//
+// Browsable documentation of this API.
+func (c *Client) GetDocs(ctx context.Context, opts ...RequestOption) ([]byte, error) {
+	var out []byte
+	err := c.send(ctx, "GET", "/api/docs", nil, nil, nil, "", &out, opts)
+	return out, err
+}
+
+// GetFeedV1 calls GET /api/feed
+//
+// Posts of the users you follow, newest first.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetFeedV1(ctx context.Context, params *GetFeedV1Params, opts ...RequestOption) (*GetFeedV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out GetFeedV1Response
+	if err := c.send(ctx, "GET", "/api/feed", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthorFeedV1 calls GET /api/feeds/authors/{username}/{format}
+//
+// Latest posts of an author.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetAuthorFeedV1(ctx context.Context, username string, format string, params *GetAuthorFeedV1Params, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/feeds/authors/"+url.PathEscape(username)+"/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// GetTagFeedV1 calls GET /api/feeds/tags/{tag}/{format}
+//
+// Latest posts with a tag.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetTagFeedV1(ctx context.Context, tag string, format string, params *GetTagFeedV1Params, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/feeds/tags/"+url.PathEscape(tag)+"/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// GetSiteFeedV1 calls GET /api/feeds/{format}
+//
+// Latest posts of the site.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetSiteFeedV1(ctx context.Context, format string, params *GetSiteFeedV1Params, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/feeds/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// DeleteAccountV1 calls DELETE /api/me
+//
+// Delete the signed in user with everything they wrote and uploaded.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) DeleteAccountV1(ctx context.Context, body DeleteAccountRequest, opts ...RequestOption) (*DeleteAccountV1Response, error) {
+	var out DeleteAccountV1Response
+	if err := c.send(ctx, "DELETE", "/api/me", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMyAnalyticsV1 calls GET /api/me/analytics
+//
+// Daily views, readers, likes and comments of your posts.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetMyAnalyticsV1(ctx context.Context, params *GetMyAnalyticsV1Params, opts ...RequestOption) (*GetMyAnalyticsV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+	}
+	var out GetMyAnalyticsV1Response
+	if err := c.send(ctx, "GET", "/api/me/analytics", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveAvatarV1 calls DELETE /api/me/avatar
+//
+// Remove your avatar, falling back to your identicon.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) RemoveAvatarV1(ctx context.Context, opts ...RequestOption) (*RemoveAvatarV1Response, error) {
+	var out RemoveAvatarV1Response
+	if err := c.send(ctx, "DELETE", "/api/me/avatar", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetAvatarV1 calls PUT /api/me/avatar
+//
+// Upload your avatar.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) SetAvatarV1(ctx context.Context, filename string, file io.Reader, opts ...RequestOption) (*SetAvatarV1Response, error) {
+	var out SetAvatarV1Response
+	if err := c.upload(ctx, "PUT", "/api/me/avatar", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBookmarksV1 calls GET /api/me/bookmarks
+//
+// List your bookmarked blogs.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListBookmarksV1(ctx context.Context, opts ...RequestOption) (*ListBookmarksV1Response, error) {
+	var out ListBookmarksV1Response
+	if err := c.send(ctx, "GET", "/api/me/bookmarks", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMyMediaV1 calls GET /api/me/media
+//
+// List your image library, newest first.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListMyMediaV1(ctx context.Context, params *ListMyMediaV1Params, opts ...RequestOption) (*ListMyMediaV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListMyMediaV1Response
+	if err := c.send(ctx, "GET", "/api/me/media", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListReadingListsV1 calls GET /api/me/reading-lists
+//
+// List your reading lists.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListReadingListsV1(ctx context.Context, opts ...RequestOption) (*ListReadingListsV1Response, error) {
+	var out ListReadingListsV1Response
+	if err := c.send(ctx, "GET", "/api/me/reading-lists", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateReadingListV1 calls POST /api/me/reading-lists
+//
+// Create a reading list.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) CreateReadingListV1(ctx context.Context, body CreateReadingListRequest, opts ...RequestOption) (*CreateReadingListV1Response, error) {
+	var out CreateReadingListV1Response
+	if err := c.send(ctx, "POST", "/api/me/reading-lists", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteReadingListV1 calls DELETE /api/me/reading-lists/{listId}
+//
+// Delete a reading list.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) DeleteReadingListV1(ctx context.Context, listID int64, opts ...RequestOption) (*DeleteReadingListV1Response, error) {
+	var out DeleteReadingListV1Response
+	if err := c.send(ctx, "DELETE", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetReadingListV1 calls GET /api/me/reading-lists/{listId}
+//
+// Fetch one of your reading lists with its blogs in order.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetReadingListV1(ctx context.Context, listID int64, opts ...RequestOption) (*GetReadingListV1Response, error) {
+	var out GetReadingListV1Response
+	if err := c.send(ctx, "GET", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateReadingListV1 calls PUT /api/me/reading-lists/{listId}
+//
+// Rename a reading list or share it, fields left out keep their value.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) UpdateReadingListV1(ctx context.Context, listID int64, body UpdateReadingListRequest, opts ...RequestOption) (*UpdateReadingListV1Response, error) {
+	var out UpdateReadingListV1Response
+	if err := c.send(ctx, "PUT", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddReadingListItemV1 calls POST /api/me/reading-lists/{listId}/items
+//
+// Add a blog to the end of a reading list.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) AddReadingListItemV1(ctx context.Context, listID int64, body ReadingListItemRequest, opts ...RequestOption) (*AddReadingListItemV1Response, error) {
+	var out AddReadingListItemV1Response
+	if err := c.send(ctx, "POST", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/items", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveReadingListItemV1 calls DELETE /api/me/reading-lists/{listId}/items/{blogId}
+//
+// Remove a blog from a reading list.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) RemoveReadingListItemV1(ctx context.Context, listID int64, blogID int64, opts ...RequestOption) (*RemoveReadingListItemV1Response, error) {
+	var out RemoveReadingListItemV1Response
+	if err := c.send(ctx, "DELETE", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/items/"+strconv.FormatInt(blogID, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReorderReadingListV1 calls PUT /api/me/reading-lists/{listId}/order
+//
+// Put a reading list in a new order, listing every blog in it once.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ReorderReadingListV1(ctx context.Context, listID int64, body ReorderReadingListRequest, opts ...RequestOption) (*ReorderReadingListV1Response, error) {
+	var out ReorderReadingListV1Response
+	if err := c.send(ctx, "PUT", "/api/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/order", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadMediaV1 calls POST /api/media
+//
+// Upload an image to your library.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) UploadMediaV1(ctx context.Context, filename string, file io.Reader, opts ...RequestOption) (*UploadMediaV1Response, error) {
+	var out UploadMediaV1Response
+	if err := c.upload(ctx, "POST", "/api/media", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMediaV1 calls DELETE /api/media/{id}
+//
+// Delete an image of your library.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) DeleteMediaV1(ctx context.Context, id int64, opts ...RequestOption) (*DeleteMediaV1Response, error) {
+	var out DeleteMediaV1Response
+	if err := c.send(ctx, "DELETE", "/api/media/"+strconv.FormatInt(id, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPI calls GET /api/openapi.json
+//
+// This OpenAPI document.
+func (c *Client) GetOpenAPI(ctx context.Context, opts ...RequestOption) ([]byte, error) {
+	var out []byte
+	err := c.send(ctx, "GET", "/api/openapi.json", nil, nil, nil, "", &out, opts)
+	return out, err
+}
+
+// GetSharedReadingListV1 calls GET /api/reading-lists/shared/{token}
+//
+// Fetch a reading list shared by its owner.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetSharedReadingListV1(ctx context.Context, token string, opts ...RequestOption) (*GetSharedReadingListV1Response, error) {
+	var out GetSharedReadingListV1Response
+	if err := c.send(ctx, "GET", "/api/reading-lists/shared/"+url.PathEscape(token), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RequestResetCodeV1 calls POST /api/request-reset-code
+//
+// Mail a password reset code.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) RequestResetCodeV1(ctx context.Context, body ResetCodeRequest, opts ...RequestOption) (*RequestResetCodeV1Response, error) {
+	var out RequestResetCodeV1Response
+	if err := c.send(ctx, "POST", "/api/request-reset-code", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ResetPasswordV1 calls POST /api/reset-password
+//
+// Set a new password after verifying a reset code.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ResetPasswordV1(ctx context.Context, body ResetPasswordRequest, opts ...RequestOption) (*ResetPasswordV1Response, error) {
+	var out ResetPasswordV1Response
+	if err := c.send(ctx, "POST", "/api/reset-password", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SignInV1 calls POST /api/signin
+//
+// Sign in and get a session token.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) SignInV1(ctx context.Context, body SignInRequest, opts ...RequestOption) (*SignInV1Response, error) {
+	var out SignInV1Response
+	if err := c.send(ctx, "POST", "/api/signin", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SignUpV1 calls POST /api/signup
+//
+// Register and get a session token.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) SignUpV1(ctx context.Context, body SignUpRequest, opts ...RequestOption) (*SignUpV1Response, error) {
+	var out SignUpV1Response
+	if err := c.send(ctx, "POST", "/api/signup", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPopularBlogsV1 calls GET /api/top-popular-blogs
+//
+// List the hottest blogs, ranked by likes, comments and views decayed with age.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListPopularBlogsV1(ctx context.Context, params *ListPopularBlogsV1Params, opts ...RequestOption) (*ListPopularBlogsV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Window != "" {
+			query.Set("window", params.Window)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListPopularBlogsV1Response
+	if err := c.send(ctx, "GET", "/api/top-popular-blogs", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserProfileV1 calls GET /api/users/{username}
+//
+// Public profile of a user with a page of their posts, newest first.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetUserProfileV1(ctx context.Context, username string, params *GetUserProfileV1Params, opts ...RequestOption) (*GetUserProfileV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out GetUserProfileV1Response
+	if err := c.send(ctx, "GET", "/api/users/"+url.PathEscape(username), query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserAvatarV1 calls GET /api/users/{username}/avatar
+//
+// Avatar of a user, their identicon when they have not uploaded one.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) GetUserAvatarV1(ctx context.Context, username string, params *GetUserAvatarV1Params, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Size != 0 {
+			query.Set("size", strconv.FormatInt(params.Size, 10))
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/users/"+url.PathEscape(username)+"/avatar", query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// UnfollowUserV1 calls DELETE /api/users/{username}/follow
+//
+// Stop following a user.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) UnfollowUserV1(ctx context.Context, username string, opts ...RequestOption) (*UnfollowUserV1Response, error) {
+	var out UnfollowUserV1Response
+	if err := c.send(ctx, "DELETE", "/api/users/"+url.PathEscape(username)+"/follow", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FollowUserV1 calls POST /api/users/{username}/follow
+//
+// Follow a user.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) FollowUserV1(ctx context.Context, username string, opts ...RequestOption) (json.RawMessage, error) {
+	var out json.RawMessage
+	err := c.send(ctx, "POST", "/api/users/"+url.PathEscape(username)+"/follow", nil, nil, nil, "", &out, opts)
+	return out, err
+}
+
+// ListFollowersV1 calls GET /api/users/{username}/followers
+//
+// List the followers of a user.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListFollowersV1(ctx context.Context, username string, params *ListFollowersV1Params, opts ...RequestOption) (*ListFollowersV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListFollowersV1Response
+	if err := c.send(ctx, "GET", "/api/users/"+url.PathEscape(username)+"/followers", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFollowingV1 calls GET /api/users/{username}/following
+//
+// List the users a user follows.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) ListFollowingV1(ctx context.Context, username string, params *ListFollowingV1Params, opts ...RequestOption) (*ListFollowingV1Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListFollowingV1Response
+	if err := c.send(ctx, "GET", "/api/users/"+url.PathEscape(username)+"/following", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAllBlogsWithMeta calls GET /api/v2/all-blogs-with-meta
+//
+// List every blog with its counters and latest comments.
+func (c *Client) ListAllBlogsWithMeta(ctx context.Context, params *ListAllBlogsWithMetaParams, opts ...RequestOption) (*ListAllBlogsWithMetaResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Search != "" {
+			query.Set("search", params.Search)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListAllBlogsWithMetaResponse
+	if err := c.send(ctx, "GET", "/api/v2/all-blogs-with-meta", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlogs calls GET /api/v2/blogs
+//
+// List the signed in user's blogs.
+func (c *Client) ListBlogs(ctx context.Context, params *ListBlogsParams, opts ...RequestOption) (*ListBlogsResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Title != "" {
+			query.Set("title", params.Title)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListBlogsResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateBlog calls POST /api/v2/blogs
+//
+// Publish a blog.
+func (c *Client) CreateBlog(ctx context.Context, body CreateBlogRequest, opts ...RequestOption) (*CreateBlogResponse, error) {
+	var out CreateBlogResponse
+	if err := c.send(ctx, "POST", "/api/v2/blogs", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBlogsWithMeta calls GET /api/v2/blogs-with-meta
+//
+// List the signed in user's blogs with their counters and latest comments.
+func (c *Client) ListBlogsWithMeta(ctx context.Context, params *ListBlogsWithMetaParams, opts ...RequestOption) (*ListBlogsWithMetaResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Search != "" {
+			query.Set("search", params.Search)
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListBlogsWithMetaResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs-with-meta", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteBlog calls DELETE /api/v2/blogs/{id}
+//
+// Delete a blog.
+func (c *Client) DeleteBlog(ctx context.Context, id int64, opts ...RequestOption) (*DeleteBlogResponse, error) {
+	var out DeleteBlogResponse
+	if err := c.send(ctx, "DELETE", "/api/v2/blogs/"+strconv.FormatInt(id, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBlog calls GET /api/v2/blogs/{id}
+//
+// Fetch a blog.
+func (c *Client) GetBlog(ctx context.Context, id int64, params *GetBlogParams, opts ...RequestOption) (*GetBlogResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out GetBlogResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs/"+strconv.FormatInt(id, 10), query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PatchBlog calls PATCH /api/v2/blogs/{id}
+//
+// Change a blog with a JSON Merge Patch.
+func (c *Client) PatchBlog(ctx context.Context, id int64, params *PatchBlogParams, body UpdateBlogRequest, opts ...RequestOption) (*PatchBlogResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out PatchBlogResponse
+	if err := c.send(ctx, "PATCH", "/api/v2/blogs/"+strconv.FormatInt(id, 10), query, header, body, "application/merge-patch+json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateBlog calls PUT /api/v2/blogs/{id}
+//
+// Change a blog, fields left out keep their value.
+func (c *Client) UpdateBlog(ctx context.Context, id int64, params *UpdateBlogParams, body UpdateBlogRequest, opts ...RequestOption) (*UpdateBlogResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out UpdateBlogResponse
+	if err := c.send(ctx, "PUT", "/api/v2/blogs/"+strconv.FormatInt(id, 10), query, header, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveBookmark calls DELETE /api/v2/blogs/{id}/bookmark
+//
+// Remove a bookmark.
+func (c *Client) RemoveBookmark(ctx context.Context, id int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/bookmark", nil, nil, nil, "", nil, opts)
+}
+
+// BookmarkBlog calls POST /api/v2/blogs/{id}/bookmark
+//
+// Bookmark a blog.
+func (c *Client) BookmarkBlog(ctx context.Context, id int64, opts ...RequestOption) (*BookmarkBlogResponse, error) {
+	var out BookmarkBlogResponse
+	if err := c.send(ctx, "POST", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/bookmark", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListComments calls GET /api/v2/blogs/{id}/comments
+//
+// List the comments of a blog.
+func (c *Client) ListComments(ctx context.Context, id int64, params *ListCommentsParams, opts ...RequestOption) (*ListCommentsResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListCommentsResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/comments", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddComment calls POST /api/v2/blogs/{id}/comments
+//
+// Comment on a blog.
+func (c *Client) AddComment(ctx context.Context, id int64, body CommentRequest, opts ...RequestOption) (*AddCommentResponse, error) {
+	var out AddCommentResponse
+	if err := c.send(ctx, "POST", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/comments", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteComment calls DELETE /api/v2/blogs/{id}/comments/{commentId}
+//
+// Delete one of your comments.
+func (c *Client) DeleteComment(ctx context.Context, id int64, commentID int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/comments/"+strconv.FormatInt(commentID, 10), nil, nil, nil, "", nil, opts)
+}
+
+// EditComment calls PUT /api/v2/blogs/{id}/comments/{commentId}
+//
+// Change one of your comments.
+func (c *Client) EditComment(ctx context.Context, id int64, commentID int64, params *EditCommentParams, body CommentRequest, opts ...RequestOption) (*EditCommentResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfMatch != "" {
+			header.Set("If-Match", params.IfMatch)
+		}
+	}
+	var out EditCommentResponse
+	if err := c.send(ctx, "PUT", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/comments/"+strconv.FormatInt(commentID, 10), query, header, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveBlogCover calls DELETE /api/v2/blogs/{id}/cover
+//
+// Remove the cover image of one of your blogs.
+func (c *Client) RemoveBlogCover(ctx context.Context, id int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/cover", nil, nil, nil, "", nil, opts)
+}
+
+// SetBlogCover calls PUT /api/v2/blogs/{id}/cover
+//
+// Upload the cover image of one of your blogs.
+func (c *Client) SetBlogCover(ctx context.Context, id int64, filename string, file io.Reader, opts ...RequestOption) (*SetBlogCoverResponse, error) {
+	var out SetBlogCoverResponse
+	if err := c.upload(ctx, "PUT", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/cover", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CountLikes calls GET /api/v2/blogs/{id}/likes
+//
+// Count the likes of a blog.
+func (c *Client) CountLikes(ctx context.Context, id int64, opts ...RequestOption) (*CountLikesResponse, error) {
+	var out CountLikesResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/likes", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ToggleLike calls POST /api/v2/blogs/{id}/likes
+//
+// Like a blog, or take the like back when it is already liked.
+func (c *Client) ToggleLike(ctx context.Context, id int64, opts ...RequestOption) (*ToggleLikeResponse, error) {
+	var out ToggleLikeResponse
+	if err := c.send(ctx, "POST", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/likes", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBlogMeta calls GET /api/v2/blogs/{id}/meta
+//
+// Search engine and social card metadata of a blog.
+func (c *Client) GetBlogMeta(ctx context.Context, id int64, opts ...RequestOption) (*GetBlogMetaResponse, error) {
+	var out GetBlogMetaResponse
+	if err := c.send(ctx, "GET", "/api/v2/blogs/"+strconv.FormatInt(id, 10)+"/meta", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFeed calls GET /api/v2/feed
+//
+// Posts of the users you follow, newest first.
+func (c *Client) GetFeed(ctx context.Context, params *GetFeedParams, opts ...RequestOption) (*GetFeedResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out GetFeedResponse
+	if err := c.send(ctx, "GET", "/api/v2/feed", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthorFeed calls GET /api/v2/feeds/authors/{username}/{format}
+//
+// Latest posts of an author.
+func (c *Client) GetAuthorFeed(ctx context.Context, username string, format string, params *GetAuthorFeedParams, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/v2/feeds/authors/"+url.PathEscape(username)+"/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// GetTagFeed calls GET /api/v2/feeds/tags/{tag}/{format}
+//
+// Latest posts with a tag.
+func (c *Client) GetTagFeed(ctx context.Context, tag string, format string, params *GetTagFeedParams, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/v2/feeds/tags/"+url.PathEscape(tag)+"/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// GetSiteFeed calls GET /api/v2/feeds/{format}
+//
+// Latest posts of the site.
+func (c *Client) GetSiteFeed(ctx context.Context, format string, params *GetSiteFeedParams, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/v2/feeds/"+url.PathEscape(format), query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// DeleteAccount calls DELETE /api/v2/me
+//
+// Delete the signed in user with everything they wrote and uploaded.
+func (c *Client) DeleteAccount(ctx context.Context, body DeleteAccountRequest, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/me", nil, nil, body, "application/json", nil, opts)
+}
+
+// GetMyAnalytics calls GET /api/v2/me/analytics
+//
+// Daily views, readers, likes and comments of your posts.
+func (c *Client) GetMyAnalytics(ctx context.Context, params *GetMyAnalyticsParams, opts ...RequestOption) (*GetMyAnalyticsResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.From != "" {
+			query.Set("from", params.From)
+		}
+		if params.To != "" {
+			query.Set("to", params.To)
+		}
+	}
+	var out GetMyAnalyticsResponse
+	if err := c.send(ctx, "GET", "/api/v2/me/analytics", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveAvatar calls DELETE /api/v2/me/avatar
+//
+// Remove your avatar, falling back to your identicon.
+func (c *Client) RemoveAvatar(ctx context.Context, opts ...RequestOption) (*RemoveAvatarResponse, error) {
+	var out RemoveAvatarResponse
+	if err := c.send(ctx, "DELETE", "/api/v2/me/avatar", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetAvatar calls PUT /api/v2/me/avatar
+//
+// Upload your avatar.
+func (c *Client) SetAvatar(ctx context.Context, filename string, file io.Reader, opts ...RequestOption) (*SetAvatarResponse, error) {
+	var out SetAvatarResponse
+	if err := c.upload(ctx, "PUT", "/api/v2/me/avatar", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListBookmarks calls GET /api/v2/me/bookmarks
+//
+// List your bookmarked blogs.
+func (c *Client) ListBookmarks(ctx context.Context, opts ...RequestOption) (*ListBookmarksResponse, error) {
+	var out ListBookmarksResponse
+	if err := c.send(ctx, "GET", "/api/v2/me/bookmarks", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMyMedia calls GET /api/v2/me/media
+//
+// List your image library, newest first.
+func (c *Client) ListMyMedia(ctx context.Context, params *ListMyMediaParams, opts ...RequestOption) (*ListMyMediaResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListMyMediaResponse
+	if err := c.send(ctx, "GET", "/api/v2/me/media", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListReadingLists calls GET /api/v2/me/reading-lists
+//
+// List your reading lists.
+func (c *Client) ListReadingLists(ctx context.Context, opts ...RequestOption) (*ListReadingListsResponse, error) {
+	var out ListReadingListsResponse
+	if err := c.send(ctx, "GET", "/api/v2/me/reading-lists", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateReadingList calls POST /api/v2/me/reading-lists
+//
+// Create a reading list.
+func (c *Client) CreateReadingList(ctx context.Context, body CreateReadingListRequest, opts ...RequestOption) (*CreateReadingListResponse, error) {
+	var out CreateReadingListResponse
+	if err := c.send(ctx, "POST", "/api/v2/me/reading-lists", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteReadingList calls DELETE /api/v2/me/reading-lists/{listId}
+//
+// Delete a reading list.
+func (c *Client) DeleteReadingList(ctx context.Context, listID int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, nil, "", nil, opts)
+}
+
+// GetReadingList calls GET /api/v2/me/reading-lists/{listId}
+//
+// Fetch one of your reading lists with its blogs in order.
+func (c *Client) GetReadingList(ctx context.Context, listID int64, opts ...RequestOption) (*GetReadingListResponse, error) {
+	var out GetReadingListResponse
+	if err := c.send(ctx, "GET", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateReadingList calls PUT /api/v2/me/reading-lists/{listId}
+//
+// Rename a reading list or share it, fields left out keep their value.
+func (c *Client) UpdateReadingList(ctx context.Context, listID int64, body UpdateReadingListRequest, opts ...RequestOption) (*UpdateReadingListResponse, error) {
+	var out UpdateReadingListResponse
+	if err := c.send(ctx, "PUT", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10), nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddReadingListItem calls POST /api/v2/me/reading-lists/{listId}/items
+//
+// Add a blog to the end of a reading list.
+func (c *Client) AddReadingListItem(ctx context.Context, listID int64, body ReadingListItemRequest, opts ...RequestOption) (*AddReadingListItemResponse, error) {
+	var out AddReadingListItemResponse
+	if err := c.send(ctx, "POST", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/items", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveReadingListItem calls DELETE /api/v2/me/reading-lists/{listId}/items/{blogId}
+//
+// Remove a blog from a reading list.
+func (c *Client) RemoveReadingListItem(ctx context.Context, listID int64, blogID int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/items/"+strconv.FormatInt(blogID, 10), nil, nil, nil, "", nil, opts)
+}
+
+// ReorderReadingList calls PUT /api/v2/me/reading-lists/{listId}/order
+//
+// Put a reading list in a new order, listing every blog in it once.
+func (c *Client) ReorderReadingList(ctx context.Context, listID int64, body ReorderReadingListRequest, opts ...RequestOption) (*ReorderReadingListResponse, error) {
+	var out ReorderReadingListResponse
+	if err := c.send(ctx, "PUT", "/api/v2/me/reading-lists/"+strconv.FormatInt(listID, 10)+"/order", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadMedia calls POST /api/v2/media
+//
+// Upload an image to your library.
+func (c *Client) UploadMedia(ctx context.Context, filename string, file io.Reader, opts ...RequestOption) (*UploadMediaResponse, error) {
+	var out UploadMediaResponse
+	if err := c.upload(ctx, "POST", "/api/v2/media", nil, nil, "file", filename, file, &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMedia calls DELETE /api/v2/media/{id}
+//
+// Delete an image of your library.
+func (c *Client) DeleteMedia(ctx context.Context, id int64, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/media/"+strconv.FormatInt(id, 10), nil, nil, nil, "", nil, opts)
+}
+
+// GetSharedReadingList calls GET /api/v2/reading-lists/shared/{token}
+//
+// Fetch a reading list shared by its owner.
+func (c *Client) GetSharedReadingList(ctx context.Context, token string, opts ...RequestOption) (*GetSharedReadingListResponse, error) {
+	var out GetSharedReadingListResponse
+	if err := c.send(ctx, "GET", "/api/v2/reading-lists/shared/"+url.PathEscape(token), nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RequestResetCode calls POST /api/v2/request-reset-code
+//
+// Mail a password reset code.
+func (c *Client) RequestResetCode(ctx context.Context, body ResetCodeRequest, opts ...RequestOption) error {
+	return c.send(ctx, "POST", "/api/v2/request-reset-code", nil, nil, body, "application/json", nil, opts)
+}
+
+// ResetPassword calls POST /api/v2/reset-password
+//
+// Set a new password after verifying a reset code.
+func (c *Client) ResetPassword(ctx context.Context, body ResetPasswordRequest, opts ...RequestOption) error {
+	return c.send(ctx, "POST", "/api/v2/reset-password", nil, nil, body, "application/json", nil, opts)
+}
+
+// SignIn calls POST /api/v2/signin
+//
+// Sign in and get a session token.
+func (c *Client) SignIn(ctx context.Context, body SignInRequest, opts ...RequestOption) (*SignInResponse, error) {
+	var out SignInResponse
+	if err := c.send(ctx, "POST", "/api/v2/signin", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SignUp calls POST /api/v2/signup
+//
+// Register and get a session token.
+func (c *Client) SignUp(ctx context.Context, body SignUpRequest, opts ...RequestOption) (*SignUpResponse, error) {
+	var out SignUpResponse
+	if err := c.send(ctx, "POST", "/api/v2/signup", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListPopularBlogs calls GET /api/v2/top-popular-blogs
+//
+// List the hottest blogs, ranked by likes, comments and views decayed with age.
+func (c *Client) ListPopularBlogs(ctx context.Context, params *ListPopularBlogsParams, opts ...RequestOption) (*ListPopularBlogsResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Window != "" {
+			query.Set("window", params.Window)
+		}
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.IfNoneMatch != "" {
+			header.Set("If-None-Match", params.IfNoneMatch)
+		}
+	}
+	var out ListPopularBlogsResponse
+	if err := c.send(ctx, "GET", "/api/v2/top-popular-blogs", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserProfile calls GET /api/v2/users/{username}
+//
+// Public profile of a user with a page of their posts, newest first.
+func (c *Client) GetUserProfile(ctx context.Context, username string, params *GetUserProfileParams, opts ...RequestOption) (*GetUserProfileResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out GetUserProfileResponse
+	if err := c.send(ctx, "GET", "/api/v2/users/"+url.PathEscape(username), query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUserAvatar calls GET /api/v2/users/{username}/avatar
+//
+// Avatar of a user, their identicon when they have not uploaded one.
+func (c *Client) GetUserAvatar(ctx context.Context, username string, params *GetUserAvatarParams, opts ...RequestOption) ([]byte, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Size != 0 {
+			query.Set("size", strconv.FormatInt(params.Size, 10))
+		}
+	}
+	var out []byte
+	err := c.send(ctx, "GET", "/api/v2/users/"+url.PathEscape(username)+"/avatar", query, header, nil, "", &out, opts)
+	return out, err
+}
+
+// UnfollowUser calls DELETE /api/v2/users/{username}/follow
+//
+// Stop following a user.
+func (c *Client) UnfollowUser(ctx context.Context, username string, opts ...RequestOption) error {
+	return c.send(ctx, "DELETE", "/api/v2/users/"+url.PathEscape(username)+"/follow", nil, nil, nil, "", nil, opts)
+}
+
+// FollowUser calls POST /api/v2/users/{username}/follow
+//
+// Follow a user.
+func (c *Client) FollowUser(ctx context.Context, username string, opts ...RequestOption) (*FollowUserResponse, error) {
+	var out FollowUserResponse
+	if err := c.send(ctx, "POST", "/api/v2/users/"+url.PathEscape(username)+"/follow", nil, nil, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFollowers calls GET /api/v2/users/{username}/followers
+//
+// List the followers of a user.
+func (c *Client) ListFollowers(ctx context.Context, username string, params *ListFollowersParams, opts ...RequestOption) (*ListFollowersResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListFollowersResponse
+	if err := c.send(ctx, "GET", "/api/v2/users/"+url.PathEscape(username)+"/followers", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListFollowing calls GET /api/v2/users/{username}/following
+//
+// List the users a user follows.
+func (c *Client) ListFollowing(ctx context.Context, username string, params *ListFollowingParams, opts ...RequestOption) (*ListFollowingResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != 0 {
+			query.Set("limit", strconv.FormatInt(params.Limit, 10))
+		}
+		if params.Cursor != 0 {
+			query.Set("cursor", strconv.FormatInt(params.Cursor, 10))
+		}
+	}
+	var out ListFollowingResponse
+	if err := c.send(ctx, "GET", "/api/v2/users/"+url.PathEscape(username)+"/following", query, header, nil, "", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// VerifyResetCode calls POST /api/v2/verify-reset-code
+//
+// Check a password reset code.
+func (c *Client) VerifyResetCode(ctx context.Context, body VerifyCodeRequest, opts ...RequestOption) error {
+	return c.send(ctx, "POST", "/api/v2/verify-reset-code", nil, nil, body, "application/json", nil, opts)
+}
+
+// VerifyResetCodeV1 calls POST /api/verify-reset-code
+//
+// Check a password reset code.
+//
+// Deprecated: the operation is deprecated.
+func (c *Client) VerifyResetCodeV1(ctx context.Context, body VerifyCodeRequest, opts ...RequestOption) (*VerifyResetCodeV1Response, error) {
+	var out VerifyResetCodeV1Response
+	if err := c.send(ctx, "POST", "/api/verify-reset-code", nil, nil, body, "application/json", &out, opts); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListAllBlogsWithMetaV1Params are the parameters of ListAllBlogsWithMetaV1, zero values are left out
+type ListAllBlogsWithMetaV1Params struct {
+	// Search is the query search, words to look for in titles and posts
+	Search string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListAllBlogsWithMetaV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	StatusText string         `json:"statusText"`
+}
+
+// ListBlogsV1Params are the parameters of ListBlogsV1, zero values are left out
+type ListBlogsV1Params struct {
+	// Title is the query title, only blogs with this title
+	Title string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListBlogsV1Response struct {
+	Blogs      []Blog `json:"blogs"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type CreateBlogV1Response struct {
+	Blog       Blog   `json:"blog"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+// ListBlogsWithMetaV1Params are the parameters of ListBlogsWithMetaV1, zero values are left out
+type ListBlogsWithMetaV1Params struct {
+	// Search is the query search, words to look for in titles and posts
+	Search string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListBlogsWithMetaV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	StatusText string         `json:"statusText"`
+}
+
+type DeleteBlogV1Response struct {
+	Blog       Blog   `json:"blog"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+// GetBlogV1Params are the parameters of GetBlogV1, zero values are left out
+type GetBlogV1Params struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type GetBlogV1Response struct {
+	Blog       Blog   `json:"blog"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+// PatchBlogV1Params are the parameters of PatchBlogV1, zero values are left out
+type PatchBlogV1Params struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type PatchBlogV1Response struct {
+	Blog       Blog   `json:"blog"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+// UpdateBlogV1Params are the parameters of UpdateBlogV1, zero values are left out
+type UpdateBlogV1Params struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type UpdateBlogV1Response struct {
+	Blog       Blog   `json:"blog"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type RemoveBookmarkV1Response struct {
+	Msg string `json:"msg"`
+}
+
+// ListCommentsV1Params are the parameters of ListCommentsV1, zero values are left out
+type ListCommentsV1Params struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type DeleteCommentV1Response struct {
+	Msg string `json:"msg"`
+}
+
+// EditCommentV1Params are the parameters of EditCommentV1, zero values are left out
+type EditCommentV1Params struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type EditCommentV1Response struct {
+	Comment Comment `json:"comment"`
+	Msg     string  `json:"msg"`
+}
+
+type RemoveBlogCoverV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type SetBlogCoverV1Response struct {
+	CoverImage *CoverImage `json:"cover_image"`
+	Msg        string      `json:"msg"`
+	StatusText string      `json:"statusText"`
+}
+
+type CountLikesV1Response struct {
+	Likes int64 `json:"likes"`
+}
+
+// GetFeedV1Params are the parameters of GetFeedV1, zero values are left out
+type GetFeedV1Params struct {
+	// Limit is the query limit, page size, 20 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type GetFeedV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	StatusText string         `json:"statusText"`
+}
+
+// GetAuthorFeedV1Params are the parameters of GetAuthorFeedV1, zero values are left out
+type GetAuthorFeedV1Params struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+// GetTagFeedV1Params are the parameters of GetTagFeedV1, zero values are left out
+type GetTagFeedV1Params struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+// GetSiteFeedV1Params are the parameters of GetSiteFeedV1, zero values are left out
+type GetSiteFeedV1Params struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type DeleteAccountV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+// GetMyAnalyticsV1Params are the parameters of GetMyAnalyticsV1, zero values are left out
+type GetMyAnalyticsV1Params struct {
+	// From is the query from, first day, 29 days before to by default
+	From string
+	// To is the query to, last day, today by default
+	To string
+}
+
+type GetMyAnalyticsV1Response struct {
+	From       string          `json:"from"`
+	Msg        string          `json:"msg"`
+	Posts      []PostAnalytics `json:"posts"`
+	StatusText string          `json:"statusText"`
+	To         string          `json:"to"`
+	Totals     AnalyticsTotals `json:"totals"`
+}
+
+type RemoveAvatarV1Response struct {
+	AvatarURL  string `json:"avatar_url"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type SetAvatarV1Response struct {
+	AvatarURL  string `json:"avatar_url"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type ListBookmarksV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	StatusText string         `json:"statusText"`
+}
+
+// ListMyMediaV1Params are the parameters of ListMyMediaV1, zero values are left out
+type ListMyMediaV1Params struct {
+	// Limit is the query limit, page size, 30 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListMyMediaV1Response struct {
+	Media      []Media `json:"media"`
+	Msg        string  `json:"msg"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	StatusText string  `json:"statusText"`
+}
+
+type ListReadingListsV1Response struct {
+	Msg          string        `json:"msg"`
+	ReadingLists []ReadingList `json:"reading_lists"`
+	StatusText   string        `json:"statusText"`
+}
+
+type CreateReadingListV1Response struct {
+	Msg         string      `json:"msg"`
+	ReadingList ReadingList `json:"reading_list"`
+	ShareURL    string      `json:"share_url"`
+	StatusText  string      `json:"statusText"`
+}
+
+type DeleteReadingListV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type GetReadingListV1Response struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	Msg         string         `json:"msg"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+	StatusText  string         `json:"statusText"`
+}
+
+type UpdateReadingListV1Response struct {
+	Msg         string      `json:"msg"`
+	ReadingList ReadingList `json:"reading_list"`
+	ShareURL    string      `json:"share_url"`
+	StatusText  string      `json:"statusText"`
+}
+
+type AddReadingListItemV1Response struct {
+	Item       ReadingListItem `json:"item"`
+	Msg        string          `json:"msg"`
+	StatusText string          `json:"statusText"`
+}
+
+type RemoveReadingListItemV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type ReorderReadingListV1Response struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	Msg         string         `json:"msg"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+	StatusText  string         `json:"statusText"`
+}
+
+type UploadMediaV1Response struct {
+	Media      Media  `json:"media"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type DeleteMediaV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+}
+
+type GetSharedReadingListV1Response struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	Msg         string         `json:"msg"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+	StatusText  string         `json:"statusText"`
+}
+
+type RequestResetCodeV1Response struct {
+	Msg string `json:"msg"`
+}
+
+type ResetPasswordV1Response struct {
+	Msg string `json:"msg"`
+}
+
+type SignInV1Response struct {
+	AvatarURL  string `json:"avatar_url"`
+	Email      string `json:"email"`
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+	Token      string `json:"token"`
+	Username   string `json:"username"`
+}
+
+type SignUpV1Response struct {
+	Msg        string `json:"msg"`
+	StatusText string `json:"statusText"`
+	Token      string `json:"token"`
+}
+
+// ListPopularBlogsV1Params are the parameters of ListPopularBlogsV1, zero values are left out
+type ListPopularBlogsV1Params struct {
+	// Window is the query window, period ranked, week by default
+	Window string
+	// Limit is the query limit, page size, 5 by default
+	Limit int64
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListPopularBlogsV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	StatusText string         `json:"statusText"`
+}
+
+// GetUserProfileV1Params are the parameters of GetUserProfileV1, zero values are left out
+type GetUserProfileV1Params struct {
+	// Limit is the query limit, page size, 10 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type GetUserProfileV1Response struct {
+	Blogs      []BlogWithMeta `json:"blogs"`
+	Msg        string         `json:"msg"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Profile    Profile        `json:"profile"`
+	StatusText string         `json:"statusText"`
+}
+
+// GetUserAvatarV1Params are the parameters of GetUserAvatarV1, zero values are left out
+type GetUserAvatarV1Params struct {
+	// Size is the query size, identicon size in pixels, 256 by default
+	Size int64
+}
+
+type UnfollowUserV1Response struct {
+	Msg string `json:"msg"`
+}
+
+// ListFollowersV1Params are the parameters of ListFollowersV1, zero values are left out
+type ListFollowersV1Params struct {
+	// Limit is the query limit, page size, 50 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListFollowersV1Response struct {
+	Count      int64        `json:"count"`
+	Msg        string       `json:"msg"`
+	NextCursor int64        `json:"next_cursor,omitempty"`
+	StatusText string       `json:"statusText"`
+	Users      []PublicUser `json:"users"`
+}
+
+// ListFollowingV1Params are the parameters of ListFollowingV1, zero values are left out
+type ListFollowingV1Params struct {
+	// Limit is the query limit, page size, 50 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListFollowingV1Response struct {
+	Count      int64        `json:"count"`
+	Msg        string       `json:"msg"`
+	NextCursor int64        `json:"next_cursor,omitempty"`
+	StatusText string       `json:"statusText"`
+	Users      []PublicUser `json:"users"`
+}
+
+// ListAllBlogsWithMetaParams are the parameters of ListAllBlogsWithMeta, zero values are left out
+type ListAllBlogsWithMetaParams struct {
+	// Search is the query search, words to look for in titles and posts
+	Search string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListAllBlogsWithMetaResponse struct {
+	Data []BlogWithMeta `json:"data"`
+}
+
+// ListBlogsParams are the parameters of ListBlogs, zero values are left out
+type ListBlogsParams struct {
+	// Title is the query title, only blogs with this title
+	Title string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListBlogsResponse struct {
+	Data []BlogV2 `json:"data"`
+}
+
+type CreateBlogResponse struct {
+	Data BlogV2 `json:"data"`
+}
+
+// ListBlogsWithMetaParams are the parameters of ListBlogsWithMeta, zero values are left out
+type ListBlogsWithMetaParams struct {
+	// Search is the query search, words to look for in titles and posts
+	Search string
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListBlogsWithMetaResponse struct {
+	Data []BlogWithMeta `json:"data"`
+}
+
+type DeleteBlogResponse struct {
+	Data BlogV2 `json:"data"`
+}
+
+// GetBlogParams are the parameters of GetBlog, zero values are left out
+type GetBlogParams struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type GetBlogResponse struct {
+	Data BlogV2 `json:"data"`
+}
+
+// PatchBlogParams are the parameters of PatchBlog, zero values are left out
+type PatchBlogParams struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type PatchBlogResponse struct {
+	Data BlogV2 `json:"data"`
+}
+
+// UpdateBlogParams are the parameters of UpdateBlog, zero values are left out
+type UpdateBlogParams struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type UpdateBlogResponse struct {
+	Data BlogV2 `json:"data"`
+}
+
+type BookmarkBlogResponse struct {
+	Data Bookmark `json:"data"`
+}
+
+// ListCommentsParams are the parameters of ListComments, zero values are left out
+type ListCommentsParams struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListCommentsResponse struct {
+	Data []Comment `json:"data"`
+}
+
+type AddCommentResponse struct {
+	Data Comment `json:"data"`
+}
+
+// EditCommentParams are the parameters of EditComment, zero values are left out
+type EditCommentParams struct {
+	// IfMatch is the header If-Match, ETag or "v{version}" the change was made on, refused with 412 when stale
+	IfMatch string
+}
+
+type EditCommentResponse struct {
+	Data Comment `json:"data"`
+}
+
+type SetBlogCoverResponse struct {
+	Data *CoverImage `json:"data"`
+}
+
+type CountLikesResponseData struct {
+	Likes int64 `json:"likes"`
+}
+
+type CountLikesResponse struct {
+	Data CountLikesResponseData `json:"data"`
+}
+
+type ToggleLikeResponse struct {
+	Data Like `json:"data"`
+}
+
+type GetBlogMetaResponse struct {
+	Data BlogSEO `json:"data"`
+}
+
+// GetFeedParams are the parameters of GetFeed, zero values are left out
+type GetFeedParams struct {
+	// Limit is the query limit, page size, 20 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type GetFeedResponse struct {
+	Data       []BlogWithMeta `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// GetAuthorFeedParams are the parameters of GetAuthorFeed, zero values are left out
+type GetAuthorFeedParams struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+// GetTagFeedParams are the parameters of GetTagFeed, zero values are left out
+type GetTagFeedParams struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+// GetSiteFeedParams are the parameters of GetSiteFeed, zero values are left out
+type GetSiteFeedParams struct {
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+// GetMyAnalyticsParams are the parameters of GetMyAnalytics, zero values are left out
+type GetMyAnalyticsParams struct {
+	// From is the query from, first day, 29 days before to by default
+	From string
+	// To is the query to, last day, today by default
+	To string
+}
+
+type GetMyAnalyticsResponseData struct {
+	From   string          `json:"from"`
+	Posts  []PostAnalytics `json:"posts"`
+	To     string          `json:"to"`
+	Totals AnalyticsTotals `json:"totals"`
+}
+
+type GetMyAnalyticsResponse struct {
+	Data GetMyAnalyticsResponseData `json:"data"`
+}
+
+type RemoveAvatarResponseData struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+type RemoveAvatarResponse struct {
+	Data RemoveAvatarResponseData `json:"data"`
+}
+
+type SetAvatarResponseData struct {
+	AvatarURL string `json:"avatar_url"`
+}
+
+type SetAvatarResponse struct {
+	Data SetAvatarResponseData `json:"data"`
+}
+
+type ListBookmarksResponse struct {
+	Data []BlogWithMeta `json:"data"`
+}
+
+// ListMyMediaParams are the parameters of ListMyMedia, zero values are left out
+type ListMyMediaParams struct {
+	// Limit is the query limit, page size, 30 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListMyMediaResponse struct {
+	Data       []Media `json:"data"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+type ListReadingListsResponse struct {
+	Data []ReadingList `json:"data"`
+}
+
+type CreateReadingListResponseData struct {
+	ReadingList ReadingList `json:"reading_list"`
+	ShareURL    string      `json:"share_url"`
+}
+
+type CreateReadingListResponse struct {
+	Data CreateReadingListResponseData `json:"data"`
+}
+
+type GetReadingListResponseData struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+}
+
+type GetReadingListResponse struct {
+	Data GetReadingListResponseData `json:"data"`
+}
+
+type UpdateReadingListResponseData struct {
+	ReadingList ReadingList `json:"reading_list"`
+	ShareURL    string      `json:"share_url"`
+}
+
+type UpdateReadingListResponse struct {
+	Data UpdateReadingListResponseData `json:"data"`
+}
+
+type AddReadingListItemResponse struct {
+	Data ReadingListItem `json:"data"`
+}
+
+type ReorderReadingListResponseData struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+}
+
+type ReorderReadingListResponse struct {
+	Data ReorderReadingListResponseData `json:"data"`
+}
+
+type UploadMediaResponse struct {
+	Data Media `json:"data"`
+}
+
+type GetSharedReadingListResponseData struct {
+	Blogs       []BlogWithMeta `json:"blogs"`
+	ReadingList ReadingList    `json:"reading_list"`
+	ShareURL    string         `json:"share_url"`
+}
+
+type GetSharedReadingListResponse struct {
+	Data GetSharedReadingListResponseData `json:"data"`
+}
+
+type SignInResponseData struct {
+	AvatarURL string `json:"avatar_url"`
+	Email     string `json:"email"`
+	Token     string `json:"token"`
+	Username  string `json:"username"`
+}
+
+type SignInResponse struct {
+	Data SignInResponseData `json:"data"`
+}
+
+type SignUpResponseData struct {
+	Token string `json:"token"`
+}
+
+type SignUpResponse struct {
+	Data SignUpResponseData `json:"data"`
+}
+
+// ListPopularBlogsParams are the parameters of ListPopularBlogs, zero values are left out
+type ListPopularBlogsParams struct {
+	// Window is the query window, period ranked, week by default
+	Window string
+	// Limit is the query limit, page size, 5 by default
+	Limit int64
+	// IfNoneMatch is the header If-None-Match, ETag of a copy held, answered with 304 while it is current
+	IfNoneMatch string
+}
+
+type ListPopularBlogsResponse struct {
+	Data []BlogWithMeta `json:"data"`
+}
+
+// GetUserProfileParams are the parameters of GetUserProfile, zero values are left out
+type GetUserProfileParams struct {
+	// Limit is the query limit, page size, 10 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type GetUserProfileResponseData struct {
+	Blogs   []BlogWithMeta `json:"blogs"`
+	Profile Profile        `json:"profile"`
+}
+
+type GetUserProfileResponse struct {
+	Data       GetUserProfileResponseData `json:"data"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+// GetUserAvatarParams are the parameters of GetUserAvatar, zero values are left out
+type GetUserAvatarParams struct {
+	// Size is the query size, identicon size in pixels, 256 by default
+	Size int64
+}
+
+type FollowUserResponse struct {
+	Data Follow `json:"data"`
+}
+
+// ListFollowersParams are the parameters of ListFollowers, zero values are left out
+type ListFollowersParams struct {
+	// Limit is the query limit, page size, 50 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListFollowersResponseData struct {
+	Count int64        `json:"count"`
+	Users []PublicUser `json:"users"`
+}
+
+type ListFollowersResponse struct {
+	Data       ListFollowersResponseData `json:"data"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+// ListFollowingParams are the parameters of ListFollowing, zero values are left out
+type ListFollowingParams struct {
+	// Limit is the query limit, page size, 50 by default
+	Limit int64
+	// Cursor is the query cursor, next_cursor of the previous page
+	Cursor int64
+}
+
+type ListFollowingResponseData struct {
+	Count int64        `json:"count"`
+	Users []PublicUser `json:"users"`
+}
+
+type ListFollowingResponse struct {
+	Data       ListFollowingResponseData `json:"data"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+type VerifyResetCodeV1Response struct {
+	Msg string `json:"msg"`
+}
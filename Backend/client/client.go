@@ -0,0 +1,141 @@
+// Package client calls the API from Go, for scripts and internal tooling.
+// The types and methods of api.gen.go are generated from the OpenAPI
+// document of the API, regenerate them after changing a route:
+//
+//	go generate ./client
+//
+// Failures are returned as *Problem, the problem document of the answer.
+package client
+
+//go:generate go run ../cmd/openapi -client api.gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API at BaseURL, e.g. http://localhost:3000, sending
+// Token as the Authorization header when set
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+func New(baseURL, token string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Token: token, HTTPClient: http.DefaultClient}
+}
+
+// RequestOption changes a request before it is sent
+type RequestOption func(*http.Request)
+
+// WithHeader sets a header of the request
+func WithHeader(name, value string) RequestOption {
+	return func(r *http.Request) { r.Header.Set(name, value) }
+}
+
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return fmt.Sprintf("%s (%d %s): %s", p.Title, p.Status, p.Code, p.Detail)
+	}
+	return fmt.Sprintf("%s (%d %s)", p.Title, p.Status, p.Code)
+}
+
+// send calls the API with body as JSON of the given media type, unless it
+// is nil, and decodes the answer into out: []byte takes it as it is
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, header http.Header,
+	body interface{}, media string, out interface{}, opts []RequestOption) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	return c.do(ctx, method, path, query, header, reader, media, out, opts)
+}
+
+// upload sends file as the field of a multipart form
+func (c *Client) upload(ctx context.Context, method, path string, query url.Values, header http.Header,
+	field, filename string, file io.Reader, out interface{}, opts []RequestOption) error {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	return c.do(ctx, method, path, query, header, &buf, form.FormDataContentType(), out, opts)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header,
+	body io.Reader, media string, out interface{}, opts []RequestOption) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", media)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", c.Token)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= 400 {
+		problem := &Problem{}
+		contentType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+		if !strings.HasSuffix(contentType, "json") || json.Unmarshal(data, problem) != nil || problem.Status == 0 {
+			problem = &Problem{Status: int64(res.StatusCode), Title: http.StatusText(res.StatusCode), Detail: string(data)}
+		}
+		return problem
+	}
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*out = data
+		return nil
+	}
+	if res.StatusCode == http.StatusNotModified || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"Gator_blog/client"
+	"Gator_blog/controller"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedClientIsCurrent(t *testing.T) {
+	want, err := controller.APISpec().GoClient("client")
+	require.NoError(t, err)
+	have, err := os.ReadFile("api.gen.go")
+	require.NoError(t, err)
+	assert.True(t, string(want) == string(have), "api.gen.go is stale, run go generate ./client")
+}
+
+func TestClient(t *testing.T) {
+	var seen *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+		body, _ = io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/api/v2/blogs/7":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"id": 7, "title": "Hello", "tags": []map[string]interface{}{{"id": 1, "name": "go"}}}})
+		default:
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"type":"about:blank","title":"Not Found","status":404,"code":"not_found","detail":"Blog not found"}`))
+		}
+	}))
+	defer server.Close()
+	c := client.New(server.URL, "session-token")
+
+	res, err := c.GetBlog(context.Background(), 7, &client.GetBlogParams{IfNoneMatch: `"v1"`})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", res.Data.Title)
+	assert.Equal(t, "go", res.Data.Tags[0].Name)
+	assert.Equal(t, "session-token", seen.Header.Get("Authorization"))
+	assert.Equal(t, `"v1"`, seen.Header.Get("If-None-Match"))
+
+	title := "New"
+	_, err = c.PatchBlog(context.Background(), 8, nil, client.UpdateBlogRequest{Title: &title})
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem))
+	assert.Equal(t, "not_found", problem.Code)
+	assert.EqualValues(t, http.StatusNotFound, problem.Status)
+	assert.Equal(t, "/api/v2/blogs/8", seen.URL.Path)
+	assert.Equal(t, "application/merge-patch+json", seen.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"title":"New"}`, string(body))
+}
@@ -0,0 +1,79 @@
+// Command migrate applies, rolls back and lists the schema migrations. It
+// takes the same configuration flags as the server.
+//
+//	go run ./cmd/migrate [flags] up
+//	go run ./cmd/migrate [flags] [-steps N] down
+//	go run ./cmd/migrate [flags] status
+package main
+
+import (
+	"Gator_blog/config"
+	"Gator_blog/database"
+	"Gator_blog/migrations"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: migrate [flags] up | [-steps N] down | status")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	steps := flag.Int("steps", 1, "number of migrations down rolls back")
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:", err)
+		os.Exit(2)
+	}
+	if flag.NArg() != 1 {
+		usage()
+	}
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Database connection failed:", err)
+		os.Exit(1)
+	}
+
+	switch flag.Arg(0) {
+	case "up":
+		applied, err := migrations.Up(db)
+		exitOn(err)
+		fmt.Printf("%d migration(s) applied\n", len(applied))
+	case "down":
+		if *steps < 1 {
+			usage()
+		}
+		rolledBack, err := migrations.Down(db, *steps)
+		exitOn(err)
+		fmt.Printf("%d migration(s) rolled back\n", len(rolledBack))
+	case "status":
+		states, err := migrations.Status(db)
+		exitOn(err)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, state := range states {
+			appliedAt := "pending"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if state.Unknown {
+				appliedAt += " (unknown to this build)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		usage()
+	}
+}
+
+func exitOn(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
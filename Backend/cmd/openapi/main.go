@@ -0,0 +1,55 @@
+// Command openapi writes the OpenAPI document of the API, as served at
+// /api/openapi.json, and the Go client generated from it. The validation
+// limits are the defaults.
+//
+//	go run ./cmd/openapi -spec openapi.json
+//	go run ./cmd/openapi -client client/api.gen.go
+package main
+
+import (
+	"Gator_blog/controller"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	spec := flag.String("spec", "", "file to write the document to, - for standard output")
+	client := flag.String("client", "", "file to write the Go client to")
+	pkg := flag.String("package", "client", "package of the Go client")
+	flag.Parse()
+	if *spec == "" && *client == "" {
+		fmt.Fprintln(os.Stderr, "usage: openapi [-spec file] [-client file [-package name]]")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	doc := controller.APISpec()
+	if *spec != "" {
+		out, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			fail(err)
+		}
+		out = append(out, '\n')
+		if *spec == "-" {
+			os.Stdout.Write(out)
+		} else if err := os.WriteFile(*spec, out, 0644); err != nil {
+			fail(err)
+		}
+	}
+	if *client != "" {
+		out, err := doc.GoClient(*pkg)
+		if err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(*client, out, 0644); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "openapi:", err)
+	os.Exit(1)
+}
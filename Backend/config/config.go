@@ -0,0 +1,383 @@
+// Package config loads the server configuration. Values come from, in
+// increasing precedence, the defaults of the active profile, a YAML or TOML
+// file, environment variables and command line flags.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// profiles, selected with -env or APP_ENV
+const (
+	Development = "development"
+	Test        = "test"
+	Production  = "production"
+)
+
+// Config is the whole server configuration. Every setting carries its file
+// key, its environment variable and, through its file path, its flag:
+// database.dsn is set by DATABASE_DSN or -database.dsn. Settings tagged
+// secret are masked when the configuration is printed.
+type Config struct {
+	Env        string           `yaml:"-" toml:"-"`
+	Server     ServerConfig     `yaml:"server" toml:"server"`
+	Database   DatabaseConfig   `yaml:"database" toml:"database"`
+	Redis      RedisConfig      `yaml:"redis" toml:"redis"`
+	Cache      CacheConfig      `yaml:"cache" toml:"cache"`
+	SMTP       SMTPConfig       `yaml:"smtp" toml:"smtp"`
+	JWT        JWTConfig        `yaml:"jwt" toml:"jwt"`
+	Storage    StorageConfig    `yaml:"storage" toml:"storage"`
+	Validation ValidationConfig `yaml:"validation" toml:"validation"`
+	API        APIConfig        `yaml:"api" toml:"api"`
+	Analytics  AnalyticsConfig  `yaml:"analytics" toml:"analytics"`
+}
+
+type ServerConfig struct {
+	Addr    string `yaml:"addr" toml:"addr" env:"SERVER_ADDR" usage:"address the HTTP server listens on"`
+	BaseURL string `yaml:"base_url" toml:"base_url" env:"BASE_URL" usage:"public URL of the site used in feeds and sitemaps, the request host when empty"`
+}
+
+type DatabaseConfig struct {
+	Driver string `yaml:"driver" toml:"driver" env:"DATABASE_DRIVER" usage:"mysql, postgres or sqlite"`
+	DSN    string `yaml:"dsn" toml:"dsn" env:"DATABASE_DSN" secret:"dsn" usage:"data source name in the driver's format"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" toml:"addr" env:"REDIS_ADDR" usage:"Redis host:port"`
+	Password string `yaml:"password" toml:"password" env:"REDIS_PASSWORD" secret:"true" usage:"Redis password"`
+	DB       int    `yaml:"db" toml:"db" env:"REDIS_DB" usage:"Redis database number"`
+}
+
+type CacheConfig struct {
+	Driver string `yaml:"driver" toml:"driver" env:"CACHE_DRIVER" usage:"where responses are cached: redis, memory or none"`
+	Size   int    `yaml:"size" toml:"size" env:"CACHE_SIZE" usage:"entries kept by the memory cache"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"SMTP_HOST" usage:"SMTP server for password reset mails"`
+	Port     string `yaml:"port" toml:"port" env:"SMTP_PORT" usage:"SMTP port"`
+	Sender   string `yaml:"sender" toml:"sender" env:"SMTP_SENDER" usage:"address password reset mails are sent from"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD" secret:"true" usage:"SMTP password"`
+}
+
+type JWTConfig struct {
+	Secret string        `yaml:"secret" toml:"secret" env:"JWT_SECRET" secret:"true" usage:"key signing session tokens, at least 32 bytes in production"`
+	TTL    time.Duration `yaml:"ttl" toml:"ttl" env:"JWT_TTL" usage:"lifetime of session tokens"`
+}
+
+type StorageConfig struct {
+	Driver string   `yaml:"driver" toml:"driver" env:"MEDIA_STORAGE" usage:"where uploads are kept, local or s3"`
+	Root   string   `yaml:"root" toml:"root" env:"MEDIA_ROOT" usage:"directory of local uploads"`
+	S3     S3Config `yaml:"s3" toml:"s3"`
+}
+
+type ValidationConfig struct {
+	TitleMax        int    `yaml:"title_max" toml:"title_max" env:"VALIDATION_TITLE_MAX" usage:"characters allowed in blog titles"`
+	PostMax         int    `yaml:"post_max" toml:"post_max" env:"VALIDATION_POST_MAX" usage:"characters allowed in blog posts"`
+	CommentMax      int    `yaml:"comment_max" toml:"comment_max" env:"VALIDATION_COMMENT_MAX" usage:"characters allowed in comments"`
+	ListNameMax     int    `yaml:"list_name_max" toml:"list_name_max" env:"VALIDATION_LIST_NAME_MAX" usage:"characters allowed in reading list names"`
+	UsernameMin     int    `yaml:"username_min" toml:"username_min" env:"VALIDATION_USERNAME_MIN" usage:"shortest username"`
+	UsernameMax     int    `yaml:"username_max" toml:"username_max" env:"VALIDATION_USERNAME_MAX" usage:"longest username"`
+	UsernamePattern string `yaml:"username_pattern" toml:"username_pattern" env:"VALIDATION_USERNAME_PATTERN" usage:"regular expression usernames must match"`
+	PasswordMin     int    `yaml:"password_min" toml:"password_min" env:"VALIDATION_PASSWORD_MIN" usage:"shortest password"`
+	PasswordMax     int    `yaml:"password_max" toml:"password_max" env:"VALIDATION_PASSWORD_MAX" usage:"longest password, bcrypt only reads 72 bytes"`
+	PasswordClasses int    `yaml:"password_classes" toml:"password_classes" env:"VALIDATION_PASSWORD_CLASSES" usage:"kinds of characters a password must mix, of lower case, upper case, digits and symbols"`
+}
+
+// AnalyticsConfig bounds what is kept of readers. Views are stored under a
+// keyed hash of the reader, and only for a while, their daily stats stay.
+type AnalyticsConfig struct {
+	ViewerSecret  string        `yaml:"viewer_secret" toml:"viewer_secret" env:"ANALYTICS_VIEWER_SECRET" secret:"true" usage:"key hashing readers into viewer ids, at least 32 bytes in production"`
+	ViewRetention time.Duration `yaml:"view_retention" toml:"view_retention" env:"ANALYTICS_VIEW_RETENTION" usage:"how long single views are kept, at least 72h so the rollup can recount yesterday"`
+}
+
+// APIConfig dates the retirement of /api, v1, in favour of /api/v2. The
+// dates are announced on every v1 response, see middleware.Deprecated.
+type APIConfig struct {
+	V1Deprecated string `yaml:"v1_deprecated" toml:"v1_deprecated" env:"API_V1_DEPRECATED" usage:"day v1 was deprecated, as YYYY-MM-DD"`
+	V1Sunset     string `yaml:"v1_sunset" toml:"v1_sunset" env:"API_V1_SUNSET" usage:"day v1 may stop being served, as YYYY-MM-DD"`
+}
+
+// layout of the dates of APIConfig
+const DateLayout = "2006-01-02"
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" toml:"endpoint" env:"S3_ENDPOINT" usage:"S3 endpoint host"`
+	Region    string `yaml:"region" toml:"region" env:"S3_REGION" usage:"S3 region"`
+	Bucket    string `yaml:"bucket" toml:"bucket" env:"S3_BUCKET" usage:"S3 bucket"`
+	AccessKey string `yaml:"access_key" toml:"access_key" env:"S3_ACCESS_KEY" usage:"S3 access key"`
+	SecretKey string `yaml:"secret_key" toml:"secret_key" env:"S3_SECRET_KEY" secret:"true" usage:"S3 secret key"`
+	UseSSL    bool   `yaml:"use_ssl" toml:"use_ssl" env:"S3_USE_SSL" usage:"connect to S3 over TLS"`
+	PublicURL string `yaml:"public_url" toml:"public_url" env:"S3_PUBLIC_URL" usage:"URL uploads are served from, the bucket URL when empty"`
+}
+
+// Defaults returns the built-in settings of a profile. Only development
+// falls back to a local database, other profiles must configure one.
+func Defaults(env string) Config {
+	cfg := Config{
+		Env:      env,
+		Server:   ServerConfig{Addr: ":8000"},
+		Database: DatabaseConfig{Driver: "mysql"},
+		Redis:    RedisConfig{Addr: "localhost:6379"},
+		Cache:    CacheConfig{Driver: "redis", Size: 10000},
+		SMTP:     SMTPConfig{Host: "smtp.gmail.com", Port: "587", Sender: "gatorblog.help@gmail.com"},
+		JWT:      JWTConfig{TTL: 24 * time.Hour},
+		Storage:  StorageConfig{Driver: "local", Root: "./uploads", S3: S3Config{UseSSL: true}},
+		Validation: ValidationConfig{TitleMax: 200, PostMax: 100000, CommentMax: 5000, ListNameMax: 100,
+			UsernameMin: 3, UsernameMax: 30, UsernamePattern: `^[A-Za-z0-9._-]+$`,
+			PasswordMin: 8, PasswordMax: 72, PasswordClasses: 2},
+		API:       APIConfig{V1Deprecated: "2026-10-19", V1Sunset: "2027-04-30"},
+		Analytics: AnalyticsConfig{ViewRetention: 30 * 24 * time.Hour},
+	}
+	if env == Test {
+		cfg.Cache.Driver = "memory"
+	}
+	return cfg
+}
+
+// local databases the development profile uses when no DSN is configured
+var developmentDSNs = map[string]string{
+	"mysql":    "root:@tcp(localhost:3306)/gator_blog_db?charset=utf8mb4&parseTime=True&loc=Local",
+	"postgres": "host=localhost user=postgres dbname=gator_blog_db sslmode=disable",
+	"sqlite":   "gator_blog.db",
+}
+
+// Load builds the configuration for the command line args, registering its
+// flags on fs. The file is -config, CONFIG_FILE or config.yaml, config.yml or
+// config.toml in the working directory when one exists, and is followed by
+// the profile file beside it, e.g. config.production.yaml.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "configuration file, YAML or TOML")
+	env := fs.String("env", envOr("APP_ENV", Development), "profile: development, test or production")
+	var sample Config
+	flags := registerFlags(fs, &sample)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch *env {
+	case Development, Test, Production:
+	default:
+		return nil, fmt.Errorf("unknown profile %q", *env)
+	}
+	cfg := Defaults(*env)
+
+	path := *configFile
+	if path == "" {
+		path = findFile(".", "config")
+	}
+	if path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return nil, err
+		}
+		ext := filepath.Ext(path)
+		profile := strings.TrimSuffix(path, ext) + "." + *env + ext
+		if _, err := os.Stat(profile); err == nil {
+			if err := readFile(profile, &cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return nil, err
+	}
+	if err := flags.apply(fs, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.Env = *env
+	if cfg.Env == Development && cfg.Database.DSN == "" {
+		cfg.Database.DSN = developmentDSNs[cfg.Database.Driver]
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate reports every setting that is missing or invalid for the profile
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.Addr == "" {
+		errs = append(errs, errors.New("server.addr is required"))
+	}
+	if c.Server.BaseURL != "" {
+		if u, err := url.Parse(c.Server.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, errors.New("server.base_url must be an absolute URL"))
+		}
+	}
+	switch c.Database.Driver {
+	case "mysql", "postgres", "sqlite":
+	default:
+		errs = append(errs, fmt.Errorf("database.driver must be mysql, postgres or sqlite, not %q", c.Database.Driver))
+	}
+	if c.Database.DSN == "" {
+		errs = append(errs, errors.New("database.dsn is required"))
+	}
+	if c.Redis.Addr == "" {
+		errs = append(errs, errors.New("redis.addr is required"))
+	}
+	switch c.Cache.Driver {
+	case "redis", "none":
+	case "memory":
+		if c.Cache.Size < 1 {
+			errs = append(errs, errors.New("cache.size must be positive for the memory cache"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("cache.driver must be redis, memory or none, not %q", c.Cache.Driver))
+	}
+	if c.JWT.TTL <= 0 {
+		errs = append(errs, errors.New("jwt.ttl must be positive"))
+	}
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.Root == "" {
+			errs = append(errs, errors.New("storage.root is required for local storage"))
+		}
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" {
+			errs = append(errs, errors.New("storage.s3.endpoint and storage.s3.bucket are required for s3 storage"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.driver must be local or s3, not %q", c.Storage.Driver))
+	}
+	errs = append(errs, c.Validation.validate()...)
+	errs = append(errs, c.API.validate()...)
+	if c.Analytics.ViewRetention < 72*time.Hour {
+		errs = append(errs, errors.New("analytics.view_retention must be at least 72h"))
+	}
+
+	if c.Env == Production {
+		if len(c.JWT.Secret) < 32 {
+			errs = append(errs, errors.New("jwt.secret of at least 32 bytes is required in production"))
+		}
+		if len(c.Analytics.ViewerSecret) < 32 {
+			errs = append(errs, errors.New("analytics.viewer_secret of at least 32 bytes is required in production"))
+		}
+		if c.SMTP.Password == "" {
+			errs = append(errs, errors.New("smtp.password is required in production"))
+		}
+		if c.Server.BaseURL == "" {
+			errs = append(errs, errors.New("server.base_url is required in production"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// the columns limited text is stored in and how many characters they hold.
+// Posts are MEDIUMTEXT on MySQL, 16MB of up to 4 byte characters, and
+// usernames are copied to blogs.user_name.
+var columnSizes = map[string]struct {
+	column string
+	size   int
+}{
+	"title_max":     {"blogs.title", 255},
+	"post_max":      {"blogs.post", 1<<24/4 - 1},
+	"list_name_max": {"reading_lists.name", 100},
+	"username_max":  {"blogs.user_name", 50},
+}
+
+// reports limits that would refuse every request or could not be applied
+func (v ValidationConfig) validate() []error {
+	var errs []error
+	for name, limit := range map[string]int{"title_max": v.TitleMax, "post_max": v.PostMax, "comment_max": v.CommentMax,
+		"list_name_max": v.ListNameMax, "username_max": v.UsernameMax, "password_max": v.PasswordMax} {
+		if limit < 1 {
+			errs = append(errs, fmt.Errorf("validation.%s must be positive", name))
+		}
+		if col, ok := columnSizes[name]; ok && limit > col.size {
+			errs = append(errs, fmt.Errorf("validation.%s must be at most %d, the size of %s", name, col.size, col.column))
+		}
+	}
+	if v.UsernameMin < 1 || v.UsernameMin > v.UsernameMax {
+		errs = append(errs, errors.New("validation.username_min must be between 1 and validation.username_max"))
+	}
+	if v.PasswordMin < 1 || v.PasswordMin > v.PasswordMax {
+		errs = append(errs, errors.New("validation.password_min must be between 1 and validation.password_max"))
+	}
+	if v.PasswordClasses < 0 || v.PasswordClasses > 4 {
+		errs = append(errs, errors.New("validation.password_classes must be between 0 and 4"))
+	}
+	if _, err := regexp.Compile(v.UsernamePattern); err != nil {
+		errs = append(errs, fmt.Errorf("validation.username_pattern: %v", err))
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// reports dates that cannot be read or a sunset before the deprecation
+func (a APIConfig) validate() []error {
+	deprecated, err := time.Parse(DateLayout, a.V1Deprecated)
+	if err != nil {
+		return []error{fmt.Errorf("api.v1_deprecated must be a date as YYYY-MM-DD, not %q", a.V1Deprecated)}
+	}
+	sunset, err := time.Parse(DateLayout, a.V1Sunset)
+	if err != nil {
+		return []error{fmt.Errorf("api.v1_sunset must be a date as YYYY-MM-DD, not %q", a.V1Sunset)}
+	}
+	if !sunset.After(deprecated) {
+		return []error{errors.New("api.v1_sunset must be after api.v1_deprecated")}
+	}
+	return nil
+}
+
+// String renders the configuration as YAML with its secrets masked, for logs
+func (c Config) String() string {
+	redacted := c
+	redact(&redacted)
+	out, err := yaml.Marshal(redacted)
+	if err != nil {
+		return err.Error()
+	}
+	return "env: " + c.Env + "\n" + string(out)
+}
+
+// first of name.yaml, name.yml and name.toml in dir
+func findFile(dir, name string) string {
+	for _, ext := range []string{".yaml", ".yml", ".toml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// decodes a file over cfg, keeping the settings it does not mention
+func readFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		_, err = toml.Decode(string(data), cfg)
+	default:
+		return fmt.Errorf("%s: configuration files must be YAML or TOML", path)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
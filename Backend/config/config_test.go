@@ -0,0 +1,183 @@
+package config_test
+
+import (
+	"Gator_blog/config"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clears the variables Load reads and runs it in an empty directory
+func setup(t *testing.T) string {
+	for _, name := range []string{"APP_ENV", "CONFIG_FILE", "SERVER_ADDR", "BASE_URL", "DATABASE_DRIVER", "DATABASE_DSN", "REDIS_ADDR",
+		"REDIS_PASSWORD", "REDIS_DB", "CACHE_DRIVER", "CACHE_SIZE", "SMTP_PASSWORD", "JWT_SECRET", "JWT_TTL", "MEDIA_STORAGE", "S3_ENDPOINT", "S3_BUCKET",
+		"API_V1_DEPRECATED", "API_V1_SUNSET"} {
+		t.Setenv(name, "")
+	}
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func load(args ...string) (*config.Config, error) {
+	return config.Load(flag.NewFlagSet("test", flag.ContinueOnError), args)
+}
+
+func TestDevelopmentDefaults(t *testing.T) {
+	setup(t)
+	cfg, err := load()
+	assert.Nil(t, err)
+	assert.Equal(t, config.Development, cfg.Env)
+	assert.Equal(t, ":8000", cfg.Server.Addr)
+	assert.Contains(t, cfg.Database.DSN, "gator_blog_db")
+	assert.Equal(t, "localhost:6379", cfg.Redis.Addr)
+	assert.Equal(t, "redis", cfg.Cache.Driver)
+	assert.Equal(t, 24*time.Hour, cfg.JWT.TTL)
+	assert.Empty(t, cfg.JWT.Secret)
+	assert.Empty(t, cfg.SMTP.Password)
+}
+
+func TestProductionRequiresSecrets(t *testing.T) {
+	setup(t)
+	_, err := load("-env", "production")
+	assert.NotNil(t, err)
+	for _, setting := range []string{"database.dsn", "jwt.secret", "analytics.viewer_secret", "smtp.password", "server.base_url"} {
+		assert.Contains(t, err.Error(), setting)
+	}
+
+	t.Setenv("DATABASE_DSN", "gator:pw@tcp(db:3306)/gator")
+	t.Setenv("JWT_SECRET", strings.Repeat("k", 32))
+	t.Setenv("ANALYTICS_VIEWER_SECRET", strings.Repeat("v", 32))
+	t.Setenv("SMTP_PASSWORD", "smtp-pw")
+	t.Setenv("BASE_URL", "https://blog.example.com")
+	cfg, err := load("-env", "production")
+	assert.Nil(t, err)
+	assert.Equal(t, config.Production, cfg.Env)
+}
+
+// Test that flags beat the environment, which beats the profile file, which
+// beats the base file
+func TestPrecedence(t *testing.T) {
+	dir := setup(t)
+	os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(`
+server:
+  addr: ":9000"
+redis:
+  addr: "file:6379"
+  db: 2
+jwt:
+  ttl: 1h
+`), 0o644)
+	os.WriteFile(filepath.Join(dir, "config.test.yaml"), []byte(`
+redis:
+  addr: "profile:6379"
+`), 0o644)
+
+	cfg, err := load("-env", "test", "-database.dsn", "test.db")
+	assert.Nil(t, err)
+	assert.Equal(t, ":9000", cfg.Server.Addr)
+	assert.Equal(t, "profile:6379", cfg.Redis.Addr)
+	assert.Equal(t, 2, cfg.Redis.DB)
+	assert.Equal(t, time.Hour, cfg.JWT.TTL)
+	assert.Equal(t, "memory", cfg.Cache.Driver)
+
+	t.Setenv("REDIS_ADDR", "env:6379")
+	t.Setenv("SERVER_ADDR", ":9100")
+	cfg, err = load("-env", "test", "-database.dsn", "test.db", "-server.addr", ":9200")
+	assert.Nil(t, err)
+	assert.Equal(t, "env:6379", cfg.Redis.Addr)
+	assert.Equal(t, ":9200", cfg.Server.Addr)
+}
+
+func TestTOMLFile(t *testing.T) {
+	dir := setup(t)
+	path := filepath.Join(dir, "settings.toml")
+	os.WriteFile(path, []byte(`
+[storage]
+driver = "s3"
+
+[storage.s3]
+endpoint = "s3.example.com"
+bucket = "uploads"
+use_ssl = false
+`), 0o644)
+
+	cfg, err := load("-config", path)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3", cfg.Storage.Driver)
+	assert.Equal(t, "uploads", cfg.Storage.S3.Bucket)
+	assert.False(t, cfg.Storage.S3.UseSSL)
+}
+
+func TestInvalidValues(t *testing.T) {
+	setup(t)
+	t.Setenv("REDIS_DB", "two")
+	_, err := load()
+	assert.ErrorContains(t, err, "REDIS_DB")
+
+	t.Setenv("REDIS_DB", "")
+	_, err = load("-storage.driver", "ftp")
+	assert.ErrorContains(t, err, "storage.driver")
+
+	_, err = load("-cache.driver", "disk")
+	assert.ErrorContains(t, err, "cache.driver")
+
+	_, err = load("-cache.driver", "memory", "-cache.size", "0")
+	assert.ErrorContains(t, err, "cache.size")
+
+	_, err = load("-validation.username_pattern", "[a-z")
+	assert.ErrorContains(t, err, "validation.username_pattern")
+
+	_, err = load("-validation.password_min", "100")
+	assert.ErrorContains(t, err, "validation.password_min")
+
+	// limits must fit the columns the text is stored in
+	_, err = load("-validation.title_max", "300")
+	assert.ErrorContains(t, err, "validation.title_max must be at most 255")
+
+	_, err = load("-analytics.view_retention", "24h")
+	assert.ErrorContains(t, err, "analytics.view_retention")
+
+	_, err = load("-api.v1_sunset", "next spring")
+	assert.ErrorContains(t, err, "api.v1_sunset")
+
+	_, err = load("-api.v1_sunset", "2020-01-01")
+	assert.ErrorContains(t, err, "api.v1_sunset must be after")
+
+	_, err = load("-env", "staging")
+	assert.ErrorContains(t, err, "staging")
+}
+
+func TestStringRedactsSecrets(t *testing.T) {
+	setup(t)
+	t.Setenv("JWT_SECRET", "jwt-secret-value")
+	t.Setenv("SMTP_PASSWORD", "smtp-secret-value")
+	t.Setenv("DATABASE_DSN", "gator:db-secret-value@tcp(db:3306)/gator")
+	cfg, err := load()
+	assert.Nil(t, err)
+
+	out := cfg.String()
+	for _, secret := range []string{"jwt-secret-value", "smtp-secret-value", "db-secret-value"} {
+		assert.NotContains(t, out, secret)
+	}
+	assert.Contains(t, out, "gator:******@tcp(db:3306)/gator")
+	assert.Equal(t, "jwt-secret-value", cfg.JWT.Secret)
+
+	t.Setenv("DATABASE_DRIVER", "postgres")
+	for dsn, want := range map[string]string{
+		"host=db user=gator password=db-secret-value dbname=gator": "host=db user=gator password=****** dbname=gator",
+		"postgres://gator:db-secret-value@db:5432/gator":           "postgres://gator:******@db:5432/gator",
+	} {
+		t.Setenv("DATABASE_DSN", dsn)
+		cfg, err := load()
+		assert.Nil(t, err)
+		assert.Contains(t, cfg.String(), want)
+	}
+}
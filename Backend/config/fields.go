@@ -0,0 +1,165 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const mask = "******"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// calls fn for every setting of the struct v points into, with its dotted
+// file path such as storage.s3.bucket
+func walk(v reflect.Value, prefix string, fn func(path string, field reflect.StructField, value reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "-" || name == "" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		if field.Type.Kind() == reflect.Struct {
+			walk(v.Field(i), name, fn)
+			continue
+		}
+		fn(name, field, v.Field(i))
+	}
+}
+
+// parses raw into a setting
+func set(value reflect.Value, raw string) error {
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+// overrides settings with the environment variables named in their env
+// tags. Empty variables count as unset.
+func applyEnv(cfg *Config) error {
+	var err error
+	walk(reflect.ValueOf(cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		name := field.Tag.Get("env")
+		raw := os.Getenv(name)
+		if name == "" || raw == "" || err != nil {
+			return
+		}
+		if e := set(value, raw); e != nil {
+			err = fmt.Errorf("%s: %w", name, e)
+		}
+	})
+	return err
+}
+
+// a flag that only remembers what was passed, so unset flags leave the
+// setting alone
+type rawFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *rawFlag) String() string     { return f.value }
+func (f *rawFlag) Set(s string) error { f.value = s; return nil }
+func (f *rawFlag) IsBoolFlag() bool   { return f.isBool }
+
+type flagSet map[string]*rawFlag
+
+// registers a flag per setting of cfg, named by its file path
+func registerFlags(fs *flag.FlagSet, cfg *Config) flagSet {
+	flags := flagSet{}
+	walk(reflect.ValueOf(cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		f := &rawFlag{isBool: value.Kind() == reflect.Bool}
+		fs.Var(f, path, field.Tag.Get("usage"))
+		flags[path] = f
+	})
+	return flags
+}
+
+// overrides settings with the flags that were passed
+func (flags flagSet) apply(fs *flag.FlagSet, cfg *Config) error {
+	passed := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { passed[f.Name] = true })
+
+	var err error
+	walk(reflect.ValueOf(cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		if !passed[path] || err != nil {
+			return
+		}
+		if e := set(value, flags[path].value); e != nil {
+			err = fmt.Errorf("-%s: %w", path, e)
+		}
+	})
+	return err
+}
+
+// masks the settings tagged secret in place
+func redact(cfg *Config) {
+	walk(reflect.ValueOf(cfg).Elem(), "", func(path string, field reflect.StructField, value reflect.Value) {
+		switch field.Tag.Get("secret") {
+		case "true":
+			if value.String() != "" {
+				value.SetString(mask)
+			}
+		case "dsn":
+			value.SetString(redactDSN(value.String()))
+		}
+	})
+}
+
+// password in a PostgreSQL keyword/value data source name
+var dsnPassword = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// masks the password of a URL style, keyword/value or MySQL style data
+// source name
+func redactDSN(dsn string) string {
+	if dsnPassword.MatchString(dsn) {
+		return dsnPassword.ReplaceAllString(dsn, "${1}"+mask)
+	}
+	if u, err := url.Parse(dsn); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return strings.Replace(dsn, u.User.String()+"@", url.User(u.User.Username()).String()+":"+mask+"@", 1)
+		}
+		return dsn
+	}
+	at := strings.LastIndex(dsn, "@")
+	if at < 0 {
+		return dsn
+	}
+	if colon := strings.Index(dsn[:at], ":"); colon >= 0 && colon+1 < at {
+		return dsn[:colon+1] + mask + dsn[at:]
+	}
+	return dsn
+}
@@ -0,0 +1,140 @@
+// Package container wires the repositories, services, handlers and jobs of
+// one application instance, so two instances can run side by side on
+// different databases, Redis stores, caches and upload storage.
+package container
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/controller"
+	"Gator_blog/jobs"
+	"Gator_blog/ranking"
+	"Gator_blog/redis"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/storage"
+	"Gator_blog/utils"
+
+	"gorm.io/gorm"
+)
+
+// Repos are the storage a container is built on
+type Repos struct {
+	Users        repository.UserRepo
+	Blogs        repository.BlogRepo
+	Comments     repository.CommentRepo
+	Likes        repository.LikeRepo
+	Follows      repository.FollowRepo
+	Bookmarks    repository.BookmarkRepo
+	ReadingLists repository.ReadingListRepo
+	Media        repository.MediaRepo
+	Analytics    repository.AnalyticsRepo
+	Profiles     repository.ProfileRepo
+	Site         repository.SiteRepo
+	Accounts     repository.AccountRepo
+}
+
+// GormRepos returns the repositories backed by db
+func GormRepos(db *gorm.DB) Repos {
+	return Repos{
+		Users:        repository.NewUsers(db),
+		Blogs:        repository.NewBlogs(db),
+		Comments:     repository.NewComments(db),
+		Likes:        repository.NewLikes(db),
+		Follows:      repository.NewFollows(db),
+		Bookmarks:    repository.NewBookmarks(db),
+		ReadingLists: repository.NewReadingLists(db),
+		Media:        repository.NewMedia(db),
+		Analytics:    repository.NewAnalytics(db),
+		Profiles:     repository.NewProfiles(db),
+		Site:         repository.NewSite(db),
+		Accounts:     repository.NewAccounts(db),
+	}
+}
+
+// Infra is what a container keeps outside its database: the Redis store of
+// counters, timelines and leaderboards, the response cache and the storage of
+// uploaded files, nil when uploads are not configured
+type Infra struct {
+	Store   *redis.Store
+	Cache   cache.Cache
+	Storage storage.Storage
+}
+
+type Container struct {
+	Repos Repos
+	Infra Infra
+
+	Ranker *ranking.Ranker
+	Jobs   *jobs.Runner
+
+	UserService        *service.UserService
+	BlogService        *service.BlogService
+	CommentService     *service.CommentService
+	LikeService        *service.LikeService
+	FollowService      *service.FollowService
+	BookmarkService    *service.BookmarkService
+	ReadingListService *service.ReadingListService
+	MediaService       *service.MediaService
+	AnalyticsService   *service.AnalyticsService
+	ProfileService     *service.ProfileService
+	SiteService        *service.SiteService
+	AccountService     *service.AccountService
+
+	Users        *controller.UserHandler
+	Blogs        *controller.BlogHandler
+	Comments     *controller.CommentHandler
+	Likes        *controller.LikeHandler
+	Follows      *controller.FollowHandler
+	Feed         *controller.FeedHandler
+	Bookmarks    *controller.BookmarkHandler
+	ReadingLists *controller.ReadingListHandler
+	Media        *controller.MediaHandler
+	Analytics    *controller.AnalyticsHandler
+	Profiles     *controller.ProfileHandler
+	Site         *controller.SiteHandler
+	Accounts     *controller.AccountHandler
+}
+
+// New returns a container on db and infra that mails reset codes through
+// utils
+func New(db *gorm.DB, infra Infra) *Container {
+	ranker := ranking.NewRanker(db, infra.Store)
+	c := Build(GormRepos(db), utils.SendResetCodeEmail, infra, ranker)
+	c.Jobs = jobs.NewRunner(db, infra.Store, ranker)
+	return c
+}
+
+// Build returns a container on the given repositories, mailer, infra and
+// ranking of popular blogs
+func Build(repos Repos, mail service.Mailer, infra Infra, ranker *ranking.Ranker) *Container {
+	c := &Container{Repos: repos, Infra: infra, Ranker: ranker}
+	c.UserService = service.NewUserService(repos.Users, mail)
+	c.BlogService = service.NewBlogService(repos.Blogs)
+	c.CommentService = service.NewCommentService(repos.Comments, repos.Blogs)
+	c.LikeService = service.NewLikeService(repos.Likes, repos.Blogs)
+	c.FollowService = service.NewFollowService(repos.Follows, repos.Users)
+	c.BookmarkService = service.NewBookmarkService(repos.Bookmarks, repos.Blogs)
+	c.ReadingListService = service.NewReadingListService(repos.ReadingLists, repos.Blogs)
+	c.MediaService = service.NewMediaService(repos.Media)
+	c.AnalyticsService = service.NewAnalyticsService(repos.Analytics)
+	c.ProfileService = service.NewProfileService(repos.Profiles)
+	c.SiteService = service.NewSiteService(repos.Site)
+	c.AccountService = service.NewAccountService(repos.Accounts)
+
+	uploads := controller.NewUploads(c.MediaService, infra.Storage)
+	meta := controller.NewMetaLoader(c.LikeService, c.CommentService, c.BookmarkService, uploads, infra.Store)
+	c.Users = controller.NewUserHandler(c.UserService, uploads)
+	c.Blogs = controller.NewBlogHandler(c.BlogService, c.UserService, c.FollowService, uploads, c.AnalyticsService, meta, ranker, infra.Store, infra.Cache)
+	c.Comments = controller.NewCommentHandler(c.CommentService, c.UserService, infra.Store)
+	c.Likes = controller.NewLikeHandler(c.LikeService, c.UserService, infra.Store)
+	c.Follows = controller.NewFollowHandler(c.FollowService, c.UserService, infra.Store)
+	c.Feed = controller.NewFeedHandler(c.FollowService, c.BlogService, c.UserService, meta, infra.Store)
+	c.Bookmarks = controller.NewBookmarkHandler(c.BookmarkService, c.UserService, meta)
+	c.ReadingLists = controller.NewReadingListHandler(c.ReadingListService, c.UserService, meta)
+	c.Media = controller.NewMediaHandler(uploads, c.BlogService, c.UserService, infra.Cache)
+	c.Analytics = controller.NewAnalyticsHandler(c.AnalyticsService, c.UserService)
+	c.Profiles = controller.NewProfileHandler(c.ProfileService, c.UserService, c.BlogService, uploads, meta)
+	c.Site = controller.NewSiteHandler(c.SiteService, c.BlogService, c.UserService, uploads, infra.Cache)
+	c.Accounts = controller.NewAccountHandler(c.AccountService, c.UserService, uploads, infra.Store, infra.Cache)
+	return c
+}
@@ -0,0 +1,105 @@
+package container_test
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/container"
+	"Gator_blog/database/dbtest"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/ranking"
+	"Gator_blog/redis"
+	"Gator_blog/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// one application instance with its own database, Redis and upload storage
+type instance struct {
+	deps *container.Container
+	app  *fiber.App
+	blog model.Blog
+}
+
+func newInstance(t *testing.T) *instance {
+	db := dbtest.Open(t, &model.User{}, &model.Tag{}, &model.Blog{}, &model.Comment{}, &model.Like{}, &model.BlogView{},
+		&model.Follow{}, &model.Bookmark{}, &model.Media{}, &model.MediaVariant{})
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	deps := container.New(db, container.Infra{
+		Store:   redis.NewStore(client),
+		Cache:   cache.NewRedis(client),
+		Storage: storage.NewLocal(t.TempDir(), "/uploads"),
+	})
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "alice@example.com")
+		return c.Next()
+	})
+	app.Get("/blogs/:id", deps.Blogs.Fetch)
+	app.Post("/blogs/:id/likes", deps.Likes.Toggle)
+	app.Get("/blogs/:id/likes", deps.Likes.Count)
+
+	user := model.User{Username: "alice", Email: "alice@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	blog := model.Blog{Title: "Hello", Post: "content", UserID: user.ID, UserName: user.Username}
+	db.Create(&blog)
+	return &instance{deps: deps, app: app, blog: blog}
+}
+
+func (i *instance) likes(t *testing.T, blogID uint) int64 {
+	resp, err := i.app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d/likes", blogID), nil))
+	assert.NoError(t, err)
+	var body map[string]int64
+	json.NewDecoder(resp.Body).Decode(&body)
+	return body["likes"]
+}
+
+// Test that two containers on separate databases and Redis servers share no
+// counters, caches, leaderboards or jobs
+func TestContainersAreIsolated(t *testing.T) {
+	if dbtest.Driver() != "sqlite" {
+		t.Skip("needs two databases")
+	}
+	first, second := newInstance(t), newInstance(t)
+	// both databases number their first blog the same
+	assert.Equal(t, first.blog.ID, second.blog.ID)
+	id := first.blog.ID
+
+	resp, err := first.app.Test(httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/likes", id), nil))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, int64(1), first.likes(t, id))
+	assert.Equal(t, int64(0), second.likes(t, id))
+
+	// reading a blog caches it in its own instance only
+	second.deps.Repos.Blogs.Delete(&second.blog)
+	resp, _ = first.app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d", id), nil))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = second.app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d", id), nil))
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// jobs write back and rank the counters of their own database
+	assert.NoError(t, first.deps.Jobs.ReconcileCounters())
+	assert.NoError(t, first.deps.Ranker.Refresh())
+	assert.NoError(t, second.deps.Ranker.Refresh())
+	ids, err := first.deps.Ranker.Top(ranking.All, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{id}, ids)
+	ids, err = second.deps.Ranker.Top(ranking.All, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	stored, err := first.deps.BlogService.Get(id)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stored.LikesCount)
+}
@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AccountHandler serves the account of the signed in user
+type AccountHandler struct {
+	accounts *service.AccountService
+	users    *service.UserService
+	media    *Uploads
+	store    *redis.Store
+	cache    cache.Cache
+}
+
+func NewAccountHandler(accounts *service.AccountService, users *service.UserService, media *Uploads, store *redis.Store, responses cache.Cache) *AccountHandler {
+	return &AccountHandler{accounts: accounts, users: users, media: media, store: store, cache: responses}
+}
+
+// Deletes the signed in user's account after confirming {"password"}, with
+// their posts, comments, likes, follows, lists and uploaded files
+func (h *AccountHandler) Delete(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Account deleted successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	var input deleteAccountRequest
+	if err := c.BodyParser(&input); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(input); err != nil {
+		return problem.Invalid(err)
+	}
+	deleted, err := h.accounts.Delete(&user, input.Password)
+	if errors.Is(err, service.ErrIncorrectPassword) {
+		return problem.Unauthorized("Incorrect password")
+	}
+	if err != nil {
+		return problem.Failed("Could not delete account", err)
+	}
+
+	// the rows are gone, clean up what lives outside the database
+	for _, m := range deleted.Media {
+		deleteStoredFiles(h.media.files, m)
+	}
+	for _, id := range deleted.BlogIDs {
+		h.store.DeleteCounters(id)
+	}
+	invalidateTimeline(h.store, user.ID)
+	invalidateAuthor(h.cache, user.ID)
+	return c.Status(200).JSON(context)
+}
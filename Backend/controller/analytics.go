@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/service"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// default and maximum number of days covered by the analytics endpoint
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 366
+)
+
+type AnalyticsTotals struct {
+	Views         int64 `json:"views"`
+	UniqueReaders int64 `json:"unique_readers"`
+	Likes         int64 `json:"likes"`
+	Comments      int64 `json:"comments"`
+}
+
+type PostAnalytics struct {
+	ID     uint                  `json:"id"`
+	Title  string                `json:"title"`
+	Totals AnalyticsTotals       `json:"totals"`
+	Daily  []model.BlogDailyStat `json:"daily"`
+}
+
+// AnalyticsHandler serves the reading statistics of the signed in author
+type AnalyticsHandler struct {
+	analytics *service.AnalyticsService
+	users     *service.UserService
+}
+
+func NewAnalyticsHandler(analytics *service.AnalyticsService, users *service.UserService) *AnalyticsHandler {
+	return &AnalyticsHandler{analytics: analytics, users: users}
+}
+
+// returns views, unique readers, likes and comments per day for every post of
+// the signed in author. Accepts ?from=YYYY-MM-DD&to=YYYY-MM-DD
+func (h *AnalyticsHandler) Mine(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Analytics",
+	}
+
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := analyticsRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		return problem.BadRequest("Invalid date range")
+	}
+
+	blogs, err := h.analytics.Posts(user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch analytics", err)
+	}
+	ids := make([]uint, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+
+	stats, err := h.analytics.DailyStats(ids, from, to)
+	if err != nil {
+		return problem.Failed("Could not fetch analytics", err)
+	}
+
+	// unique readers do not add up across days or posts, count them over the
+	// whole range
+	readers, err := h.analytics.Readers(ids, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return problem.Failed("Could not fetch analytics", err)
+	}
+	var totals AnalyticsTotals
+	totals.UniqueReaders, err = h.analytics.AuthorReaders(user.ID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return problem.Failed("Could not fetch analytics", err)
+	}
+
+	posts := make([]PostAnalytics, len(blogs))
+	index := make(map[uint]int, len(blogs))
+	for i, blog := range blogs {
+		posts[i] = PostAnalytics{ID: blog.ID, Title: blog.Title, Daily: []model.BlogDailyStat{}}
+		posts[i].Totals.UniqueReaders = readers[blog.ID]
+		index[blog.ID] = i
+	}
+	for _, stat := range stats {
+		post := &posts[index[stat.BlogID]]
+		post.Daily = append(post.Daily, stat)
+		post.Totals.Views += stat.Views
+		post.Totals.Likes += stat.Likes
+		post.Totals.Comments += stat.Comments
+		totals.Views += stat.Views
+		totals.Likes += stat.Likes
+		totals.Comments += stat.Comments
+	}
+	context["from"] = from.Format(model.DayLayout)
+	context["to"] = to.Format(model.DayLayout)
+	context["totals"] = totals
+	context["posts"] = posts
+	return c.Status(200).JSON(context)
+}
+
+// parses the requested day range, defaulting to the last defaultAnalyticsDays days
+func analyticsRange(fromParam, toParam string) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if toParam != "" {
+		t, err := time.ParseInLocation(model.DayLayout, toParam, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultAnalyticsDays - 1))
+	if fromParam != "" {
+		t, err := time.ParseInLocation(model.DayLayout, fromParam, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = t
+	}
+	if from.After(to) || to.Sub(from) > maxAnalyticsDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fiber.ErrBadRequest
+	}
+	return from, to, nil
+}
@@ -0,0 +1,222 @@
+package controller_test
+
+import (
+	"Gator_blog/config"
+	"Gator_blog/controller"
+	"Gator_blog/jobs"
+	"Gator_blog/model"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+const browserUserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+
+// Define the test suite for view tracking and author analytics
+type AnalyticsTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	mr     *miniredis.Miniredis
+	author model.User
+	reader model.User
+	blogID uint
+}
+
+// Setup before each test
+func (suite *AnalyticsTestSuite) SetupTest() {
+	suite.mr = setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.author = model.User{Username: "author", Email: "author@example.com", Password: "hashed_password"}
+	suite.reader = model.User{Username: "reader", Email: "reader@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.author)
+	suite.db.Create(&suite.reader)
+
+	blog := model.Blog{Title: "Analytics", Post: "content", UserID: suite.author.ID, UserName: suite.author.Username}
+	suite.db.Create(&blog)
+	suite.blogID = blog.ID
+}
+
+// app authenticating every request as email
+func (suite *AnalyticsTestSuite) appAs(email string) *fiber.App {
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", email)
+		return c.Next()
+	})
+	deps := testContainer(suite.db)
+	app.Get("/blogs/:id", deps.Blogs.Fetch)
+	app.Get("/me/analytics", deps.Analytics.Mine)
+	return app
+}
+
+// the background jobs on the suite's database
+func (suite *AnalyticsTestSuite) jobs() *jobs.Runner {
+	return testContainer(suite.db).Jobs
+}
+
+func (suite *AnalyticsTestSuite) read(email, userAgent string) {
+	suite.readBlog(suite.blogID, email, userAgent)
+}
+
+func (suite *AnalyticsTestSuite) readBlog(blogID uint, email, userAgent string) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d", blogID), nil)
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := suite.appAs(email).Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *AnalyticsTestSuite) views() int64 {
+	var count int64
+	suite.db.Model(&model.BlogView{}).Where("blog_id = ?", suite.blogID).Count(&count)
+	return count
+}
+
+// Test that a reader is counted once per window
+func (suite *AnalyticsTestSuite) TestViewsAreDeduplicated() {
+	suite.read(suite.reader.Email, browserUserAgent)
+	suite.read(suite.reader.Email, browserUserAgent)
+	assert.Equal(suite.T(), int64(1), suite.views())
+
+	suite.read(suite.author.Email, browserUserAgent)
+	assert.Equal(suite.T(), int64(2), suite.views())
+}
+
+// Test that viewer ids are keyed, not a plain hash of the reader
+func (suite *AnalyticsTestSuite) TestViewerIDsAreKeyed() {
+	controller.InitViews(config.AnalyticsConfig{ViewerSecret: strings.Repeat("s", 32)})
+	suite.read(suite.reader.Email, browserUserAgent)
+
+	var view model.BlogView
+	suite.db.Where("blog_id = ?", suite.blogID).First(&view)
+	plain := sha256.Sum256([]byte(fmt.Sprintf("user:%d", suite.reader.ID)))
+	assert.NotEqual(suite.T(), hex.EncodeToString(plain[:]), view.Viewer)
+	assert.Len(suite.T(), view.Viewer, 64)
+}
+
+// Test that crawlers and scripts are not counted
+func (suite *AnalyticsTestSuite) TestBotsAreIgnored() {
+	suite.read(suite.reader.Email, "Googlebot/2.1 (+http://www.google.com/bot.html)")
+	suite.read(suite.reader.Email, "curl/8.5.0")
+	suite.read(suite.reader.Email, "")
+	assert.Equal(suite.T(), int64(0), suite.views())
+}
+
+// Test that the daily rollup feeds the analytics endpoint, counting a reader
+// of several days and posts once
+func (suite *AnalyticsTestSuite) TestAnalyticsEndpoint() {
+	other := model.Blog{Title: "Another", Post: "content", UserID: suite.author.ID, UserName: suite.author.Username}
+	suite.db.Create(&other)
+
+	// the reader reads the post yesterday, then again today along with the
+	// author and reads the other post too
+	suite.read(suite.reader.Email, browserUserAgent)
+	yesterday := time.Now().AddDate(0, 0, -1)
+	suite.db.Model(&model.BlogView{}).Where("blog_id = ?", suite.blogID).Update("created_at", yesterday)
+	suite.mr.FastForward(time.Hour)
+
+	suite.read(suite.reader.Email, browserUserAgent)
+	suite.read(suite.author.Email, browserUserAgent)
+	suite.readBlog(other.ID, suite.reader.Email, browserUserAgent)
+	suite.db.Create(&model.Like{UserID: suite.reader.ID, BlogID: suite.blogID})
+	suite.db.Create(&model.Comment{Content: "Great", UserID: suite.reader.ID, BlogID: suite.blogID})
+
+	var viewers []string
+	suite.db.Model(&model.BlogView{}).Where("user_id = ?", suite.reader.ID).Distinct().Pluck("viewer", &viewers)
+	assert.Len(suite.T(), viewers, 1, "a reader keeps their id across days and posts")
+
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(yesterday))
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(time.Now()))
+	// rolling up twice must not duplicate rows
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(time.Now()))
+
+	resp, err := suite.appAs(suite.author.Email).Test(httptest.NewRequest(http.MethodGet, "/me/analytics", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Totals controller.AnalyticsTotals `json:"totals"`
+		Posts  []controller.PostAnalytics `json:"posts"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	// the reader and the author
+	assert.Equal(suite.T(), controller.AnalyticsTotals{Views: 4, UniqueReaders: 2, Likes: 1, Comments: 1}, result.Totals)
+	assert.Len(suite.T(), result.Posts, 2)
+	post := result.Posts[0]
+	assert.Equal(suite.T(), suite.blogID, post.ID)
+	assert.Equal(suite.T(), controller.AnalyticsTotals{Views: 3, UniqueReaders: 2, Likes: 1, Comments: 1}, post.Totals)
+	assert.Len(suite.T(), post.Daily, 2)
+	assert.Equal(suite.T(), yesterday.Format(model.DayLayout), post.Daily[0].Day)
+	assert.Equal(suite.T(), int64(1), post.Daily[0].Views)
+	assert.Equal(suite.T(), int64(1), post.Daily[0].UniqueReaders)
+	assert.Equal(suite.T(), int64(2), post.Daily[1].Views)
+	assert.Equal(suite.T(), int64(2), post.Daily[1].UniqueReaders)
+	assert.Equal(suite.T(), int64(1), post.Daily[1].Likes)
+	assert.Equal(suite.T(), controller.AnalyticsTotals{Views: 1, UniqueReaders: 1}, result.Posts[1].Totals)
+}
+
+// Test that rolling a day up again resets the stats of activity deleted since
+func (suite *AnalyticsTestSuite) TestRollupResetsDeletedActivity() {
+	like := model.Like{UserID: suite.reader.ID, BlogID: suite.blogID}
+	suite.db.Create(&like)
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(time.Now()))
+
+	suite.db.Delete(&like)
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(time.Now()))
+
+	var stat model.BlogDailyStat
+	suite.db.Where("blog_id = ?", suite.blogID).First(&stat)
+	assert.Zero(suite.T(), stat.Likes)
+}
+
+// Test that old views are pruned while their daily stats stay
+func (suite *AnalyticsTestSuite) TestOldViewsArePruned() {
+	old := time.Now().AddDate(0, 0, -40)
+	suite.db.Create(&model.BlogView{BlogID: suite.blogID, Viewer: "old", CreatedAt: old})
+	suite.db.Create(&model.BlogView{BlogID: suite.blogID, Viewer: "recent"})
+	assert.Nil(suite.T(), suite.jobs().RollupDailyStats(old))
+
+	assert.Nil(suite.T(), suite.jobs().PruneViews(time.Now().AddDate(0, 0, -30)))
+	assert.Equal(suite.T(), int64(1), suite.views())
+	var stats int64
+	suite.db.Model(&model.BlogDailyStat{}).Where("day = ?", old.Format(model.DayLayout)).Count(&stats)
+	assert.Equal(suite.T(), int64(1), stats)
+}
+
+// Test that only the signed in author's posts are reported
+func (suite *AnalyticsTestSuite) TestAnalyticsOnlyOwnPosts() {
+	resp, _ := suite.appAs(suite.reader.Email).Test(httptest.NewRequest(http.MethodGet, "/me/analytics", nil))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Empty(suite.T(), result["posts"])
+}
+
+// Test invalid date ranges
+func (suite *AnalyticsTestSuite) TestAnalyticsInvalidRange() {
+	app := suite.appAs(suite.author.Email)
+	for _, query := range []string{"from=yesterday", "from=2024-02-01&to=2024-01-01", "from=2020-01-01&to=2024-01-01"} {
+		resp, _ := app.Test(httptest.NewRequest(http.MethodGet, "/me/analytics?"+query, nil))
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+// Run the test suite
+func TestAnalyticsSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsTestSuite))
+}
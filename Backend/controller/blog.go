@@ -1,18 +1,50 @@
 package controller
 
 import (
-	"Gator_blog/database"
+	"Gator_blog/cache"
 	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/ranking"
 	"Gator_blog/redis"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
 	"fmt"
 	"log"
-	"time"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// BlogHandler serves the blog routes
+type BlogHandler struct {
+	blogs     *service.BlogService
+	users     *service.UserService
+	follows   *service.FollowService
+	media     *Uploads
+	analytics *service.AnalyticsService
+	meta      *MetaLoader
+	ranker    *ranking.Ranker
+	store     *redis.Store
+	cache     cache.Cache
+}
+
+func NewBlogHandler(blogs *service.BlogService, users *service.UserService, follows *service.FollowService, media *Uploads, analytics *service.AnalyticsService, meta *MetaLoader, ranker *ranking.Ranker, store *redis.Store, responses cache.Cache) *BlogHandler {
+	return &BlogHandler{blogs: blogs, users: users, follows: follows, media: media, analytics: analytics, meta: meta, ranker: ranker, store: store, cache: responses}
+}
+
+// id in the path parameter name, 0 when it is not a number so lookups miss
+func pathID(c *fiber.Ctx, name string) uint {
+	id, err := strconv.ParseUint(c.Params(name), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
 // Get list of all blogs
-func BlogList(c *fiber.Ctx) error {
+func (h *BlogHandler) List(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Blog List",
@@ -23,20 +55,13 @@ func BlogList(c *fiber.Ctx) error {
 	log.Println("user email", userEmail)
 	if !ok || userEmail == "" {
 		log.Println("User email not found in context")
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 
 	// Find the user by email
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-
-	if result.Error != nil {
-		log.Println("User not found")
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
 
 	// Query parameters (e.g., ?title=example)
@@ -50,46 +75,21 @@ func BlogList(c *fiber.Ctx) error {
 
 	// Retrieve blogs for the user
 	var blogs []model.Blog
-	found, err := redis.GetCache(cacheKey, &blogs)
-	if err != nil {
-		log.Println("Reddis error: ", err)
-	}
-
-	if found { //cache hit
-		log.Println("Cache hit for ", cacheKey)
-		context["blogs"] = blogs
-		c.Status(200)
-		return c.JSON(context)
-
-	}
-	//cache miss
-	log.Println("Cache miss for ", cacheKey)
-	query := database.DBConn.Where("user_id = ?", user.ID)
-
-	if titleFilter != "" {
-		query = query.Where("title LIKE ?", "%"+titleFilter+"%")
-	}
-	result = query.Find(&blogs)
-
-	if result.Error != nil {
-		log.Println("Error fetching blogs", err)
-		context["statusText"] = "error"
-		context["msg"] = "Could not fetch blogs"
-		return c.JSON(context)
-	}
-	//store in redis
-	err = redis.SetCache(cacheKey, blogs, 10*time.Minute)
+	status, err := cache.Remember(h.cache, cacheKey, &blogs, blogCacheOptions, func() (interface{}, []string, error) {
+		blogs, err := h.blogs.List(repository.BlogFilter{UserID: user.ID, Title: titleFilter})
+		return blogs, []string{blogListsTag(user.ID), authorTag(user.ID)}, err
+	})
 	if err != nil {
-		log.Println("Error setting cache", err)
+		return problem.Failed("Could not fetch blogs", err)
 	}
+	c.Set(headerCache, string(status))
 	context["blogs"] = blogs
-	c.Status(200)
-	return c.JSON(context)
+	return sendConditional(c, context, validators{})
 
 }
 
 // Fetches a single blog by ID
-func BlogFetch(c *fiber.Ctx) error {
+func (h *BlogHandler) Fetch(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Fetch Blog",
@@ -99,67 +99,46 @@ func BlogFetch(c *fiber.Ctx) error {
 	userEmail, ok := c.Locals("userEmail").(string)
 	if !ok || userEmail == "" {
 		log.Println("User email not found in context")
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 
 	// Find the user by email
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-	if result.Error != nil {
-		log.Println("User not found")
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.Status(404).JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
 
 	// Get blog ID from params
-	blogID := c.Params("id")
-	if blogID == "" {
-		context["statusText"] = "error"
-		context["msg"] = "Blog ID is required"
-		return c.Status(400).JSON(context)
+	if c.Params("id") == "" {
+		return problem.BadRequest("Blog ID is required")
 	}
+	blogID := pathID(c, "id")
 
-	//setup cachekey
-	cacheKey := fmt.Sprintf("user:%d:blog:%s", user.ID, blogID)
+	// one copy for every reader, dropped through its tags when the blog or
+	// its author changes
+	cacheKey := blogCacheKey(blogID)
 
 	// Retrieve the specific blog
 	var blog model.Blog
-
-	found, err := redis.GetCache(cacheKey, &blog)
-	if err != nil {
-		log.Println("Reddis error: ", err)
-	}
-	if found { //cache hit
-		log.Println("Cache hit for ", cacheKey)
-		context["blog"] = blog
-		return c.Status(200).JSON(context)
-	}
-	//cache miss
-	log.Println("Cache miss for ", cacheKey)
-
-	//result = database.DBConn.Where("id = ? AND user_id = ?", blogID, user.ID).First(&blog)
-	// Sritha
-	result = database.DBConn.Where("id = ?", blogID).First(&blog)
-	if result.Error != nil {
-		log.Println("Blog not found")
-		context["statusText"] = "error"
-		context["msg"] = "Blog not found"
-		return c.Status(404).JSON(context)
-	}
-	err = redis.SetCache(cacheKey, blog, 10*time.Minute)
+	status, err := cache.Remember(h.cache, cacheKey, &blog, blogCacheOptions, func() (interface{}, []string, error) {
+		blog, err := h.blogs.Get(blogID)
+		return blog, []string{blogTag(blog.ID), authorTag(blog.UserID)}, err
+	})
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
 	if err != nil {
-		log.Println("Error setting cache", err)
+		return problem.Failed("Could not fetch blog", err)
 	}
+	c.Set(headerCache, string(status))
+	trackBlogView(h.analytics, h.store, c, user.ID, blog)
 
 	context["blog"] = blog
-	return c.Status(200).JSON(context)
+	return sendConditional(c, context, validators{ETag: versionETag(blog.Version, blog)})
 }
 
 // Adds a blog
-func BlogCreate(c *fiber.Ctx) error {
+func (h *BlogHandler) Create(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Add Blog",
@@ -168,55 +147,73 @@ func BlogCreate(c *fiber.Ctx) error {
 	userEmail, ok := c.Locals("userEmail").(string)
 	if !ok || userEmail == "" {
 		log.Println("User email not found in context")
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 	// Find the user by email
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.Status(404).JSON(context)
-	}
-	// Parse request body
-	var blog model.Blog
-	if err := c.BodyParser(&blog); err != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Invalid input"
-		return c.Status(404).JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
+	// Parse request body, only the fields of createBlogRequest are taken
+	var body createBlogRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
+	}
+	blog := body.blog()
 
-	blog.UserID = user.ID
-	blog.UserName = user.Username
-
-	result = database.DBConn.Create(&blog)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Could not create blog"
-		return c.Status(500).JSON(context)
+	err = h.blogs.Create(user, &blog)
+	if errors.Is(err, service.ErrTooManyTags) {
+		return tooManyTags()
+	}
+	if err != nil {
+		return problem.Failed("Could not create blog", err)
 	}
 	context["msg"] = "Blog created successfully"
 	context["blog"] = blog
+	c.Set(fiber.HeaderETag, versionETag(blog.Version, blog))
+	pushToFollowerTimelines(h.store, h.follows, blog)
+	invalidateFeeds(h.cache)
 
 	// Invalidate cache since we created a new blog
-	cacheKey := fmt.Sprintf("user:%d:blogs", user.ID)
-	redis.DeleteCache(cacheKey)
+	invalidateBlogLists(h.cache, user.ID)
+	return c.Status(201).JSON(context)
+}
 
-	// Invalidate title specific cache entries
-	pattern := fmt.Sprintf("user:%d:blogs:title:*", user.ID)
-	keys, _ := redis.RedisClient.Keys(redis.Ctx, pattern).Result()
-	if len(keys) > 0 {
-		redis.RedisClient.Del(redis.Ctx, keys...)
+// Updated a blog, fields left out of the body keep their value
+func (h *BlogHandler) Update(c *fiber.Ctx) error {
+	var body updateBlogRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
 	}
-	log.Println("Invalidate cache for", cacheKey)
-	log.Println("Invalidate cache for", pattern)
-	return c.Status(201).JSON(context)
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
+	}
+	return h.update(c, body.changes())
+}
+
+// Patches a blog with a JSON Merge Patch (RFC 7396) of its title, post and
+// tags
+func (h *BlogHandler) Patch(c *fiber.Ctx) error {
+	switch c.Get(fiber.HeaderContentType) {
+	case mimeMergePatch, fiber.MIMEApplicationJSON, fiber.MIMEApplicationJSONCharsetUTF8:
+	default:
+		return problem.New(415, problem.UnsupportedMediaType, "Send a JSON Merge Patch as "+mimeMergePatch)
+	}
+	body, err := blogMergePatch(c.Body())
+	if err != nil {
+		return problem.BadRequest("Invalid patch: " + err.Error())
+	}
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
+	}
+	return h.update(c, body.changes())
 }
 
-// Updated a blog
-func BlogUpdate(c *fiber.Ctx) error {
+// applies changes to the blog in the path for the signed in user
+func (h *BlogHandler) update(c *fiber.Ctx, changes service.BlogChanges) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Add Blog",
@@ -225,67 +222,39 @@ func BlogUpdate(c *fiber.Ctx) error {
 	userEmail, ok := c.Locals("userEmail").(string)
 	if !ok || userEmail == "" {
 		log.Println("User email not found in context")
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 	// Find the user by email
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.Status(404).JSON(context)
-	}
-
-	// Get blog ID from params
-	blogID := c.Params("id")
-
-	// Find the blog
-	var blog model.Blog
-	result = database.DBConn.Where("id = ? AND user_id = ?", blogID, user.ID).First(&blog)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Could not fetch blog"
-		return c.Status(404).JSON(context)
-	}
-
-	// Parse request body
-	if err := c.BodyParser(&blog); err != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Invalid input"
-		return c.Status(400).JSON(context)
-	}
-
-	result = database.DBConn.Save(&blog)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Could not update blog"
-		return c.Status(500).JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
+	}
+
+	// The cover is only changed through its own endpoint. With If-Match the
+	// blog must still be at the version it names.
+	blog, err := h.blogs.Update(user.ID, pathID(c, "id"), ifMatchVersion(c), changes)
+	switch {
+	case errors.Is(err, repository.ErrConflict):
+		c.Set(fiber.HeaderETag, versionETag(blog.Version, blog))
+		return problem.New(412, problem.VersionMismatch, "Blog was changed since it was loaded").With("blog", blog)
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.Missing("Could not fetch blog")
+	case errors.Is(err, service.ErrTooManyTags):
+		return tooManyTags()
+	case err != nil:
+		return problem.Failed("Could not update blog", err)
 	}
 	context["msg"] = "Blog updated successfully"
 	context["blog"] = blog
+	c.Set(fiber.HeaderETag, versionETag(blog.Version, blog))
 
-	// Invalidate caches for this specific blog and the blogs list
-	blogCacheKey := fmt.Sprintf("user:%d:blog:%s", user.ID, blogID)
-	listCacheKey := fmt.Sprintf("user:%d:blogs", user.ID)
-
-	redis.DeleteCache(blogCacheKey)
-	redis.DeleteCache(listCacheKey)
-	log.Println("Invalidate cache for", blogCacheKey)
-	log.Println("Invalidate cache for", listCacheKey)
-	// Invalidate title-specific cache entries
-	pattern := fmt.Sprintf("user:%d:blogs:title:*", user.ID)
-	keys, _ := redis.RedisClient.Keys(redis.Ctx, pattern).Result()
-	if len(keys) > 0 {
-		redis.RedisClient.Del(redis.Ctx, keys...)
-	}
-	log.Println("Invalidate cache for", pattern)
+	// Invalidate caches for this specific blog, the blogs lists and feeds
+	invalidateBlog(h.cache, user.ID, blog.ID)
 	return c.Status(200).JSON(context)
 }
 
 // Deletes a blog
-func BlogDelete(c *fiber.Ctx) error {
+func (h *BlogHandler) Delete(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Add Blog",
@@ -294,60 +263,34 @@ func BlogDelete(c *fiber.Ctx) error {
 	userEmail, ok := c.Locals("userEmail").(string)
 	if !ok || userEmail == "" {
 		log.Println("User email not found in context")
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 	// Find the user by email
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.Status(404).JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
 
-	// Get blog ID from params
-	blogID := c.Params("id")
-
-	// Find the blog
-	var blog model.Blog
-	result = database.DBConn.Where("id = ? AND user_id = ?", blogID, user.ID).First(&blog)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Could not fetch blog"
-		return c.Status(404).JSON(context)
+	blog, err := h.blogs.Delete(user.ID, pathID(c, "id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Could not fetch blog")
 	}
-
-	result = database.DBConn.Delete(&blog)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Could not delete blog"
-		return c.Status(500).JSON(context)
+	if err != nil {
+		return problem.Failed("Could not delete blog", err)
 	}
 	context["msg"] = "Blog deleted successfully"
 	context["blog"] = blog
+	h.store.DeleteCounters(blog.ID)
+	deleteMediaByID(h.media, blog.CoverMediaID)
+	removeFromFollowerTimelines(h.store, h.follows, blog)
 
-	// Invalidate caches for this specific blog and the blogs list
-	blogCacheKey := fmt.Sprintf("user:%d:blog:%s", user.ID, blogID)
-	listCacheKey := fmt.Sprintf("user:%d:blogs", user.ID)
-	log.Println("Invalidate cache for", blogCacheKey)
-	log.Println("Invalidate cache for", listCacheKey)
-	redis.DeleteCache(blogCacheKey)
-	redis.DeleteCache(listCacheKey)
-
-	// Invalidate title-specific cache entries
-	pattern := fmt.Sprintf("user:%d:blogs:title:*", user.ID)
-	keys, _ := redis.RedisClient.Keys(redis.Ctx, pattern).Result()
-	if len(keys) > 0 {
-		redis.RedisClient.Del(redis.Ctx, keys...)
-	}
-	log.Println("Invalidate cache for", pattern)
+	// Invalidate caches for this specific blog, the blogs lists and feeds
+	invalidateBlog(h.cache, user.ID, blog.ID)
 	return c.Status(200).JSON(context)
 }
 
 // fetches blogs of a particular user based on user_id
-func BlogListWithMeta(c *fiber.Ctx) error {
+func (h *BlogHandler) ListWithMeta(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "Blogs with Meta",
@@ -355,149 +298,103 @@ func BlogListWithMeta(c *fiber.Ctx) error {
 
 	userEmail, ok := c.Locals("userEmail").(string)
 	if !ok || userEmail == "" {
-		context["statusText"] = "error"
-		context["msg"] = "Unauthorized"
-		return c.Status(401).JSON(context)
+		return problem.Unauthorized("Unauthorized")
 	}
 
-	var user model.User
-	result := database.DBConn.Where("email = ?", userEmail).First(&user)
-	if result.Error != nil {
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.Status(404).JSON(context)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
 
-	searchQuery := c.Query("search")
-	var blogs []model.Blog
-
-	query := database.DBConn.Where("user_id = ?", user.ID)
-
-	if searchQuery != "" {
-		likePattern := "%" + searchQuery + "%"
-		query = query.Where("title LIKE ? OR post LIKE ?", likePattern, likePattern)
+	blogs, err := h.blogs.List(repository.BlogFilter{UserID: user.ID, Search: c.Query("search")})
+	if err != nil {
+		return problem.Failed("Failed to fetch blogs", err)
 	}
 
-	if err := query.Find(&blogs).Error; err != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Failed to fetch blogs"
-		return c.Status(500).JSON(context)
+	enrichedBlogs, err := h.meta.Load(blogs, user.ID)
+	if err != nil {
+		return problem.Failed("Failed to fetch blogs", err)
 	}
 
-	var enrichedBlogs []BlogWithMeta
-	for _, blog := range blogs {
-		var likeCount int64
-		database.DBConn.Model(&model.Like{}).Where("blog_id = ?", blog.ID).Count(&likeCount)
+	context["blogs"] = enrichedBlogs
+	return sendConditional(c, context, validators{Weak: true})
+}
 
-		var comments []model.Comment
-		database.DBConn.Where("blog_id = ?", blog.ID).Find(&comments)
+// fetches blogs of all users
+func (h *BlogHandler) AllWithMeta(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "All Blogs with Meta",
+	}
+	blogs, err := h.blogs.List(repository.BlogFilter{Search: c.Query("search")})
+	if err != nil {
+		return problem.Failed("Failed to fetch blogs", err)
+	}
 
-		enrichedBlogs = append(enrichedBlogs, BlogWithMeta{
-			ID:        blog.ID,
-			Title:     blog.Title,
-			Post:      blog.Post,
-			CreatedAt: blog.CreatedAt,
-			UpdatedAt: blog.UpdatedAt,
-			Likes:     likeCount,
-			Comments:  comments,
-		})
+	// Get likes, comment counts and comment previews for all blogs at once
+	enrichedBlogs, err := h.meta.Load(blogs, optionalUserID(c, h.users))
+	if err != nil {
+		return problem.Failed("Failed to fetch blogs", err)
 	}
 
 	context["blogs"] = enrichedBlogs
-	return c.Status(200).JSON(context)
+	return sendConditional(c, context, validators{Weak: true})
 }
 
-// fetches blogs of all users
-func AllBlogsWithMeta(c *fiber.Ctx) error {
+// default and maximum number of popular blogs returned
+const (
+	defaultPopularLimit = 5
+	maxPopularLimit     = 50
+)
+
+// fetches the hottest blogs, ranked by likes, comments and views decayed
+// with age. Accepts ?window=day|week|month|all and ?limit=N
+func (h *BlogHandler) Popular(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
-		"msg":        "All Blogs with Meta",
+		"msg":        "Popular Blogs",
 	}
-	searchQuery := c.Query("search")
-	var blogs []model.Blog
-	query := database.DBConn
 
-	if searchQuery != "" {
-		likePattern := "%" + searchQuery + "%"
-		query = query.Where("title LIKE ? OR post LIKE ?", likePattern, likePattern)
+	window, err := ranking.ParseWindow(c.Query("window"))
+	if err != nil {
+		return problem.BadRequest("Invalid window")
 	}
-	if err := query.Find(&blogs).Error; err != nil {
-		context["statusText"] = "error"
-		context["msg"] = "Failed to fetch blogs"
-		return c.Status(500).JSON(context)
+	limit := c.QueryInt("limit", defaultPopularLimit)
+	if limit < 1 || limit > maxPopularLimit {
+		return problem.BadRequest("Invalid limit")
 	}
 
-	var enrichedBlogs []BlogWithMeta
-	for _, blog := range blogs {
-		// Get likes count
-		var likeCount int64
-		database.DBConn.Model(&model.Like{}).Where("blog_id = ?", blog.ID).Count(&likeCount)
+	// Step 1: Get the ids of the hottest blogs from the leaderboard
+	ids, err := h.ranker.Top(window, limit)
+	if err != nil {
+		return problem.Failed("Failed to fetch top blogs", err)
+	}
 
-		// Get comments
-		var comments []model.Comment
-		database.DBConn.Where("blog_id = ?", blog.ID).Find(&comments)
+	// Step 2: Fetch those blogs in one query and restore the ranking order
+	blogs, err := h.blogs.Ranked(ids)
+	if err != nil {
+		return problem.Failed("Failed to fetch top blogs", err)
+	}
 
-		enrichedBlogs = append(enrichedBlogs, BlogWithMeta{
-			ID:        blog.ID,
-			Title:     blog.Title,
-			Post:      blog.Post,
-			UserID:    blog.UserID,
-			UserName:  blog.UserName,
-			CreatedAt: blog.CreatedAt,
-			UpdatedAt: blog.UpdatedAt,
-			Likes:     likeCount,
-			Comments:  comments,
-		})
+	popularblogs, err := h.meta.Load(blogs, optionalUserID(c, h.users))
+	if err != nil {
+		return problem.Failed("Failed to fetch top blogs", err)
 	}
+	context["blogs"] = popularblogs
+	return sendConditional(c, context, validators{Weak: true})
+}
 
-	context["blogs"] = enrichedBlogs
-	return c.Status(200).JSON(context)
+// 404 when the signed in user no longer exists, 500 when they could not be
+// looked up
+func userProblem(err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	return problem.Failed("Could not fetch user", err)
 }
 
-// fetches popular blogs based on likes
-func Top5PopularBlogs(c *fiber.Ctx) error {
-	context := fiber.Map{
-		"statusText": "OK",
-		"msg":        "Top 5 Popular Blogs",
-	}
-
-	type Result struct {
-		BlogID uint
-		Count  int64
-	}
-
-	var results []Result
-	// Step 1: Get top 5 blog IDs by like count
-	if err := database.DBConn.
-		Model(&model.Like{}).
-		Select("blog_id as blog_id, COUNT(*) as count").
-		Group("blog_id").
-		Order("count DESC").
-		Limit(5).
-		Scan(&results).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch top blogs"})
-	}
-	// log.Println("results---", results)
-	var popularblogs []BlogWithMeta
-	for _, res := range results {
-		var blog model.Blog
-		if err := database.DBConn.Where("id = ?", res.BlogID).Find(&blog).Error; err != nil {
-			continue
-		}
-
-		popularblogs = append(popularblogs, BlogWithMeta{
-			ID:        blog.ID,
-			Title:     blog.Title,
-			Post:      blog.Post,
-			UserID:    blog.UserID,
-			UserName:  blog.UserName,
-			CreatedAt: blog.CreatedAt,
-			UpdatedAt: blog.UpdatedAt,
-			Likes:     res.Count,
-		})
-		// log.Println("popular blogs", popularblogs)
-	}
-	// log.Println("popular blogs---", popularblogs)
-	context["blogs"] = popularblogs
-	return c.Status(200).JSON(context)
+// the answer to a blog with more distinct tags than it may have
+func tooManyTags() error {
+	return problem.Invalid(validate.Errors{{Field: "tags", Rule: "max",
+		Message: fmt.Sprintf("tags must be at most %d items", service.MaxBlogTags)}})
 }
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/redis"
+	"Gator_blog/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// number of most recent comments embedded in each BlogWithMeta
+const commentPreviewLimit = 3
+
+// MetaLoader enriches blog listings for the handlers that serve them
+type MetaLoader struct {
+	likes     *service.LikeService
+	comments  *service.CommentService
+	bookmarks *service.BookmarkService
+	media     *Uploads
+	store     *redis.Store
+}
+
+func NewMetaLoader(likes *service.LikeService, comments *service.CommentService, bookmarks *service.BookmarkService, media *Uploads, store *redis.Store) *MetaLoader {
+	return &MetaLoader{likes: likes, comments: comments, bookmarks: bookmarks, media: media, store: store}
+}
+
+// Load enriches blogs with their like and comment counts, a preview of their
+// latest comments, their cover image and whether viewerID bookmarked them (0
+// for anonymous viewers). The number of queries does not depend on
+// len(blogs): one batched counter lookup per count, one comment, one cover
+// and one bookmark fetch.
+func (m *MetaLoader) Load(blogs []model.Blog, viewerID uint) ([]BlogWithMeta, error) {
+	enriched := make([]BlogWithMeta, 0, len(blogs))
+	if len(blogs) == 0 {
+		return enriched, nil
+	}
+
+	ids := make([]uint, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+
+	likes, err := blogCounts(m.store, ids, redis.CounterLikes, m.likes.CountByBlog)
+	if err != nil {
+		return nil, err
+	}
+	commentCounts, err := blogCounts(m.store, ids, redis.CounterComments, m.comments.CountByBlog)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := m.comments.Latest(ids, commentPreviewLimit)
+	if err != nil {
+		return nil, err
+	}
+	previews := make(map[uint][]model.Comment, len(ids))
+	for _, comment := range latest {
+		previews[comment.BlogID] = append(previews[comment.BlogID], comment)
+	}
+	bookmarked, err := m.bookmarks.Bookmarked(viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+	covers, err := coverImages(m.media, blogs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, blog := range blogs {
+		comments := previews[blog.ID]
+		if comments == nil {
+			comments = []model.Comment{}
+		}
+		enriched = append(enriched, BlogWithMeta{
+			ID:             blog.ID,
+			Title:          blog.Title,
+			Post:           blog.Post,
+			UserID:         blog.UserID,
+			UserName:       blog.UserName,
+			CreatedAt:      blog.CreatedAt,
+			UpdatedAt:      blog.UpdatedAt,
+			Likes:          likes[blog.ID],
+			CommentsCount:  commentCounts[blog.ID],
+			Comments:       comments,
+			BookmarkedByMe: bookmarked[blog.ID],
+			CoverImage:     covers[blog.ID],
+		})
+	}
+	return enriched, nil
+}
+
+// id of the signed in user on routes where signing in is optional, 0 otherwise
+func optionalUserID(c *fiber.Ctx, users *service.UserService) uint {
+	userEmail, ok := c.Locals("userEmail").(string)
+	if !ok || userEmail == "" {
+		return 0
+	}
+	user, err := users.ByEmail(userEmail)
+	if err != nil {
+		return 0
+	}
+	return user.ID
+}
@@ -0,0 +1,137 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// counts every statement gorm sends to the database
+func countQueries(db *gorm.DB) *int64 {
+	var n int64
+	inc := func(*gorm.DB) { atomic.AddInt64(&n, 1) }
+	db.Callback().Query().After("gorm:query").Register("test:count_query", inc)
+	db.Callback().Row().After("gorm:row").Register("test:count_row", inc)
+	db.Callback().Raw().After("gorm:raw").Register("test:count_raw", inc)
+	return &n
+}
+
+// seeds an author with size blogs, each liked once and commented on five times
+func seedBlogsWithMeta(db *gorm.DB, size int) {
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	for i := 0; i < size; i++ {
+		blog := model.Blog{Title: fmt.Sprintf("Blog %d", i), Post: "content", UserID: user.ID, UserName: user.Username}
+		db.Create(&blog)
+		db.Create(&model.Like{UserID: user.ID, BlogID: blog.ID})
+		for j := 0; j < 5; j++ {
+			db.Create(&model.Comment{Content: fmt.Sprintf("Comment %d", j), UserID: user.ID, UserName: user.Username, BlogID: blog.ID})
+		}
+	}
+}
+
+func metaApp(db *gorm.DB) *fiber.App {
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "test@example.com")
+		return c.Next()
+	})
+	app.Get("/all-blogs-with-meta", deps.Blogs.AllWithMeta)
+	app.Get("/blogs-with-meta", deps.Blogs.ListWithMeta)
+	app.Get("/top-popular-blogs", deps.Blogs.Popular)
+	return app
+}
+
+// issues one request and returns how many queries it took
+func queriesFor(t testing.TB, app *fiber.App, queries *int64, url string) int64 {
+	atomic.StoreInt64(queries, 0)
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode, "a failing request is not worth counting")
+	return atomic.LoadInt64(queries)
+}
+
+func TestBlogWithMetaQueryCountIsConstant(t *testing.T) {
+	for _, url := range []string{"/all-blogs-with-meta", "/blogs-with-meta", "/top-popular-blogs"} {
+		t.Run(url, func(t *testing.T) {
+			var counts []int64
+			for _, size := range []int{1, 10, 50} {
+				t.Run(fmt.Sprint(size), func(t *testing.T) {
+					db := openTestDB(t)
+					seedBlogsWithMeta(db, size)
+					queries := countQueries(db)
+					counts = append(counts, queriesFor(t, metaApp(db), queries, url))
+				})
+			}
+			for _, n := range counts[1:] {
+				assert.Equal(t, counts[0], n, "query count grew with result size")
+			}
+		})
+	}
+}
+
+func TestAllBlogsWithMetaCommentPreview(t *testing.T) {
+	db := openTestDB(t)
+	seedBlogsWithMeta(db, 2)
+
+	resp, err := metaApp(db).Test(httptest.NewRequest(http.MethodGet, "/all-blogs-with-meta", nil))
+	assert.NoError(t, err)
+
+	var result struct {
+		Blogs []controller.BlogWithMeta `json:"blogs"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Len(t, result.Blogs, 2)
+	for _, blog := range result.Blogs {
+		assert.Equal(t, int64(1), blog.Likes)
+		assert.Equal(t, int64(5), blog.CommentsCount)
+		assert.Len(t, blog.Comments, 3)
+		// latest comments, oldest first
+		assert.Equal(t, "Comment 2", blog.Comments[0].Content)
+		assert.Equal(t, "Comment 4", blog.Comments[2].Content)
+	}
+}
+
+func TestAllBlogsWithMetaEmptyComments(t *testing.T) {
+	db := openTestDB(t)
+	db.Create(&model.Blog{Title: "Quiet", Post: "nobody commented", UserID: 1, UserName: "author"})
+
+	resp, _ := metaApp(db).Test(httptest.NewRequest(http.MethodGet, "/all-blogs-with-meta", nil))
+
+	var result map[string][]map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Len(t, result["blogs"], 1)
+	assert.Equal(t, []interface{}{}, result["blogs"][0]["comments"])
+}
+
+func benchmarkBlogsWithMeta(b *testing.B, size int) {
+	// every table MetaLoader reads, as in the tests
+	db := openTestDB(b)
+	seedBlogsWithMeta(db, size)
+
+	app := metaApp(db)
+	queries := countQueries(db)
+	b.ResetTimer()
+	var total int64
+	for i := 0; i < b.N; i++ {
+		total += queriesFor(b, app, queries, "/all-blogs-with-meta")
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "queries/op")
+}
+
+func BenchmarkAllBlogsWithMeta10(b *testing.B)   { benchmarkBlogsWithMeta(b, 10) }
+func BenchmarkAllBlogsWithMeta100(b *testing.B)  { benchmarkBlogsWithMeta(b, 100) }
+func BenchmarkAllBlogsWithMeta1000(b *testing.B) { benchmarkBlogsWithMeta(b, 1000) }
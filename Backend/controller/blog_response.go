@@ -6,13 +6,24 @@ import (
 )
 
 type BlogWithMeta struct {
-	ID        uint            `json:"id"`
-	Title     string          `json:"title"`
-	Post      string          `json:"post"`
-	UserID    uint            `json:"user_id"`
-	UserName  string          `json:"user_name"`
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
-	Likes     int64           `json:"likes"`
-	Comments  []model.Comment `json:"comments"`
+	ID             uint            `json:"id"`
+	Title          string          `json:"title"`
+	Post           string          `json:"post"`
+	UserID         uint            `json:"user_id"`
+	UserName       string          `json:"user_name"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Likes          int64           `json:"likes"`
+	CommentsCount  int64           `json:"comments_count"`
+	Comments       []model.Comment `json:"comments"` // most recent comments only, see commentPreviewLimit
+	BookmarkedByMe bool            `json:"bookmarked_by_me"`
+	CoverImage     *CoverImage     `json:"cover_image"` // nil without a cover
+}
+
+// cover image of a blog, cropped to 1200x630 with a 600x315 copy
+type CoverImage struct {
+	URL      string `json:"url"`
+	SmallURL string `json:"small_url"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
 }
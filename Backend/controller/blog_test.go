@@ -1,10 +1,11 @@
 package controller_test
 
 import (
-	"Gator_blog/controller"
-	"Gator_blog/database"
+	"Gator_blog/cache"
+	"Gator_blog/config"
+	"Gator_blog/container"
+	"Gator_blog/database/dbtest"
 	"Gator_blog/model"
-	"Gator_blog/redis"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -15,7 +16,6 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -24,28 +24,21 @@ type BlogTestSuite struct {
 	suite.Suite
 	app    *fiber.App
 	db     *gorm.DB
+	deps   *container.Container
 	userID uint
 	token  string
 }
 
 // Setup before each test
 func (suite *BlogTestSuite) SetupTest() {
-	// Create an in-memory SQLite database for testing
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		suite.T().Fatal("Failed to connect to test database:", err)
-	}
-	db.AutoMigrate(&model.User{}, &model.Blog{})
-	database.DBConn = db
-	suite.db = db
-
-	// Initialize Redis and flush any existing keys to start with a clean state
-	redis.InitRedis()
-	if err := redis.RedisClient.FlushDB(redis.Ctx).Err(); err != nil {
-		suite.T().Fatal("Failed to flush Redis DB:", err)
-	}
+	// Create an empty database for testing
+	suite.db = dbtest.Open(suite.T(), &model.User{}, &model.Blog{})
 
-	app := fiber.New()
+	// Cache in memory as the test profile does, starting empty
+	cache.InitCache(config.Defaults(config.Test).Cache, nil)
+	suite.deps = testContainer(suite.db)
+
+	app := newApp()
 
 	// Create a middleware to set the user email in locals for testing
 	app.Use(func(c *fiber.Ctx) error {
@@ -56,11 +49,11 @@ func (suite *BlogTestSuite) SetupTest() {
 	})
 
 	// Setup all routes
-	app.Get("/blogs", controller.BlogList)
-	app.Get("/blogs/:id", controller.BlogFetch)
-	app.Post("/blogs", controller.BlogCreate)
-	app.Put("/blogs/:id", controller.BlogUpdate)
-	app.Delete("/blogs/:id", controller.BlogDelete)
+	app.Get("/blogs", suite.deps.Blogs.List)
+	app.Get("/blogs/:id", suite.deps.Blogs.Fetch)
+	app.Post("/blogs", suite.deps.Blogs.Create)
+	app.Put("/blogs/:id", suite.deps.Blogs.Update)
+	app.Delete("/blogs/:id", suite.deps.Blogs.Delete)
 
 	suite.app = app
 
@@ -79,8 +72,7 @@ func (suite *BlogTestSuite) SetupTest() {
 func (suite *BlogTestSuite) TearDownTest() {
 	sqlDB, _ := suite.db.DB()
 	sqlDB.Close()
-	// Flush Redis DB to clear any cached data between tests
-	redis.RedisClient.FlushDB(redis.Ctx)
+	cache.Default = cache.None{}
 }
 
 // Test fetching blogs when user is not authenticated
@@ -91,13 +83,13 @@ func (suite *BlogTestSuite) TestBlogListUnauthorized() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Unauthorized", result["msg"])
+	assert.Equal(suite.T(), "unauthenticated", result["code"])
+	assert.Equal(suite.T(), "Unauthorized", result["detail"])
 }
 
 // Test fetching blogs for a user with no blogs
@@ -150,24 +142,24 @@ func (suite *BlogTestSuite) TestBlogListWithBlogs() {
 
 // Test fetching blogs for a user with non-existent email
 func (suite *BlogTestSuite) TestBlogListUserNotFound() {
-	suite.app = fiber.New()
+	suite.app = newApp()
 	suite.app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "nonexistent@example.com")
 		return c.Next()
 	})
-	suite.app.Get("/blogs", controller.BlogList)
+	suite.app.Get("/blogs", suite.deps.Blogs.List)
 	req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 // Test with database error when fetching blogs
@@ -175,21 +167,24 @@ func (suite *BlogTestSuite) TestBlogListDatabaseError() {
 	blog := model.Blog{Title: "Test Blog", Post: "Post content", UserID: suite.userID}
 	suite.db.Create(&blog)
 	tx := suite.db.Begin()
-	originalDB := database.DBConn
-	database.DBConn = tx
 	tx.Rollback()
+	suite.app = newApp()
+	suite.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "test@example.com")
+		return c.Next()
+	})
+	suite.app.Get("/blogs", testContainer(tx).Blogs.List)
 	req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	database.DBConn = originalDB
+	assert.Equal(suite.T(), "internal", result["code"])
 }
 
 // Test blogs belonging to different users are properly segregated
@@ -281,12 +276,12 @@ func (suite *BlogTestSuite) TestBlogFetchSuccess() {
 	suite.db.Create(&blog)
 
 	// Update the app to include the BlogFetch route
-	app := fiber.New()
+	app := newApp()
 	app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "test@example.com")
 		return c.Next()
 	})
-	app.Get("/blogs/:id", controller.BlogFetch)
+	app.Get("/blogs/:id", suite.deps.Blogs.Fetch)
 	suite.app = app
 
 	url := fmt.Sprintf("/blogs/%d", blog.ID)
@@ -312,6 +307,11 @@ func (suite *BlogTestSuite) TestBlogFetchSuccess() {
 	// Verify timestamp fields are present
 	assert.Contains(suite.T(), blogResult, "created_at")
 	assert.Contains(suite.T(), blogResult, "updated_at")
+
+	// The first fetch loaded the blog, the next one is served from the cache
+	assert.Equal(suite.T(), "MISS", resp.Header.Get("X-Cache"))
+	resp, _ = suite.app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Equal(suite.T(), "HIT", resp.Header.Get("X-Cache"))
 }
 
 // Test fetching a blog when user is not authenticated
@@ -325,12 +325,12 @@ func (suite *BlogTestSuite) TestBlogFetchUnauthorized() {
 	suite.db.Create(&blog)
 
 	// Set up app with no authentication
-	app := fiber.New()
+	app := newApp()
 	app.Use(func(c *fiber.Ctx) error {
 		// Not setting userEmail simulates no authentication
 		return c.Next()
 	})
-	app.Get("/blogs/:id", controller.BlogFetch)
+	app.Get("/blogs/:id", suite.deps.Blogs.Fetch)
 	suite.app = app
 
 	url := fmt.Sprintf("/blogs/%d", blog.ID)
@@ -339,24 +339,24 @@ func (suite *BlogTestSuite) TestBlogFetchUnauthorized() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Unauthorized", result["msg"])
+	assert.Equal(suite.T(), "unauthenticated", result["code"])
+	assert.Equal(suite.T(), "Unauthorized", result["detail"])
 }
 
 // Test fetching a non-existent blog
 func (suite *BlogTestSuite) TestBlogFetchNonExistent() {
 	// Update the app to include the BlogFetch route
-	app := fiber.New()
+	app := newApp()
 	app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "test@example.com")
 		return c.Next()
 	})
-	app.Get("/blogs/:id", controller.BlogFetch)
+	app.Get("/blogs/:id", suite.deps.Blogs.Fetch)
 	suite.app = app
 
 	req := httptest.NewRequest(http.MethodGet, "/blogs/9999", nil)
@@ -369,19 +369,19 @@ func (suite *BlogTestSuite) TestBlogFetchNonExistent() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Blog not found", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "Blog not found", result["detail"])
 }
 
 // Test fetching a blog with missing ID parameter
 func (suite *BlogTestSuite) TestBlogFetchMissingIDParam() {
 	// Update the app to include the BlogFetch route
-	app := fiber.New()
+	app := newApp()
 	app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "test@example.com")
 		return c.Next()
 	})
-	app.Get("/blogs/:id?", controller.BlogFetch) // Make ID optional for test
+	app.Get("/blogs/:id?", suite.deps.Blogs.Fetch) // Make ID optional for test
 	suite.app = app
 
 	req := httptest.NewRequest(http.MethodGet, "/blogs/", nil) // No ID
@@ -394,12 +394,12 @@ func (suite *BlogTestSuite) TestBlogFetchMissingIDParam() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Blog ID is required", result["msg"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
+	assert.Equal(suite.T(), "Blog ID is required", result["detail"])
 }
 
 // Tests for BlogFetch function
-// Test fetching another user's blog
+// Test fetching another user's blog, every signed in reader may
 func (suite *BlogTestSuite) TestBlogFetchOtherUserBlog() {
 	// Create another user
 	otherUser := model.User{
@@ -417,32 +417,70 @@ func (suite *BlogTestSuite) TestBlogFetchOtherUserBlog() {
 	}
 	suite.db.Create(&blog)
 
-	// Update the app to include the BlogFetch route
-	app := fiber.New()
-	app.Use(func(c *fiber.Ctx) error {
-		c.Locals("userEmail", "test@example.com") // Current user
-		return c.Next()
-	})
-	app.Get("/blogs/:id", controller.BlogFetch)
-	suite.app = app
-
-	// Try to fetch the other user's blog
+	// Fetch the other user's blog
 	url := fmt.Sprintf("/blogs/%d", blog.ID)
 	req := httptest.NewRequest(http.MethodGet, url, nil)
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Blog not found", result["msg"])
+	assert.Equal(suite.T(), "OK", result["statusText"])
+	blogResult := result["blog"].(map[string]interface{})
+	assert.Equal(suite.T(), "Other User Blog", blogResult["Title"])
+}
+
+// Test that an update by the author reaches readers who have the blog cached
+func (suite *BlogTestSuite) TestBlogFetchAfterAuthorUpdate() {
+	reader := model.User{
+		Username: "reader",
+		Email:    "reader@example.com",
+		Password: "hashed_password",
+	}
+	suite.db.Create(&reader)
+
+	blog := model.Blog{
+		Title:  "Original Title",
+		Post:   "Original Content",
+		UserID: suite.userID,
+	}
+	suite.db.Create(&blog)
+
+	readerApp := newApp()
+	readerApp.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "reader@example.com")
+		return c.Next()
+	})
+	readerApp.Get("/blogs/:id", suite.deps.Blogs.Fetch)
+
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+	fetchTitle := func(app *fiber.App) interface{} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Nil(suite.T(), err)
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result["blog"].(map[string]interface{})["Title"]
+	}
+
+	// both the reader and the author have it cached
+	assert.Equal(suite.T(), "Original Title", fetchTitle(readerApp))
+	assert.Equal(suite.T(), "Original Title", fetchTitle(suite.app))
+
+	jsonData, _ := json.Marshal(map[string]interface{}{"title": "Updated Title"})
+	req := httptest.NewRequest(http.MethodPut, url, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	assert.Equal(suite.T(), "Updated Title", fetchTitle(readerApp))
+	assert.Equal(suite.T(), "Updated Title", fetchTitle(suite.app))
 }
 
-// Test fetching a blog with non-existent user
 func (suite *BlogTestSuite) TestBlogFetchUserNotFound() {
 	// Create a blog
 	blog := model.Blog{
@@ -453,12 +491,12 @@ func (suite *BlogTestSuite) TestBlogFetchUserNotFound() {
 	suite.db.Create(&blog)
 
 	// Update the app with non-existent user email
-	app := fiber.New()
+	app := newApp()
 	app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "nonexistent@example.com")
 		return c.Next()
 	})
-	app.Get("/blogs/:id", controller.BlogFetch)
+	app.Get("/blogs/:id", suite.deps.Blogs.Fetch)
 	suite.app = app
 
 	url := fmt.Sprintf("/blogs/%d", blog.ID)
@@ -472,8 +510,8 @@ func (suite *BlogTestSuite) TestBlogFetchUserNotFound() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 // Tests for BlogCreate function
@@ -534,8 +572,8 @@ func (suite *BlogTestSuite) TestBlogCreateUnauthorized() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Unauthorized", result["msg"])
+	assert.Equal(suite.T(), "unauthenticated", result["code"])
+	assert.Equal(suite.T(), "Unauthorized", result["detail"])
 }
 
 // Test creating a blog with invalid input
@@ -546,23 +584,23 @@ func (suite *BlogTestSuite) TestBlogCreateInvalidInput() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Invalid input", result["msg"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
+	assert.Equal(suite.T(), "Invalid input", result["detail"])
 }
 
 // Test creating a blog with non-existent user
 func (suite *BlogTestSuite) TestBlogCreateUserNotFound() {
-	suite.app = fiber.New()
+	suite.app = newApp()
 	suite.app.Use(func(c *fiber.Ctx) error {
 		c.Locals("userEmail", "nonexistent@example.com")
 		return c.Next()
 	})
-	suite.app.Post("/blogs", controller.BlogCreate)
+	suite.app.Post("/blogs", suite.deps.Blogs.Create)
 
 	blogData := map[string]interface{}{
 		"title": "New Test Blog",
@@ -580,8 +618,8 @@ func (suite *BlogTestSuite) TestBlogCreateUserNotFound() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 // Tests for BlogUpdate function
@@ -654,8 +692,8 @@ func (suite *BlogTestSuite) TestBlogUpdateNonExistent() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Could not fetch blog", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "Could not fetch blog", result["detail"])
 }
 
 // Test updating a blog with invalid input
@@ -680,8 +718,8 @@ func (suite *BlogTestSuite) TestBlogUpdateInvalidInput() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Invalid input", result["msg"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
+	assert.Equal(suite.T(), "Invalid input", result["detail"])
 }
 
 // Test updating another user's blog
@@ -720,8 +758,8 @@ func (suite *BlogTestSuite) TestBlogUpdateOtherUserBlog() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Could not fetch blog", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "Could not fetch blog", result["detail"])
 
 	// Verify that the blog remains unchanged
 	var unchangedBlog model.Blog
@@ -771,8 +809,8 @@ func (suite *BlogTestSuite) TestBlogDeleteNonExistent() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Could not fetch blog", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "Could not fetch blog", result["detail"])
 }
 
 // Test deleting another user's blog
@@ -804,8 +842,8 @@ func (suite *BlogTestSuite) TestBlogDeleteOtherUserBlog() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Could not fetch blog", result["msg"])
+	assert.Equal(suite.T(), "not_found", result["code"])
+	assert.Equal(suite.T(), "Could not fetch blog", result["detail"])
 
 	// Verify that the blog still exists
 	var count int64
@@ -833,8 +871,8 @@ func (suite *BlogTestSuite) TestBlogDeleteUnauthorized() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Unauthorized", result["msg"])
+	assert.Equal(suite.T(), "unauthenticated", result["code"])
+	assert.Equal(suite.T(), "Unauthorized", result["detail"])
 
 	// Verify blog still exists
 	var count int64
@@ -0,0 +1,347 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BookmarkHandler serves the bookmarks of the signed in user
+type BookmarkHandler struct {
+	bookmarks *service.BookmarkService
+	users     *service.UserService
+	meta      *MetaLoader
+}
+
+func NewBookmarkHandler(bookmarks *service.BookmarkService, users *service.UserService, meta *MetaLoader) *BookmarkHandler {
+	return &BookmarkHandler{bookmarks: bookmarks, users: users, meta: meta}
+}
+
+// Bookmarks a blog for the signed in user
+func (h *BookmarkHandler) Add(c *fiber.Ctx) error {
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	bookmark, created, err := h.bookmarks.Add(user.ID, pathID(c, "id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
+	if err != nil {
+		return problem.Failed("Failed to bookmark blog", err)
+	}
+	if !created {
+		return c.Status(200).JSON(bookmark)
+	}
+	return c.Status(201).JSON(bookmark)
+}
+
+// Removes the signed in user's bookmark on a blog
+func (h *BookmarkHandler) Remove(c *fiber.Ctx) error {
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	removed, err := h.bookmarks.Remove(user.ID, pathID(c, "id"))
+	if err != nil {
+		return problem.Failed("Failed to remove bookmark", err)
+	}
+	if !removed {
+		return problem.Missing("Bookmark not found")
+	}
+	return c.Status(200).JSON(fiber.Map{"msg": "Bookmark removed successfully"})
+}
+
+// Lists the signed in user's bookmarked blogs, most recently bookmarked first
+func (h *BookmarkHandler) List(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Bookmarks",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	blogs, err := h.bookmarks.Blogs(user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch bookmarks", err)
+	}
+	enriched, err := h.meta.Load(blogs, user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch bookmarks", err)
+	}
+	context["blogs"] = enriched
+	return c.Status(200).JSON(context)
+}
+
+// ReadingListHandler serves the reading lists of the signed in user and the
+// shared ones
+type ReadingListHandler struct {
+	lists *service.ReadingListService
+	users *service.UserService
+	meta  *MetaLoader
+}
+
+func NewReadingListHandler(lists *service.ReadingListService, users *service.UserService, meta *MetaLoader) *ReadingListHandler {
+	return &ReadingListHandler{lists: lists, users: users, meta: meta}
+}
+
+// Lists the signed in user's reading lists
+func (h *ReadingListHandler) List(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading Lists",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	lists, err := h.lists.ByUser(user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch reading lists", err)
+	}
+	context["reading_lists"] = lists
+	return c.Status(200).JSON(context)
+}
+
+// Creates a reading list from {"name", "public"}
+func (h *ReadingListHandler) Create(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading list created successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	var input createReadingListRequest
+	if err := c.BodyParser(&input); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(input); err != nil {
+		return problem.Invalid(err)
+	}
+	list, err := h.lists.Create(user.ID, *input.Name, input.Public != nil && *input.Public)
+	if errors.Is(err, service.ErrTooManyLists) {
+		return problem.BadRequest("Too many reading lists")
+	}
+	if err != nil {
+		return problem.Failed("Could not create reading list", err)
+	}
+	context["reading_list"] = list
+	context["share_url"] = shareURL(c, list)
+	return c.Status(201).JSON(context)
+}
+
+// Renames a reading list or changes whether it is shared. Making a list
+// private revokes its share URL, sharing it again issues a new one.
+func (h *ReadingListHandler) Update(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading list updated successfully",
+	}
+	list, _, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+
+	var input updateReadingListRequest
+	if err := c.BodyParser(&input); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(input); err != nil {
+		return problem.Invalid(err)
+	}
+	if err := h.lists.Update(&list, input.Name, input.Public); err != nil {
+		return problem.Failed("Could not update reading list", err)
+	}
+	context["reading_list"] = list
+	context["share_url"] = shareURL(c, list)
+	return c.Status(200).JSON(context)
+}
+
+// Deletes a reading list and its items
+func (h *ReadingListHandler) Delete(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading list deleted successfully",
+	}
+	list, _, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+	if err := h.lists.Delete(&list); err != nil {
+		return problem.Failed("Could not delete reading list", err)
+	}
+	return c.Status(200).JSON(context)
+}
+
+// Returns one of the signed in user's reading lists with its blogs in order
+func (h *ReadingListHandler) Get(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading List",
+	}
+	list, user, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+	return h.render(c, context, list, user.ID)
+}
+
+// Returns a public reading list by its share token, no sign in required
+func (h *ReadingListHandler) Shared(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading List",
+	}
+	list, err := h.lists.Shared(c.Params("token"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Reading list not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch reading list", err)
+	}
+	return h.render(c, context, list, optionalUserID(c, h.users))
+}
+
+// Adds a blog to a reading list from {"blog_id"}, at the end of the list
+func (h *ReadingListHandler) AddItem(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Blog added to reading list",
+	}
+	list, _, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+
+	var input readingListItemRequest
+	if err := c.BodyParser(&input); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(input); err != nil {
+		return problem.Invalid(err)
+	}
+	item, created, err := h.lists.Add(list, input.BlogID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not add blog to reading list", err)
+	}
+	context["item"] = item
+	if !created {
+		return c.Status(200).JSON(context)
+	}
+	return c.Status(201).JSON(context)
+}
+
+// Removes a blog from a reading list
+func (h *ReadingListHandler) RemoveItem(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Blog removed from reading list",
+	}
+	list, _, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+
+	removed, err := h.lists.Remove(list, pathID(c, "blogId"))
+	if err != nil {
+		return problem.Failed("Could not remove blog from reading list", err)
+	}
+	if !removed {
+		return problem.Missing("Blog not in reading list")
+	}
+	return c.Status(200).JSON(context)
+}
+
+// Reorders a reading list from {"blog_ids": [...]}, which must name every
+// blog in the list exactly once
+func (h *ReadingListHandler) Reorder(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Reading list reordered successfully",
+	}
+	list, user, err := h.ownReadingList(c)
+	if err != nil {
+		return err
+	}
+
+	var input reorderReadingListRequest
+	if err := c.BodyParser(&input); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	err = h.lists.Reorder(list, input.BlogIDs)
+	if errors.Is(err, service.ErrInvalidOrder) {
+		return problem.BadRequest("blog_ids must list every blog in the reading list once")
+	}
+	if err != nil {
+		return problem.Failed("Could not reorder reading list", err)
+	}
+	return h.render(c, context, list, user.ID)
+}
+
+// looks up the signed in user and the reading list in the path they own
+func (h *ReadingListHandler) ownReadingList(c *fiber.Ctx) (model.ReadingList, model.User, error) {
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return model.ReadingList{}, user, err
+	}
+	list, err := h.lists.Owned(pathID(c, "listId"), user.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return list, user, problem.Missing("Reading list not found")
+	}
+	if err != nil {
+		return list, user, problem.Failed("Could not fetch reading list", err)
+	}
+	return list, user, nil
+}
+
+// writes a reading list and its blogs in list order
+func (h *ReadingListHandler) render(c *fiber.Ctx, context fiber.Map, list model.ReadingList, viewerID uint) error {
+	blogs, err := h.lists.Blogs(list.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch reading list", err)
+	}
+	enriched, err := h.meta.Load(blogs, viewerID)
+	if err != nil {
+		return problem.Failed("Could not fetch reading list", err)
+	}
+	context["reading_list"] = list
+	context["share_url"] = shareURL(c, list)
+	context["blogs"] = enriched
+	return c.Status(200).JSON(context)
+}
+
+// looks up the signed in user
+func signedInUser(c *fiber.Ctx, users *service.UserService) (model.User, error) {
+	userEmail, ok := c.Locals("userEmail").(string)
+	if !ok || userEmail == "" {
+		return model.User{}, problem.Unauthorized("Unauthorized")
+	}
+	user, err := users.ByEmail(userEmail)
+	if err != nil {
+		return user, userProblem(err)
+	}
+	return user, nil
+}
+
+// path of the public page of a shared list in the API version serving c,
+// empty when the list is private
+func shareURL(c *fiber.Ctx, list model.ReadingList) string {
+	if !list.Public || list.ShareToken == nil {
+		return ""
+	}
+	return apiPrefix(c) + "/reading-lists/shared/" + *list.ShareToken
+}
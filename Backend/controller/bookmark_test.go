@@ -0,0 +1,243 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for bookmarks and reading lists
+type BookmarkTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	reader model.User
+	other  model.User
+	blogs  []model.Blog
+}
+
+// Setup before each test
+func (suite *BookmarkTestSuite) SetupTest() {
+	setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.reader = model.User{Username: "reader", Email: "reader@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.reader)
+	suite.other = model.User{Username: "other", Email: "other@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.other)
+
+	suite.blogs = nil
+	for _, title := range []string{"First", "Second", "Third"} {
+		blog := model.Blog{Title: title, Post: "content", UserID: suite.other.ID, UserName: suite.other.Username}
+		suite.db.Create(&blog)
+		suite.blogs = append(suite.blogs, blog)
+	}
+}
+
+// app signed in as user, or anonymous when user is nil
+func (suite *BookmarkTestSuite) appAs(user *model.User) *fiber.App {
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		if user != nil {
+			c.Locals("userEmail", user.Email)
+		}
+		return c.Next()
+	})
+	deps := testContainer(suite.db)
+	app.Get("/all-blogs-with-meta", deps.Blogs.AllWithMeta)
+	app.Post("/blogs/:id/bookmark", deps.Bookmarks.Add)
+	app.Delete("/blogs/:id/bookmark", deps.Bookmarks.Remove)
+	app.Get("/me/bookmarks", deps.Bookmarks.List)
+	app.Get("/me/reading-lists", deps.ReadingLists.List)
+	app.Post("/me/reading-lists", deps.ReadingLists.Create)
+	app.Get("/me/reading-lists/:listId", deps.ReadingLists.Get)
+	app.Put("/me/reading-lists/:listId", deps.ReadingLists.Update)
+	app.Delete("/me/reading-lists/:listId", deps.ReadingLists.Delete)
+	app.Post("/me/reading-lists/:listId/items", deps.ReadingLists.AddItem)
+	app.Delete("/me/reading-lists/:listId/items/:blogId", deps.ReadingLists.RemoveItem)
+	app.Put("/me/reading-lists/:listId/order", deps.ReadingLists.Reorder)
+	app.Get("/reading-lists/shared/:token", deps.ReadingLists.Shared)
+	return app
+}
+
+func (suite *BookmarkTestSuite) do(user *model.User, method, url string, payload interface{}) (*http.Response, map[string]interface{}) {
+	var body *bytes.Reader
+	if payload != nil {
+		raw, _ := json.Marshal(payload)
+		body = bytes.NewReader(raw)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, url, body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.appAs(user).Test(req)
+	assert.Nil(suite.T(), err)
+	var decoded map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp, decoded
+}
+
+func titles(body map[string]interface{}) []string {
+	var out []string
+	for _, b := range body["blogs"].([]interface{}) {
+		out = append(out, b.(map[string]interface{})["title"].(string))
+	}
+	return out
+}
+
+// creates a reading list for the reader and returns its id
+func (suite *BookmarkTestSuite) createList(name string, public bool) uint {
+	resp, body := suite.do(&suite.reader, http.MethodPost, "/me/reading-lists", fiber.Map{"name": name, "public": public})
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	return uint(body["reading_list"].(map[string]interface{})["id"].(float64))
+}
+
+// Test adding and removing bookmarks
+func (suite *BookmarkTestSuite) TestBookmarkAndRemove() {
+	url := fmt.Sprintf("/blogs/%d/bookmark", suite.blogs[0].ID)
+	resp, _ := suite.do(&suite.reader, http.MethodPost, url, nil)
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodPost, url, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	suite.do(&suite.reader, http.MethodPost, fmt.Sprintf("/blogs/%d/bookmark", suite.blogs[2].ID), nil)
+
+	_, body := suite.do(&suite.reader, http.MethodGet, "/me/bookmarks", nil)
+	assert.Equal(suite.T(), []string{"Third", "First"}, titles(body))
+
+	resp, _ = suite.do(&suite.reader, http.MethodDelete, url, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodDelete, url, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = suite.do(&suite.reader, http.MethodPost, "/blogs/999/bookmark", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test the bookmarked_by_me flag for signed in and anonymous viewers
+func (suite *BookmarkTestSuite) TestBookmarkedByMe() {
+	suite.do(&suite.reader, http.MethodPost, fmt.Sprintf("/blogs/%d/bookmark", suite.blogs[1].ID), nil)
+
+	flags := func(user *model.User) map[string]bool {
+		_, body := suite.do(user, http.MethodGet, "/all-blogs-with-meta", nil)
+		out := map[string]bool{}
+		for _, b := range body["blogs"].([]interface{}) {
+			blog := b.(map[string]interface{})
+			out[blog["title"].(string)] = blog["bookmarked_by_me"].(bool)
+		}
+		return out
+	}
+	assert.Equal(suite.T(), map[string]bool{"First": false, "Second": true, "Third": false}, flags(&suite.reader))
+	assert.Equal(suite.T(), map[string]bool{"First": false, "Second": false, "Third": false}, flags(&suite.other))
+	assert.Equal(suite.T(), map[string]bool{"First": false, "Second": false, "Third": false}, flags(nil))
+}
+
+// Test reading list CRUD and ownership
+func (suite *BookmarkTestSuite) TestReadingListCRUD() {
+	resp, _ := suite.do(&suite.reader, http.MethodPost, "/me/reading-lists", fiber.Map{"name": " "})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	id := suite.createList("Weekend", false)
+	url := fmt.Sprintf("/me/reading-lists/%d", id)
+
+	resp, body := suite.do(&suite.reader, http.MethodPut, url, fiber.Map{"name": "Holiday"})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "Holiday", body["reading_list"].(map[string]interface{})["name"])
+
+	_, body = suite.do(&suite.reader, http.MethodGet, "/me/reading-lists", nil)
+	assert.Len(suite.T(), body["reading_lists"], 1)
+
+	// other users cannot see or change the list
+	resp, _ = suite.do(&suite.other, http.MethodGet, url, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	resp, _ = suite.do(&suite.other, http.MethodDelete, url, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": suite.blogs[0].ID})
+	resp, _ = suite.do(&suite.reader, http.MethodDelete, url, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodGet, url, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	var items int64
+	suite.db.Model(&model.ReadingListItem{}).Count(&items)
+	assert.Equal(suite.T(), int64(0), items)
+}
+
+// Test adding, removing and reordering reading list items
+func (suite *BookmarkTestSuite) TestReadingListItems() {
+	url := fmt.Sprintf("/me/reading-lists/%d", suite.createList("Later", false))
+	for _, i := range []int{2, 0, 1} {
+		resp, _ := suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": suite.blogs[i].ID})
+		assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	}
+	resp, _ := suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": suite.blogs[0].ID})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": 999})
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	_, body := suite.do(&suite.reader, http.MethodGet, url, nil)
+	assert.Equal(suite.T(), []string{"Third", "First", "Second"}, titles(body))
+
+	order := []uint{suite.blogs[0].ID, suite.blogs[1].ID, suite.blogs[2].ID}
+	resp, body = suite.do(&suite.reader, http.MethodPut, url+"/order", fiber.Map{"blog_ids": order})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), []string{"First", "Second", "Third"}, titles(body))
+
+	// the new order must be a permutation of the current items
+	resp, _ = suite.do(&suite.reader, http.MethodPut, url+"/order", fiber.Map{"blog_ids": order[:2]})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodPut, url+"/order", fiber.Map{"blog_ids": []uint{order[0], order[0], order[1]}})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = suite.do(&suite.reader, http.MethodDelete, fmt.Sprintf("%s/items/%d", url, suite.blogs[1].ID), nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	resp, _ = suite.do(&suite.reader, http.MethodDelete, fmt.Sprintf("%s/items/%d", url, suite.blogs[1].ID), nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	// new items go to the end
+	suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": suite.blogs[1].ID})
+	_, body = suite.do(&suite.reader, http.MethodGet, url, nil)
+	assert.Equal(suite.T(), []string{"First", "Third", "Second"}, titles(body))
+}
+
+// Test sharing a reading list publicly and revoking it
+func (suite *BookmarkTestSuite) TestSharedReadingList() {
+	id := suite.createList("Picks", false)
+	url := fmt.Sprintf("/me/reading-lists/%d", id)
+	suite.do(&suite.reader, http.MethodPost, url+"/items", fiber.Map{"blog_id": suite.blogs[1].ID})
+
+	var list model.ReadingList
+	suite.db.First(&list, id)
+	assert.Nil(suite.T(), list.ShareToken)
+
+	_, body := suite.do(&suite.reader, http.MethodPut, url, fiber.Map{"public": true})
+	token, _ := body["reading_list"].(map[string]interface{})["share_token"].(string)
+	assert.Len(suite.T(), token, 32)
+	assert.Equal(suite.T(), "/api/reading-lists/shared/"+token, body["share_url"])
+
+	resp, body := suite.do(nil, http.MethodGet, "/reading-lists/shared/"+token, nil)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), []string{"Second"}, titles(body))
+	assert.Equal(suite.T(), "Picks", body["reading_list"].(map[string]interface{})["name"])
+
+	// making the list private revokes the link, sharing again issues a new one
+	suite.do(&suite.reader, http.MethodPut, url, fiber.Map{"public": false})
+	resp, _ = suite.do(nil, http.MethodGet, "/reading-lists/shared/"+token, nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	_, body = suite.do(&suite.reader, http.MethodPut, url, fiber.Map{"public": true})
+	assert.NotEqual(suite.T(), token, body["reading_list"].(map[string]interface{})["share_token"])
+}
+
+// Run the test suite
+func TestBookmarkSuite(t *testing.T) {
+	suite.Run(t, new(BookmarkTestSuite))
+}
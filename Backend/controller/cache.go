@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/redis"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// blogs and blog lists are fresh for a while, then served for a little
+// longer while they are reloaded
+var blogCacheOptions = cache.Options{TTL: 10 * time.Minute, StaleTTL: time.Minute}
+
+// response header telling whether the body came from the cache, one of the
+// cache.Status values
+const headerCache = "X-Cache"
+
+// tag of every cached feed
+const feedsTag = "feeds"
+
+// tag of the cached blog lists of a user, title filtered or not
+func blogListsTag(userID uint) string {
+	return fmt.Sprintf("user:%d:blogs", userID)
+}
+
+// tag of every cached entry holding the blog
+func blogTag(blogID uint) string {
+	return fmt.Sprintf("blog:%d", blogID)
+}
+
+// tag of every cached entry holding blogs or details of the user
+func authorTag(userID uint) string {
+	return fmt.Sprintf("author:%d", userID)
+}
+
+// key of a cached blog, shared by every reader
+func blogCacheKey(blogID uint) string {
+	return fmt.Sprintf("blog:%d", blogID)
+}
+
+// logs a failed cache call. Calls the breaker refused while Redis is down
+// are expected and not worth a line per request.
+func logCacheError(msg string, err error) {
+	if err != nil && !errors.Is(err, redis.ErrCircuitOpen) {
+		log.Println(msg, err)
+	}
+}
+
+// drops the cached blog lists of a user
+func invalidateBlogLists(responses cache.Cache, userID uint) {
+	logCacheError("Error invalidating cache", responses.DeleteTag(blogListsTag(userID)))
+}
+
+// drops every cached copy of a blog of userID, the lists of its author and
+// the feeds
+func invalidateBlog(responses cache.Cache, userID, blogID uint) {
+	logCacheError("Error invalidating cache", responses.DeleteTag(blogTag(blogID), blogListsTag(userID)))
+	invalidateFeeds(responses)
+}
+
+// drops everything cached about a user and their blogs
+func invalidateAuthor(responses cache.Cache, userID uint) {
+	logCacheError("Error invalidating cache", responses.DeleteTag(authorTag(userID)))
+	invalidateFeeds(responses)
+}
@@ -1,30 +1,123 @@
 package controller
 
 import (
-	"Gator_blog/database"
 	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func AddComment(c *fiber.Ctx) error {
-	var comment model.Comment
-	if err := c.BodyParser(&comment); err != nil {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+// CommentHandler serves the comments of a blog
+type CommentHandler struct {
+	comments *service.CommentService
+	users    *service.UserService
+	store    *redis.Store
+}
+
+func NewCommentHandler(comments *service.CommentService, users *service.UserService, store *redis.Store) *CommentHandler {
+	return &CommentHandler{comments: comments, users: users, store: store}
+}
+
+// Add comments on the blog in the path as the signed in user
+func (h *CommentHandler) Add(c *fiber.Ctx) error {
+	userEmail, ok := c.Locals("userEmail").(string)
+	if !ok || userEmail == "" {
+		return problem.Unauthorized("Unauthorized")
 	}
-	result := database.DBConn.Create(&comment)
-	if result.Error != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to add comment"})
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
+	var body commentRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
+	}
+
+	comment := model.Comment{Content: body.Content, UserID: user.ID, UserName: user.Username, BlogID: pathID(c, "id")}
+	err = h.comments.Add(&comment)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
+	if errors.Is(err, service.ErrInvalidInput) {
+		return problem.BadRequest("Invalid input")
+	}
+	if err != nil {
+		return problem.Failed("Failed to add comment", err)
+	}
+	bumpBlogCounter(h.store, comment.BlogID, redis.CounterComments, 1)
+	c.Set(fiber.HeaderETag, versionETag(comment.Version, comment))
 	return c.Status(201).JSON(comment)
 }
 
-func GetCommentsByBlogID(c *fiber.Ctx) error {
-	blogId := c.Params("id")
-	var comments []model.Comment
-	result := database.DBConn.Where("blog_id = ?", blogId).Find(&comments)
-	if result.Error != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch comments"})
+// Edit replaces the content of a comment of the signed in user. With
+// If-Match the comment must still be at the version it names, otherwise
+// the current comment is sent back with 412.
+func (h *CommentHandler) Edit(c *fiber.Ctx) error {
+	userEmail, ok := c.Locals("userEmail").(string)
+	if !ok || userEmail == "" {
+		return problem.Unauthorized("Unauthorized")
+	}
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
+	}
+	var body commentRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
+	}
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
+	}
+
+	comment, err := h.comments.Edit(user.ID, pathID(c, "id"), pathID(c, "commentId"), ifMatchVersion(c), body.Content)
+	switch {
+	case errors.Is(err, repository.ErrConflict):
+		c.Set(fiber.HeaderETag, versionETag(comment.Version, comment))
+		return problem.New(412, problem.VersionMismatch, "Comment was changed since it was loaded").With("comment", comment)
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.Missing("Comment not found")
+	case errors.Is(err, service.ErrInvalidInput):
+		return problem.BadRequest("Invalid input")
+	case err != nil:
+		return problem.Failed("Failed to update comment", err)
+	}
+	c.Set(fiber.HeaderETag, versionETag(comment.Version, comment))
+	return c.Status(200).JSON(fiber.Map{"msg": "Comment updated successfully", "comment": comment})
+}
+
+func (h *CommentHandler) List(c *fiber.Ctx) error {
+	comments, err := h.comments.ByBlog(pathID(c, "id"))
+	if err != nil {
+		return problem.Failed("Failed to fetch comments", err)
+	}
+	return sendConditional(c, comments, validators{Weak: true})
+}
+
+func (h *CommentHandler) Delete(c *fiber.Ctx) error {
+	userEmail, ok := c.Locals("userEmail").(string)
+	if !ok || userEmail == "" {
+		return problem.Unauthorized("Unauthorized")
+	}
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
+	}
+
+	comment, err := h.comments.Delete(user.ID, pathID(c, "id"), pathID(c, "commentId"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Comment not found")
+	}
+	if err != nil {
+		return problem.Failed("Failed to delete comment", err)
 	}
-	return c.JSON(comments)
+	bumpBlogCounter(h.store, comment.BlogID, redis.CounterComments, -1)
+	return c.Status(200).JSON(fiber.Map{"msg": "Comment deleted successfully"})
 }
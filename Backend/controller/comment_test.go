@@ -1,8 +1,7 @@
 package controller_test
 
 import (
-	"Gator_blog/controller"
-	"Gator_blog/database"
+	"Gator_blog/database/dbtest"
 	"Gator_blog/model"
 	"bytes"
 	"encoding/json"
@@ -14,7 +13,6 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -29,20 +27,18 @@ type CommentTestSuite struct {
 
 // Setup before each test
 func (suite *CommentTestSuite) SetupTest() {
-	// Create an in-memory SQLite database for testing
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		suite.T().Fatal("Failed to connect to test database:", err)
-	}
-	db.AutoMigrate(&model.User{}, &model.Blog{}, &model.Comment{})
-	database.DBConn = db
-	suite.db = db
+	// Create an empty database for testing
+	suite.db = dbtest.Open(suite.T(), &model.User{}, &model.Blog{}, &model.Comment{})
 
-	app := fiber.New()
+	deps := testContainer(suite.db)
+	app := newApp()
 
-	// Setup routes
-	app.Post("/comments", controller.AddComment)
-	app.Get("/blogs/:id/comments", controller.GetCommentsByBlogID)
+	// Setup routes, the signed in user is the test user
+	app.Post("/blogs/:id/comments", func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "test@example.com")
+		return c.Next()
+	}, deps.Comments.Add)
+	app.Get("/blogs/:id/comments", deps.Comments.List)
 
 	suite.app = app
 
@@ -73,16 +69,16 @@ func (suite *CommentTestSuite) TearDownTest() {
 
 // Test adding a comment successfully
 func (suite *CommentTestSuite) TestAddCommentSuccess() {
-	// The original test was using map with "UserID" and "BlogID", but the model likely expects "user_id" and "blog_id"
-	// Let's modify how we're sending the data to match what the controller expects
+	// The author and the blog come from the token and the path, whatever the
+	// body claims
 	commentData := map[string]interface{}{
-		"user_id": suite.userID,
-		"blog_id": suite.blogID,
+		"user_id": suite.userID + 100,
+		"blog_id": suite.blogID + 100,
 		"content": "This is a test comment",
 	}
 
 	jsonData, _ := json.Marshal(commentData)
-	req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(jsonData))
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/comments", suite.blogID), bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
@@ -103,10 +99,25 @@ func (suite *CommentTestSuite) TestAddCommentSuccess() {
 	assert.Equal(suite.T(), "This is a test comment", savedComment.Content)
 }
 
+// Test commenting on a blog that does not exist
+func (suite *CommentTestSuite) TestAddCommentNonExistentBlog() {
+	jsonData, _ := json.Marshal(map[string]string{"content": "Anyone there?"})
+	req := httptest.NewRequest(http.MethodPost, "/blogs/9999/comments", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	var count int64
+	suite.db.Model(&model.Comment{}).Count(&count)
+	assert.Zero(suite.T(), count)
+}
+
 // Test adding a comment with invalid input
 func (suite *CommentTestSuite) TestAddCommentInvalidInput() {
 	// Send malformed JSON
-	req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer([]byte("{invalid json")))
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/comments", suite.blogID), bytes.NewBuffer([]byte("{invalid json")))
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
@@ -116,18 +127,18 @@ func (suite *CommentTestSuite) TestAddCommentInvalidInput() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid input", result["error"])
+	assert.Equal(suite.T(), "Invalid input", result["detail"])
 }
 
 // Test adding a comment with missing required fields
 func (suite *CommentTestSuite) TestAddCommentMissingFields() {
-	// Missing UserID and BlogID
+	// Only the content is needed, the rest is filled in by the server
 	commentData := map[string]interface{}{
 		"content": "Incomplete comment data",
 	}
 
 	jsonData, _ := json.Marshal(commentData)
-	req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBuffer(jsonData))
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/comments", suite.blogID), bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := suite.app.Test(req)
 
@@ -137,10 +148,17 @@ func (suite *CommentTestSuite) TestAddCommentMissingFields() {
 	var result model.Comment
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	// UserID and BlogID should be zero values
-	assert.Zero(suite.T(), result.UserID)
-	assert.Zero(suite.T(), result.BlogID)
+	assert.Equal(suite.T(), suite.userID, result.UserID)
+	assert.Equal(suite.T(), suite.blogID, result.BlogID)
+	assert.Equal(suite.T(), "testuser", result.UserName)
 	assert.Equal(suite.T(), "Incomplete comment data", result.Content)
+
+	// A comment without content is refused
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/comments", suite.blogID), bytes.NewBufferString(`{"content": " "}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = suite.app.Test(req)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
 }
 
 // Test getting comments by blog ID when comments exist
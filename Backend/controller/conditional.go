@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"Gator_blog/problem"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// how long browsers and shared caches may reuse a public response before
+// revalidating it
+const publicMaxAge = 60 * time.Second
+
+// validators of a response
+type validators struct {
+	// Weak marks bodies rendered per request, equal in content but not
+	// necessarily in bytes to what another request or instance renders.
+	// Bodies taken from the shared cache are the same bytes everywhere and
+	// get a strong ETag.
+	Weak bool
+	// ETag replaces the hash of the body, see versionETag
+	ETag string
+}
+
+// sends body as JSON with an ETag and a Cache-Control policy, or 304 when
+// the ETag of the request still matches. Responses to signed in users are
+// private and revalidated on every use, the others can be shared for
+// publicMaxAge. There is no Last-Modified: bodies carry counters and
+// lists lose rows, neither of which moves an UpdatedAt.
+func sendConditional(c *fiber.Ctx, body interface{}, v validators) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return problem.Failed("Could not encode response", err)
+	}
+	etag := v.ETag
+	if etag == "" {
+		etag = `"` + contentHash(data) + `"`
+	}
+	if v.Weak {
+		etag = "W/" + etag
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Vary(fiber.HeaderAuthorization)
+	if userEmail, ok := c.Locals("userEmail").(string); ok && userEmail != "" {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	} else {
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(publicMaxAge.Seconds())))
+	}
+	if notModified(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(200).Send(data)
+}
+
+// hex digest identifying data
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// strong ETag of a versioned record, the same whichever response carries
+// it. The version is what If-Match checks, the hash of the content changes
+// with the counters as well.
+func versionETag(version int64, record interface{}) string {
+	data, _ := json.Marshal(record)
+	return fmt.Sprintf(`"v%d-%s"`, version, contentHash(data))
+}
+
+// version passed on to the services for an If-Match that cannot match, no
+// record is ever at it
+const unmatchableVersion = -1
+
+// version the If-Match header of a write asks for, 0 without one or for "*"
+// and unmatchableVersion when no tag in it carries a version. Tags only
+// need the version part, "v3" matches as well as the ETag of version 3,
+// weak tags never match.
+func ifMatchVersion(c *fiber.Ctx) int64 {
+	match := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if match == "" || match == "*" {
+		return 0
+	}
+	for _, candidate := range strings.Split(match, ",") {
+		tag, found := strings.CutPrefix(strings.TrimSpace(candidate), `"v`)
+		if !found {
+			continue
+		}
+		digits, _, _ := strings.Cut(strings.TrimSuffix(tag, `"`), "-")
+		if version, err := strconv.ParseInt(digits, 10, 64); err == nil && version > 0 {
+			return version
+		}
+	}
+	return unmatchableVersion
+}
@@ -0,0 +1,255 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// issues a GET with the given headers and returns the response and its body
+func conditionalGet(t *testing.T, app *fiber.App, url string, headers map[string]string) (*http.Response, string) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, string(raw)
+}
+
+func TestConditionalBlogFetch(t *testing.T) {
+	db := openTestDB(t)
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	blog := model.Blog{Title: "Hello", Post: "content", UserID: user.ID, UserName: user.Username}
+	db.Create(&blog)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Get("/blogs/:id", deps.Blogs.Fetch)
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+
+	resp, _ := conditionalGet(t, app, url, nil)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.False(t, strings.HasPrefix(etag, "W/"), "fetched blogs come from the shared cache and get strong ETags")
+	// the body carries counters that move without UpdatedAt
+	assert.Empty(t, resp.Header.Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", resp.Header.Get("Cache-Control"))
+	assert.Contains(t, resp.Header.Get("Vary"), "Authorization")
+
+	resp, body := conditionalGet(t, app, url, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Empty(t, body)
+	resp, _ = conditionalGet(t, app, url, map[string]string{"If-Modified-Since": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// a changed blog is sent again with new validators
+	db.Model(&blog).Updates(map[string]interface{}{"title": "Hello again", "updated_at": blog.UpdatedAt.Add(time.Hour)})
+	resp, body = conditionalGet(t, app, url, map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "Hello again")
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+}
+
+func TestConditionalPublicListings(t *testing.T) {
+	db := openTestDB(t)
+	seedBlogsWithMeta(db, 2)
+	var blog model.Blog
+	db.First(&blog)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Get("/all-blogs-with-meta", deps.Blogs.AllWithMeta)
+	app.Get("/blogs/:id/comments", deps.Comments.List)
+
+	for _, url := range []string{"/all-blogs-with-meta", fmt.Sprintf("/blogs/%d/comments", blog.ID)} {
+		t.Run(url, func(t *testing.T) {
+			resp, _ := conditionalGet(t, app, url, nil)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			etag := resp.Header.Get("ETag")
+			assert.True(t, strings.HasPrefix(etag, `W/"`), "rendered listings get weak ETags")
+			assert.Empty(t, resp.Header.Get("Last-Modified"))
+			assert.Equal(t, "public, max-age=60", resp.Header.Get("Cache-Control"))
+
+			// weak comparison, with or without the W/ prefix
+			resp, _ = conditionalGet(t, app, url, map[string]string{"If-None-Match": etag})
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+			resp, _ = conditionalGet(t, app, url, map[string]string{"If-None-Match": `"other", ` + strings.TrimPrefix(etag, "W/")})
+			assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+			resp, _ = conditionalGet(t, app, url, map[string]string{"If-None-Match": `"other"`})
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+
+	// a new comment changes both listings
+	before, _ := conditionalGet(t, app, "/all-blogs-with-meta", nil)
+	db.Create(&model.Comment{Content: "New", UserID: blog.UserID, UserName: "author", BlogID: blog.ID})
+	resp, body := conditionalGet(t, app, "/all-blogs-with-meta", map[string]string{"If-None-Match": before.Header.Get("ETag")})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "New")
+}
+
+// a deleted blog leaves no later UpdatedAt behind, so listings must not
+// answer If-Modified-Since with 304
+func TestListingsIgnoreIfModifiedSince(t *testing.T) {
+	db := openTestDB(t)
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	kept := model.Blog{Title: "Kept", Post: "content", UserID: user.ID, UserName: user.Username}
+	gone := model.Blog{Title: "Gone", Post: "content", UserID: user.ID, UserName: user.Username}
+	db.Create(&kept)
+	db.Create(&gone)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Get("/all-blogs-with-meta", deps.Blogs.AllWithMeta)
+	app.Get("/blogs", deps.Blogs.List)
+	app.Delete("/blogs/:id", deps.Blogs.Delete)
+
+	since := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	etags := map[string]string{}
+	for _, url := range []string{"/all-blogs-with-meta", "/blogs"} {
+		resp, body := conditionalGet(t, app, url, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, body, "Gone")
+		etags[url] = resp.Header.Get("ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/blogs/%d", gone.ID), nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for url, etag := range etags {
+		resp, body := conditionalGet(t, app, url, map[string]string{"If-Modified-Since": since})
+		assert.Equal(t, http.StatusOK, resp.StatusCode, url)
+		assert.NotContains(t, body, "Gone", url)
+		assert.Contains(t, body, "Kept", url)
+		resp, _ = conditionalGet(t, app, url, map[string]string{"If-None-Match": etag, "If-Modified-Since": since})
+		assert.Equal(t, http.StatusOK, resp.StatusCode, url)
+	}
+}
+
+// issues a PUT with an If-Match header when match is set
+func putIfMatch(t *testing.T, app *fiber.App, url, match string, payload fiber.Map) (*http.Response, map[string]interface{}) {
+	raw, _ := json.Marshal(payload)
+	req := httptest.NewRequest(http.MethodPut, url, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if match != "" {
+		req.Header.Set("If-Match", match)
+	}
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func TestOptimisticBlogUpdate(t *testing.T) {
+	db := openTestDB(t)
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	blog := model.Blog{Title: "Hello", Post: "content", UserID: user.ID, UserName: user.Username}
+	db.Create(&blog)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Get("/blogs/:id", deps.Blogs.Fetch)
+	app.Put("/blogs/:id", deps.Blogs.Update)
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+
+	// two tabs load the same version
+	resp, _ := conditionalGet(t, app, url, nil)
+	loaded := resp.Header.Get("ETag")
+	assert.True(t, strings.HasPrefix(loaded, `"v1-`), loaded)
+
+	resp, body := putIfMatch(t, app, url, loaded, fiber.Map{"title": "First tab"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("ETag"), `"v2-`))
+	assert.Equal(t, float64(2), body["blog"].(map[string]interface{})["version"])
+
+	// the second tab is told and gets the current copy
+	resp, body = putIfMatch(t, app, url, loaded, fiber.Map{"title": "Second tab"})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.Equal(t, "version_mismatch", body["code"])
+	current := body["blog"].(map[string]interface{})
+	assert.Equal(t, "First tab", current["Title"])
+	assert.Equal(t, float64(2), current["version"])
+	assert.True(t, strings.HasPrefix(resp.Header.Get("ETag"), `"v2-`))
+
+	// weak or version-less tags never match, a bare version does
+	resp, _ = putIfMatch(t, app, url, `W/"v2-abc"`, fiber.Map{"title": "Weak"})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp, _ = putIfMatch(t, app, url, `"abc"`, fiber.Map{"title": "No version"})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp, _ = putIfMatch(t, app, url, `"v2"`, fiber.Map{"title": "Second tab again"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// without If-Match the last write wins as before, the version still moves
+	resp, body = putIfMatch(t, app, url, "", fiber.Map{"title": "Unconditional", "version": 1})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(4), body["blog"].(map[string]interface{})["version"])
+
+	var stored model.Blog
+	db.First(&stored, blog.ID)
+	assert.Equal(t, "Unconditional", stored.Title)
+	assert.Equal(t, int64(4), stored.Version)
+}
+
+func TestOptimisticCommentEdit(t *testing.T) {
+	db := openTestDB(t)
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	blog := model.Blog{Title: "Hello", Post: "content", UserID: user.ID, UserName: user.Username}
+	db.Create(&blog)
+	comment := model.Comment{Content: "Nice", UserID: user.ID, UserName: user.Username, BlogID: blog.ID}
+	db.Create(&comment)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Put("/blogs/:id/comments/:commentId", deps.Comments.Edit)
+	url := fmt.Sprintf("/blogs/%d/comments/%d", blog.ID, comment.ID)
+
+	resp, body := putIfMatch(t, app, url, `"v1"`, fiber.Map{"content": "Very nice"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Very nice", body["comment"].(map[string]interface{})["content"])
+	assert.True(t, strings.HasPrefix(resp.Header.Get("ETag"), `"v2-`))
+
+	resp, body = putIfMatch(t, app, url, `"v1"`, fiber.Map{"content": "Lost edit"})
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	assert.Equal(t, "Very nice", body["comment"].(map[string]interface{})["content"])
+
+	resp, _ = putIfMatch(t, app, url, "", fiber.Map{"content": ""})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, _ = putIfMatch(t, app, fmt.Sprintf("/blogs/%d/comments/%d", blog.ID, comment.ID+1), "", fiber.Map{"content": "Missing"})
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/redis"
+	"log"
+)
+
+// returns a blog counter from Redis, seeding it through load on a miss.
+// Falls back to load alone when Redis is unavailable. load reports whether
+// the blog exists, counters of missing blogs are not seeded as the seeded
+// keys would never expire.
+func blogCounter(store *redis.Store, blogID uint, name string, load func() (int64, bool)) int64 {
+	val, found, err := store.GetCounter(blogID, name)
+	if err != nil {
+		log.Println("Redis error: ", err)
+		val, _ = load()
+		return val
+	}
+	if found {
+		return val
+	}
+	val, exists := load()
+	if !exists {
+		return val
+	}
+	if err := store.SeedCounter(blogID, name, val); err != nil {
+		log.Println("Error seeding counter", err)
+	}
+	return val
+}
+
+// applies delta to a blog counter. Counters that were never seeded are left
+// alone, the next read seeds them from the source table.
+func bumpBlogCounter(store *redis.Store, blogID uint, name string, delta int64) {
+	if _, err := store.IncrCounter(blogID, name, delta); err != nil {
+		log.Println("Error updating counter", err)
+	}
+}
+
+// records a view of blog. Views have no source table, so the counter is
+// seeded from the written-back column.
+func recordBlogView(store *redis.Store, blog model.Blog) {
+	applied, err := store.IncrCounter(blog.ID, redis.CounterViews, 1)
+	if err != nil {
+		log.Println("Error updating counter", err)
+		return
+	}
+	if applied {
+		return
+	}
+	if err := store.SeedCounter(blog.ID, redis.CounterViews, blog.ViewsCount); err != nil {
+		log.Println("Error seeding counter", err)
+		return
+	}
+	bumpBlogCounter(store, blog.ID, redis.CounterViews, 1)
+}
+
+// counts of many blogs for one counter, read from Redis in a single round
+// trip with the misses loaded by count, one grouped count, and seeded back
+func blogCounts(store *redis.Store, blogIDs []uint, name string, count func([]uint) (map[uint]int64, error)) (map[uint]int64, error) {
+	counts, err := store.GetCounters(blogIDs, name)
+	if err != nil {
+		log.Println("Redis error: ", err)
+		return count(blogIDs)
+	}
+
+	var missing []uint
+	for _, id := range blogIDs {
+		if _, ok := counts[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return counts, nil
+	}
+	loaded, err := count(missing)
+	if err != nil {
+		return nil, err
+	}
+	seed := make(map[uint]int64, len(missing))
+	for _, id := range missing {
+		counts[id] = loaded[id]
+		seed[id] = loaded[id]
+	}
+	if err := store.SeedCounters(name, seed); err != nil {
+		log.Println("Error seeding counter", err)
+	}
+	return counts, nil
+}
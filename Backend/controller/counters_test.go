@@ -0,0 +1,238 @@
+package controller_test
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/container"
+	"Gator_blog/database/dbtest"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// points the redis package and the cache at an in-process Redis for the
+// duration of the test
+func setupTestRedis(t *testing.T) *miniredis.Miniredis {
+	mr := miniredis.RunT(t)
+	redis.RedisClient = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	cache.Default = cache.NewRedis(redis.RedisClient)
+	t.Cleanup(func() {
+		redis.RedisClient.Close()
+		redis.RedisClient = nil
+		cache.Default = cache.None{}
+	})
+	return mr
+}
+
+// opens an in-memory SQLite database private to the test
+func openTestDB(t testing.TB) *gorm.DB {
+	return dbtest.Open(t, &model.User{}, &model.Tag{}, &model.Blog{}, &model.Comment{}, &model.Like{}, &model.BlogView{}, &model.BlogDailyStat{},
+		&model.Follow{}, &model.Bookmark{}, &model.ReadingList{}, &model.ReadingListItem{}, &model.Media{}, &model.MediaVariant{})
+}
+
+// app answering errors as problem+json, like the server
+func newApp() *fiber.App {
+	return fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+}
+
+// container on the test database db and the Redis, cache and upload storage
+// the setup helpers configured
+func testContainer(db *gorm.DB) *container.Container {
+	return container.New(db, container.Infra{
+		Store:   redis.NewStore(redis.RedisClient),
+		Cache:   cache.Default,
+		Storage: storage.Default,
+	})
+}
+
+// Define the test suite for the Redis backed counters
+type CounterTestSuite struct {
+	suite.Suite
+	app    *fiber.App
+	db     *gorm.DB
+	mr     *miniredis.Miniredis
+	deps   *container.Container
+	userID uint
+	blogID uint
+}
+
+// Setup before each test
+func (suite *CounterTestSuite) SetupTest() {
+	suite.mr = setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	deps := testContainer(suite.db)
+	suite.deps = deps
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", "test@example.com")
+		return c.Next()
+	})
+	app.Get("/blogs/:id", deps.Blogs.Fetch)
+	app.Post("/blogs/:id/likes", deps.Likes.Toggle)
+	app.Get("/blogs/:id/likes", deps.Likes.Count)
+	app.Post("/blogs/:id/comments", deps.Comments.Add)
+	app.Delete("/blogs/:id/comments/:commentId", deps.Comments.Delete)
+	suite.app = app
+
+	user := model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password"}
+	suite.db.Create(&user)
+	suite.userID = user.ID
+
+	blog := model.Blog{Title: "Test Blog", Post: "Test content", UserID: user.ID, UserName: user.Username}
+	suite.db.Create(&blog)
+	suite.blogID = blog.ID
+}
+
+func (suite *CounterTestSuite) getLikes() int64 {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d/likes", suite.blogID), nil)
+	resp, err := suite.app.Test(req)
+	assert.Nil(suite.T(), err)
+
+	var body map[string]int64
+	json.NewDecoder(resp.Body).Decode(&body)
+	return body["likes"]
+}
+
+func (suite *CounterTestSuite) counter(name string) string {
+	val, _ := suite.mr.Get(redis.CounterKey(suite.blogID, name))
+	return val
+}
+
+// Test that reading the like count seeds the Redis counter
+func (suite *CounterTestSuite) TestGetLikesSeedsCounter() {
+	suite.db.Create(&model.Like{UserID: suite.userID, BlogID: suite.blogID})
+
+	assert.Equal(suite.T(), int64(1), suite.getLikes())
+	assert.Equal(suite.T(), "1", suite.counter(redis.CounterLikes))
+}
+
+// Test that liking and unliking keep the counter in step
+func (suite *CounterTestSuite) TestLikeAndUnlikeUpdateCounter() {
+	assert.Equal(suite.T(), int64(0), suite.getLikes())
+
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/likes", suite.blogID), nil)
+	resp, _ := suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	assert.Equal(suite.T(), "1", suite.counter(redis.CounterLikes))
+	assert.Equal(suite.T(), int64(1), suite.getLikes())
+
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/likes", suite.blogID), nil)
+	resp, _ = suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "0", suite.counter(redis.CounterLikes))
+}
+
+// Test that an unseeded counter is left alone on writes
+func (suite *CounterTestSuite) TestLikeDoesNotSeedCounter() {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/likes", suite.blogID), nil)
+	suite.app.Test(req)
+
+	assert.False(suite.T(), suite.mr.Exists(redis.CounterKey(suite.blogID, redis.CounterLikes)))
+}
+
+// Test that reading the likes of a missing blog seeds no counter
+func (suite *CounterTestSuite) TestGetLikesMissingBlogSeedsNothing() {
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, "/blogs/9999/likes", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.False(suite.T(), suite.mr.Exists(redis.CounterKey(9999, redis.CounterLikes)))
+}
+
+// Test that adding and deleting comments update the counter
+func (suite *CounterTestSuite) TestCommentCounter() {
+	suite.deps.Infra.Store.SeedCounter(suite.blogID, redis.CounterComments, 0)
+
+	body := fmt.Sprintf(`{"content":"Nice","user_id":%d,"blog_id":%d}`, suite.userID, suite.blogID)
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/blogs/%d/comments", suite.blogID), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	assert.Equal(suite.T(), "1", suite.counter(redis.CounterComments))
+
+	var comment model.Comment
+	json.NewDecoder(resp.Body).Decode(&comment)
+
+	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/blogs/%d/comments/%d", suite.blogID, comment.ID), nil)
+	resp, _ = suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "0", suite.counter(redis.CounterComments))
+}
+
+// Test deleting somebody else's comment
+func (suite *CounterTestSuite) TestDeleteCommentOtherUser() {
+	comment := model.Comment{Content: "Not yours", UserID: suite.userID + 1, BlogID: suite.blogID}
+	suite.db.Create(&comment)
+
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/blogs/%d/comments/%d", suite.blogID, comment.ID), nil)
+	resp, _ := suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test that fetching a blog counts a view
+func (suite *CounterTestSuite) TestBlogFetchRecordsView() {
+	suite.db.Model(&model.Blog{}).Where("id = ?", suite.blogID).UpdateColumn("views_count", 41)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/blogs/%d", suite.blogID), nil)
+	req.Header.Set("User-Agent", browserUserAgent)
+	resp, _ := suite.app.Test(req)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "42", suite.counter(redis.CounterViews))
+}
+
+// Test that dirty counters are written back to the blog row
+func (suite *CounterTestSuite) TestFlushCounters() {
+	deps, store := suite.deps, suite.deps.Infra.Store
+	store.SeedCounter(suite.blogID, redis.CounterLikes, 4)
+	store.IncrCounter(suite.blogID, redis.CounterLikes, 1)
+	store.SeedCounter(suite.blogID, redis.CounterViews, 9)
+	store.IncrCounter(suite.blogID, redis.CounterViews, 1)
+
+	assert.Nil(suite.T(), deps.Jobs.FlushCounters())
+
+	var blog model.Blog
+	suite.db.First(&blog, suite.blogID)
+	assert.Equal(suite.T(), int64(5), blog.LikesCount)
+	assert.Equal(suite.T(), int64(10), blog.ViewsCount)
+
+	ids, _ := store.PopDirtyCounters(10)
+	assert.Empty(suite.T(), ids)
+}
+
+// Test that reconciliation rebuilds drifted counters from the source tables
+func (suite *CounterTestSuite) TestReconcileCounters() {
+	suite.db.Create(&model.Like{UserID: suite.userID, BlogID: suite.blogID})
+	suite.db.Create(&model.Comment{Content: "a", UserID: suite.userID, BlogID: suite.blogID})
+	suite.db.Create(&model.Comment{Content: "b", UserID: suite.userID, BlogID: suite.blogID})
+	suite.db.Model(&model.Blog{}).Where("id = ?", suite.blogID).UpdateColumn("views_count", 7)
+	suite.deps.Infra.Store.SetCounter(suite.blogID, redis.CounterLikes, 12)
+	suite.deps.Infra.Store.SetCounter(suite.blogID, redis.CounterViews, 3)
+
+	assert.Nil(suite.T(), suite.deps.Jobs.ReconcileCounters())
+
+	assert.Equal(suite.T(), "1", suite.counter(redis.CounterLikes))
+	assert.Equal(suite.T(), "7", suite.counter(redis.CounterViews))
+
+	var blog model.Blog
+	suite.db.First(&blog, suite.blogID)
+	assert.Equal(suite.T(), int64(1), blog.LikesCount)
+	assert.Equal(suite.T(), int64(2), blog.CommentsCount)
+}
+
+// Run the test suite
+func TestCounterSuite(t *testing.T) {
+	suite.Run(t, new(CounterTestSuite))
+}
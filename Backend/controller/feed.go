@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/service"
+	"log"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// readers following more authors than this get a materialised Redis timeline
+// that new posts are pushed to, everyone else's feed is queried on read
+const heavyFollowingThreshold = 100
+
+// default and maximum page size of the home feed
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 50
+)
+
+// FeedHandler serves the home feed of the signed in user
+type FeedHandler struct {
+	follows *service.FollowService
+	blogs   *service.BlogService
+	users   *service.UserService
+	meta    *MetaLoader
+	store   *redis.Store
+}
+
+func NewFeedHandler(follows *service.FollowService, blogs *service.BlogService, users *service.UserService, meta *MetaLoader, store *redis.Store) *FeedHandler {
+	return &FeedHandler{follows: follows, blogs: blogs, users: users, meta: meta, store: store}
+}
+
+// Returns the newest posts of the authors the signed in user follows. Blog ids
+// grow with publication time, so ?cursor is the id of the last post of the
+// previous page and pages are ordered by id.
+func (h *FeedHandler) Feed(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Feed",
+	}
+
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", defaultFeedLimit)
+	if limit < 1 || limit > maxFeedLimit {
+		return problem.BadRequest("Invalid limit")
+	}
+	var cursor uint
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return problem.BadRequest("Invalid cursor")
+		}
+		cursor = uint(parsed)
+	}
+
+	blogs, next, err := h.page(user.ID, cursor, limit)
+	if err != nil {
+		return problem.Failed("Could not fetch feed", err)
+	}
+	enriched, err := h.meta.Load(blogs, user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch feed", err)
+	}
+
+	context["blogs"] = enriched
+	if next != 0 {
+		context["next_cursor"] = strconv.FormatUint(uint64(next), 10)
+	}
+	return c.Status(200).JSON(context)
+}
+
+// returns up to limit posts older than cursor from the followed authors and
+// the cursor of the next page, 0 on the last one. The cursor is the last id
+// the page read, so posts deleted since they were pushed to a timeline make
+// the page shorter without ending the feed.
+func (h *FeedHandler) page(userID, cursor uint, limit int) ([]model.Blog, uint, error) {
+	followees, err := h.follows.Followees(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(followees) == 0 {
+		return []model.Blog{}, 0, nil
+	}
+	if len(followees) <= heavyFollowingThreshold {
+		return h.followedBlogs(followees, cursor, limit)
+	}
+
+	ids, err := h.timelineIDs(userID, followees, cursor, limit)
+	if err != nil {
+		log.Println("Redis error: ", err)
+		return h.followedBlogs(followees, cursor, limit)
+	}
+	// timeline ids are newest first
+	found, err := h.blogs.Ranked(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(ids) == limit {
+		return found, ids[len(ids)-1], nil
+	}
+	// the timeline is capped, older pages come from the database
+	before := cursor
+	if len(ids) > 0 {
+		before = ids[len(ids)-1]
+	}
+	older, next, err := h.followedBlogs(followees, before, limit-len(ids))
+	if err != nil {
+		return nil, 0, err
+	}
+	return append(found, older...), next, nil
+}
+
+// reads a page of a heavy reader's timeline, materialising it on first use
+func (h *FeedHandler) timelineIDs(userID uint, followees []uint, cursor uint, limit int) ([]uint, error) {
+	ids, found, err := h.store.TimelinePage(userID, cursor, int64(limit))
+	if err != nil || found {
+		return ids, err
+	}
+
+	latest, err := h.blogs.LatestIDs(followees, redis.TimelineSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.store.StoreTimeline(userID, latest); err != nil {
+		return nil, err
+	}
+	ids, _, err = h.store.TimelinePage(userID, cursor, int64(limit))
+	return ids, err
+}
+
+// fan-out on read: queries the newest posts of the followed authors directly
+func (h *FeedHandler) followedBlogs(followees []uint, cursor uint, limit int) ([]model.Blog, uint, error) {
+	blogs, err := h.blogs.ByAuthors(followees, cursor, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(blogs) < limit {
+		return blogs, 0, nil
+	}
+	return blogs, blogs[len(blogs)-1].ID, nil
+}
+
+// fan-out on write: pushes a new post onto the materialised timelines of the
+// author's followers
+func pushToFollowerTimelines(store *redis.Store, follows *service.FollowService, blog model.Blog) {
+	followers, err := follows.FollowerIDs(blog.UserID)
+	if err != nil {
+		log.Println("Error fetching followers", err)
+		return
+	}
+	if err := store.PushToTimelines(followers, blog.ID); err != nil && err != redis.ErrNotInitialized {
+		log.Println("Error pushing to timelines", err)
+	}
+}
+
+// takes a deleted post off the materialised timelines of the author's
+// followers
+func removeFromFollowerTimelines(store *redis.Store, follows *service.FollowService, blog model.Blog) {
+	followers, err := follows.FollowerIDs(blog.UserID)
+	if err != nil {
+		log.Println("Error fetching followers", err)
+		return
+	}
+	if err := store.RemoveFromTimelines(followers, blog.ID); err != nil && err != redis.ErrNotInitialized {
+		log.Println("Error removing from timelines", err)
+	}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// default and maximum page size of follower and following lists
+const (
+	defaultFollowLimit = 50
+	maxFollowLimit     = 100
+)
+
+// public fields of a user, safe to show to anyone
+type PublicUser struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// FollowHandler serves the social graph
+type FollowHandler struct {
+	follows *service.FollowService
+	users   *service.UserService
+	store   *redis.Store
+}
+
+func NewFollowHandler(follows *service.FollowService, users *service.UserService, store *redis.Store) *FollowHandler {
+	return &FollowHandler{follows: follows, users: users, store: store}
+}
+
+// Follows the user named in the path
+func (h *FollowHandler) Follow(c *fiber.Ctx) error {
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	follow, created, err := h.follows.Follow(user, c.Params("username"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	if errors.Is(err, service.ErrFollowSelf) {
+		return problem.BadRequest("You cannot follow yourself")
+	}
+	if err != nil {
+		return problem.Failed("Failed to follow user", err)
+	}
+	if !created {
+		return c.Status(200).JSON(fiber.Map{"msg": "Already following"})
+	}
+	invalidateTimeline(h.store, user.ID)
+	return c.Status(201).JSON(follow)
+}
+
+// Unfollows the user named in the path
+func (h *FollowHandler) Unfollow(c *fiber.Ctx) error {
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	removed, err := h.follows.Unfollow(user, c.Params("username"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	if err != nil {
+		return problem.Failed("Failed to unfollow user", err)
+	}
+	if !removed {
+		return problem.Missing("Not following")
+	}
+	invalidateTimeline(h.store, user.ID)
+	return c.Status(200).JSON(fiber.Map{"msg": "Unfollowed successfully"})
+}
+
+// Lists the users following the user named in the path
+func (h *FollowHandler) Followers(c *fiber.Ctx) error {
+	return listFollows(c, h.follows.Followers, "Followers")
+}
+
+// Lists the users the user named in the path follows
+func (h *FollowHandler) Following(c *fiber.Ctx) error {
+	return listFollows(c, h.follows.Following, "Following")
+}
+
+// pages through the follows of the named user that list returns, most recent
+// first. ?cursor is the id of the last follow of the previous page.
+func listFollows(c *fiber.Ctx, list func(username string, cursor uint, limit int) (int64, []repository.FollowedUser, error), msg string) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        msg,
+	}
+
+	limit := c.QueryInt("limit", defaultFollowLimit)
+	if limit < 1 || limit > maxFollowLimit {
+		return problem.BadRequest("Invalid limit")
+	}
+	var cursor uint
+	if raw := c.QueryInt("cursor"); raw > 0 {
+		cursor = uint(raw)
+	}
+
+	count, rows, err := list(c.Params("username"), cursor, limit)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch "+msg, err)
+	}
+
+	users := make([]PublicUser, len(rows))
+	for i, r := range rows {
+		users[i] = PublicUser{ID: r.ID, Username: r.Username}
+	}
+	context["count"] = count
+	context["users"] = users
+	if len(rows) == limit {
+		context["next_cursor"] = rows[len(rows)-1].FollowID
+	}
+	return c.Status(200).JSON(context)
+}
+
+// drops the materialised timeline of a user whose followings changed, the
+// next feed request rebuilds it
+func invalidateTimeline(store *redis.Store, userID uint) {
+	if err := store.DeleteCache(redis.TimelineKey(userID)); err != nil && err != redis.ErrNotInitialized {
+		log.Println("Error invalidating timeline", err)
+	}
+}
@@ -0,0 +1,235 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/redis"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for follows and the home feed
+type FollowTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	mr     *miniredis.Miniredis
+	reader model.User
+	author model.User
+}
+
+// Setup before each test
+func (suite *FollowTestSuite) SetupTest() {
+	suite.mr = setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.reader = suite.createUser("reader")
+	suite.author = suite.createUser("author")
+}
+
+func (suite *FollowTestSuite) createUser(name string) model.User {
+	user := model.User{Username: name, Email: name + "@example.com", Password: "hashed_password"}
+	suite.db.Create(&user)
+	return user
+}
+
+func (suite *FollowTestSuite) appAs(user model.User) *fiber.App {
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	deps := testContainer(suite.db)
+	app.Post("/blogs", deps.Blogs.Create)
+	app.Delete("/blogs/:id", deps.Blogs.Delete)
+	app.Post("/users/:username/follow", deps.Follows.Follow)
+	app.Delete("/users/:username/follow", deps.Follows.Unfollow)
+	app.Get("/users/:username/followers", deps.Follows.Followers)
+	app.Get("/users/:username/following", deps.Follows.Following)
+	app.Get("/feed", deps.Feed.Feed)
+	return app
+}
+
+func (suite *FollowTestSuite) do(user model.User, method, url string) (*http.Response, map[string]interface{}) {
+	resp, err := suite.appAs(user).Test(httptest.NewRequest(method, url, nil))
+	assert.Nil(suite.T(), err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+// returns the titles of one feed page and the cursor of the next
+func (suite *FollowTestSuite) feed(url string) ([]string, string) {
+	resp, body := suite.do(suite.reader, http.MethodGet, url)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	var titles []string
+	for _, b := range body["blogs"].([]interface{}) {
+		titles = append(titles, b.(map[string]interface{})["title"].(string))
+	}
+	cursor, _ := body["next_cursor"].(string)
+	return titles, cursor
+}
+
+func (suite *FollowTestSuite) post(author model.User, title string) model.Blog {
+	blog := model.Blog{Title: title, Post: "content", UserID: author.ID, UserName: author.Username}
+	suite.db.Create(&blog)
+	return blog
+}
+
+// Test following and unfollowing
+func (suite *FollowTestSuite) TestFollowAndUnfollow() {
+	resp, _ := suite.do(suite.reader, http.MethodPost, "/users/author/follow")
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	resp, body := suite.do(suite.reader, http.MethodPost, "/users/author/follow")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "Already following", body["msg"])
+
+	resp, _ = suite.do(suite.reader, http.MethodDelete, "/users/author/follow")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp, _ = suite.do(suite.reader, http.MethodDelete, "/users/author/follow")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test invalid follow targets
+func (suite *FollowTestSuite) TestFollowInvalid() {
+	resp, _ := suite.do(suite.reader, http.MethodPost, "/users/reader/follow")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = suite.do(suite.reader, http.MethodPost, "/users/nobody/follow")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test follower and following lists with counts and pagination
+func (suite *FollowTestSuite) TestFollowLists() {
+	for _, name := range []string{"a", "b", "c"} {
+		suite.do(suite.createUser(name), http.MethodPost, "/users/author/follow")
+	}
+	suite.do(suite.author, http.MethodPost, "/users/reader/follow")
+
+	resp, body := suite.do(suite.reader, http.MethodGet, "/users/author/followers?limit=2")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), float64(3), body["count"])
+	users := body["users"].([]interface{})
+	assert.Len(suite.T(), users, 2)
+	assert.Equal(suite.T(), "c", users[0].(map[string]interface{})["username"])
+	assert.NotContains(suite.T(), users[0], "email")
+
+	_, body = suite.do(suite.reader, http.MethodGet, fmt.Sprintf("/users/author/followers?limit=2&cursor=%v", body["next_cursor"]))
+	users = body["users"].([]interface{})
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), "a", users[0].(map[string]interface{})["username"])
+	assert.Nil(suite.T(), body["next_cursor"])
+
+	_, body = suite.do(suite.reader, http.MethodGet, "/users/author/following")
+	assert.Equal(suite.T(), float64(1), body["count"])
+	assert.Equal(suite.T(), "reader", body["users"].([]interface{})[0].(map[string]interface{})["username"])
+}
+
+// Test the feed of a reader following few authors
+func (suite *FollowTestSuite) TestFeedFanOutOnRead() {
+	other := suite.createUser("other")
+	suite.post(suite.author, "First")
+	suite.post(other, "Not followed")
+	suite.post(suite.author, "Second")
+	suite.post(suite.author, "Third")
+
+	titles, _ := suite.feed("/feed")
+	assert.Empty(suite.T(), titles)
+
+	suite.do(suite.reader, http.MethodPost, "/users/author/follow")
+
+	titles, cursor := suite.feed("/feed?limit=2")
+	assert.Equal(suite.T(), []string{"Third", "Second"}, titles)
+	titles, cursor = suite.feed("/feed?limit=2&cursor=" + cursor)
+	assert.Equal(suite.T(), []string{"First"}, titles)
+	assert.Empty(suite.T(), cursor)
+
+	assert.False(suite.T(), suite.mr.Exists(redis.TimelineKey(suite.reader.ID)))
+}
+
+// Test the feed of a reader following many authors
+func (suite *FollowTestSuite) TestFeedRedisTimeline() {
+	for i := 0; i <= 100; i++ {
+		author := suite.createUser(fmt.Sprintf("author%d", i))
+		suite.db.Create(&model.Follow{FollowerID: suite.reader.ID, FolloweeID: author.ID})
+	}
+	suite.do(suite.reader, http.MethodPost, "/users/author/follow")
+	suite.post(suite.author, "Before")
+
+	titles, _ := suite.feed("/feed")
+	assert.Equal(suite.T(), []string{"Before"}, titles)
+	assert.True(suite.T(), suite.mr.Exists(redis.TimelineKey(suite.reader.ID)))
+
+	// new posts are pushed onto the materialised timeline
+	body, _ := json.Marshal(map[string]string{"title": "After", "post": "content"})
+	req := httptest.NewRequest(http.MethodPost, "/blogs", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ := suite.appAs(suite.author).Test(req)
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	members, _ := suite.mr.ZMembers(redis.TimelineKey(suite.reader.ID))
+	assert.Len(suite.T(), members, 3) // placeholder, Before and After
+
+	titles, cursor := suite.feed("/feed?limit=1")
+	assert.Equal(suite.T(), []string{"After"}, titles)
+	titles, _ = suite.feed("/feed?limit=1&cursor=" + cursor)
+	assert.Equal(suite.T(), []string{"Before"}, titles)
+
+	// unfollowing drops the timeline
+	suite.do(suite.reader, http.MethodDelete, "/users/author/follow")
+	assert.False(suite.T(), suite.mr.Exists(redis.TimelineKey(suite.reader.ID)))
+	titles, _ = suite.feed("/feed")
+	assert.Empty(suite.T(), titles)
+}
+
+// Test that deleted posts neither stay on timelines nor end the feed early
+func (suite *FollowTestSuite) TestFeedTimelineDeletedPosts() {
+	for i := 0; i <= 100; i++ {
+		author := suite.createUser(fmt.Sprintf("author%d", i))
+		suite.db.Create(&model.Follow{FollowerID: suite.reader.ID, FolloweeID: author.ID})
+	}
+	suite.do(suite.reader, http.MethodPost, "/users/author/follow")
+	first := suite.post(suite.author, "First")
+	second := suite.post(suite.author, "Second")
+	suite.post(suite.author, "Third")
+	titles, _ := suite.feed("/feed")
+	assert.Equal(suite.T(), []string{"Third", "Second", "First"}, titles)
+
+	// deleted while the timeline could not be told, the page is short but
+	// the next one follows
+	suite.db.Delete(&model.Blog{}, second.ID)
+	titles, cursor := suite.feed("/feed?limit=2")
+	assert.Equal(suite.T(), []string{"Third"}, titles)
+	assert.Equal(suite.T(), fmt.Sprint(second.ID), cursor)
+	titles, _ = suite.feed("/feed?limit=2&cursor=" + cursor)
+	assert.Equal(suite.T(), []string{"First"}, titles)
+
+	// deleting a post takes it off the timeline
+	resp, _ := suite.do(suite.author, http.MethodDelete, fmt.Sprintf("/blogs/%d", first.ID))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	members, _ := suite.mr.ZMembers(redis.TimelineKey(suite.reader.ID))
+	assert.NotContains(suite.T(), members, fmt.Sprint(first.ID))
+}
+
+// Test invalid feed parameters
+func (suite *FollowTestSuite) TestFeedInvalidParams() {
+	resp, _ := suite.do(suite.reader, http.MethodGet, "/feed?cursor=abc")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	resp, _ = suite.do(suite.reader, http.MethodGet, "/feed?limit=1000")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+// Run the test suite
+func TestFollowSuite(t *testing.T) {
+	suite.Run(t, new(FollowTestSuite))
+}
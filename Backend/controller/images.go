@@ -0,0 +1,263 @@
+package controller
+
+import (
+	"Gator_blog/identicon"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/storage"
+	"bytes"
+	"errors"
+	"fmt"
+	"image/png"
+	"log"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// default, smallest and largest size of generated identicons
+const (
+	defaultIdenticonSize = 256
+	minIdenticonSize     = 16
+	maxIdenticonSize     = 512
+)
+
+// Uploads a cover image for one of the signed in user's blogs, replacing the
+// previous one
+func (h *MediaHandler) SetCover(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Cover image updated successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	blog, err := h.ownBlog(c, user)
+	if err != nil {
+		return err
+	}
+	data, filename, err := readUpload(c)
+	if err != nil {
+		return err
+	}
+	media, err := storeImage(h.media, user.ID, model.MediaCover, filename, data, coverVariants)
+	if err := uploadProblem(err); err != nil {
+		return err
+	}
+
+	previous := copyID(blog.CoverMediaID) // SetCover writes through the pointer
+	if err := h.blogs.SetCover(&blog, &media.ID); err != nil {
+		deleteMedia(h.media, media)
+		return problem.Failed("Could not update cover image", err)
+	}
+	deleteMediaByID(h.media, previous)
+	invalidateBlog(h.cache, user.ID, blog.ID)
+
+	context["cover_image"] = coverImage(h.media.files, media.Variants)
+	return c.Status(200).JSON(context)
+}
+
+// Removes the cover image of one of the signed in user's blogs
+func (h *MediaHandler) RemoveCover(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Cover image removed successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	blog, err := h.ownBlog(c, user)
+	if err != nil {
+		return err
+	}
+	if blog.CoverMediaID == nil {
+		return problem.Missing("Blog has no cover image")
+	}
+
+	previous := copyID(blog.CoverMediaID)
+	if err := h.blogs.SetCover(&blog, nil); err != nil {
+		return problem.Failed("Could not remove cover image", err)
+	}
+	deleteMediaByID(h.media, previous)
+	invalidateBlog(h.cache, user.ID, blog.ID)
+	return c.Status(200).JSON(context)
+}
+
+// Uploads an avatar for the signed in user, replacing the previous one
+func (h *MediaHandler) SetAvatar(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Avatar updated successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	data, filename, err := readUpload(c)
+	if err != nil {
+		return err
+	}
+	media, err := storeImage(h.media, user.ID, model.MediaAvatar, filename, data, avatarVariants)
+	if err := uploadProblem(err); err != nil {
+		return err
+	}
+
+	previous := copyID(user.AvatarMediaID)
+	if err := h.users.SetAvatar(&user, &media.ID); err != nil {
+		deleteMedia(h.media, media)
+		return problem.Failed("Could not update avatar", err)
+	}
+	deleteMediaByID(h.media, previous)
+
+	context["avatar_url"] = variantURL(h.media.files, media.Variants, "avatar")
+	return c.Status(200).JSON(context)
+}
+
+// Removes the signed in user's avatar, falling back to their identicon
+func (h *MediaHandler) RemoveAvatar(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Avatar removed successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+	if user.AvatarMediaID == nil {
+		return problem.Missing("No avatar uploaded")
+	}
+
+	previous := copyID(user.AvatarMediaID)
+	if err := h.users.SetAvatar(&user, nil); err != nil {
+		return problem.Failed("Could not remove avatar", err)
+	}
+	deleteMediaByID(h.media, previous)
+
+	context["avatar_url"] = avatarURL(h.media, user)
+	return c.Status(200).JSON(context)
+}
+
+// Serves the avatar of the user named in the path, redirecting to the
+// uploaded image or drawing their identicon. Accepts ?size for identicons.
+func (h *MediaHandler) Avatar(c *fiber.Ctx) error {
+	user, err := h.users.ByUsername(c.Params("username"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch user", err)
+	}
+	if url := avatarURL(h.media, user); url != identiconURL(user) {
+		return c.Redirect(url, fiber.StatusFound)
+	}
+
+	size := c.QueryInt("size", defaultIdenticonSize)
+	if size < minIdenticonSize || size > maxIdenticonSize {
+		return problem.BadRequest(fmt.Sprintf("size must be between %d and %d", minIdenticonSize, maxIdenticonSize))
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, identicon.Generate(user.Username, size)); err != nil {
+		return problem.Failed("Could not draw avatar", err)
+	}
+	c.Set(fiber.HeaderContentType, "image/png")
+	c.Set(fiber.HeaderCacheControl, "public, max-age=86400")
+	return c.Status(200).Send(buf.Bytes())
+}
+
+// URL of a user's avatar, their identicon when they have not uploaded one
+func avatarURL(media *Uploads, user model.User) string {
+	if user.AvatarMediaID == nil || media.files == nil {
+		return identiconURL(user)
+	}
+	variants, err := media.Variants([]uint{*user.AvatarMediaID})
+	if err != nil {
+		log.Println("Error fetching avatar", err)
+		return identiconURL(user)
+	}
+	if url := variantURL(media.files, variants[*user.AvatarMediaID], "avatar"); url != "" {
+		return url
+	}
+	return identiconURL(user)
+}
+
+func identiconURL(user model.User) string {
+	return "/api/users/" + url.PathEscape(user.Username) + "/avatar"
+}
+
+// the cover image of a blog in its two sizes
+func coverImage(files storage.Storage, variants []model.MediaVariant) *CoverImage {
+	cover := &CoverImage{}
+	for _, variant := range variants {
+		switch variant.Name {
+		case "cover":
+			cover.URL = files.URL(variant.Key)
+			cover.Width, cover.Height = variant.Width, variant.Height
+		case "cover_small":
+			cover.SmallURL = files.URL(variant.Key)
+		}
+	}
+	if cover.URL == "" {
+		return nil
+	}
+	return cover
+}
+
+// loads the cover images of many blogs in one query, keyed by blog id
+func coverImages(media *Uploads, blogs []model.Blog) (map[uint]*CoverImage, error) {
+	covers := map[uint]*CoverImage{}
+	byMedia := map[uint][]uint{}
+	var mediaIDs []uint
+	for _, blog := range blogs {
+		if blog.CoverMediaID != nil {
+			byMedia[*blog.CoverMediaID] = append(byMedia[*blog.CoverMediaID], blog.ID)
+			mediaIDs = append(mediaIDs, *blog.CoverMediaID)
+		}
+	}
+	if len(mediaIDs) == 0 || media.files == nil {
+		return covers, nil
+	}
+
+	grouped, err := media.Variants(mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+	for mediaID, blogIDs := range byMedia {
+		cover := coverImage(media.files, grouped[mediaID])
+		for _, id := range blogIDs {
+			covers[id] = cover
+		}
+	}
+	return covers, nil
+}
+
+func variantURL(files storage.Storage, variants []model.MediaVariant, name string) string {
+	for _, variant := range variants {
+		if variant.Name == name && files != nil {
+			return files.URL(variant.Key)
+		}
+	}
+	return ""
+}
+
+// looks up the blog in the path owned by user
+func (h *MediaHandler) ownBlog(c *fiber.Ctx, user model.User) (model.Blog, error) {
+	blog, err := h.blogs.Owned(pathID(c, "id"), user.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return blog, problem.Missing("Blog not found")
+	}
+	if err != nil {
+		return blog, problem.Failed("Could not fetch blog", err)
+	}
+	return blog, nil
+}
+
+func copyID(id *uint) *uint {
+	if id == nil {
+		return nil
+	}
+	value := *id
+	return &value
+}
@@ -0,0 +1,188 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for covers, avatars and account deletion
+type ImagesTestSuite struct {
+	suite.Suite
+	db    *gorm.DB
+	root  string
+	owner model.User
+	other model.User
+	blog  model.Blog
+}
+
+// Setup before each test
+func (suite *ImagesTestSuite) SetupTest() {
+	setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+	suite.root = setupTestStorage(suite.T())
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	suite.owner = model.User{Username: "owner", Email: "owner@example.com", Password: string(hash)}
+	suite.db.Create(&suite.owner)
+	suite.other = model.User{Username: "other", Email: "other@example.com", Password: string(hash)}
+	suite.db.Create(&suite.other)
+	suite.blog = model.Blog{Title: "Post", Post: "Body", UserID: suite.owner.ID, UserName: suite.owner.Username}
+	suite.db.Create(&suite.blog)
+}
+
+func (suite *ImagesTestSuite) appAs(user model.User) *fiber.App {
+	app := fiber.New(fiber.Config{BodyLimit: controller.MaxUploadSize + 1<<20, ErrorHandler: problem.Handler})
+	deps := testContainer(suite.db)
+	app.Get("/users/:username/avatar", deps.Media.Avatar)
+	app.Get("/all-blogs-with-meta", deps.Blogs.AllWithMeta)
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Put("/blogs/:id/cover", deps.Media.SetCover)
+	app.Delete("/blogs/:id/cover", deps.Media.RemoveCover)
+	app.Put("/me/avatar", deps.Media.SetAvatar)
+	app.Delete("/me/avatar", deps.Media.RemoveAvatar)
+	app.Delete("/me", deps.Accounts.Delete)
+	return app
+}
+
+func (suite *ImagesTestSuite) do(user model.User, req *http.Request) (*http.Response, map[string]interface{}) {
+	resp, err := suite.appAs(user).Test(req, -1)
+	assert.Nil(suite.T(), err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func (suite *ImagesTestSuite) setCover(user model.User, w, h int) (*http.Response, map[string]interface{}) {
+	url := fmt.Sprintf("/blogs/%d/cover", suite.blog.ID)
+	return suite.do(user, uploadRequest(http.MethodPut, url, "cover.png", pngImage(w, h)))
+}
+
+func (suite *ImagesTestSuite) stored(url string) string {
+	return filepath.Join(suite.root, filepath.FromSlash(strings.TrimPrefix(url, "/uploads/")))
+}
+
+// Test that a cover is cropped, listed with the blog and replaced cleanly
+func (suite *ImagesTestSuite) TestSetAndReplaceCover() {
+	resp, body := suite.setCover(suite.owner, 1600, 1600)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	first := body["cover_image"].(map[string]interface{})
+	assert.Equal(suite.T(), float64(1200), first["width"])
+	assert.Equal(suite.T(), float64(630), first["height"])
+	assert.FileExists(suite.T(), suite.stored(first["url"].(string)))
+	assert.FileExists(suite.T(), suite.stored(first["small_url"].(string)))
+
+	_, body = suite.do(suite.owner, httptest.NewRequest(http.MethodGet, "/all-blogs-with-meta", nil))
+	blogs := body["blogs"].([]interface{})
+	assert.Equal(suite.T(), first["url"], blogs[0].(map[string]interface{})["cover_image"].(map[string]interface{})["url"])
+
+	resp, body = suite.setCover(suite.owner, 800, 600)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.NotEqual(suite.T(), first["url"], body["cover_image"].(map[string]interface{})["url"])
+	assert.NoFileExists(suite.T(), suite.stored(first["url"].(string)))
+
+	var count int64
+	suite.db.Model(&model.Media{}).Count(&count)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+// Test that covers can only be set on and removed from one's own blogs
+func (suite *ImagesTestSuite) TestCoverOtherUsersBlog() {
+	resp, _ := suite.setCover(suite.other, 400, 300)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	suite.setCover(suite.owner, 400, 300)
+	url := fmt.Sprintf("/blogs/%d/cover", suite.blog.ID)
+	resp, _ = suite.do(suite.other, httptest.NewRequest(http.MethodDelete, url, nil))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = suite.do(suite.owner, httptest.NewRequest(http.MethodDelete, url, nil))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	_, body := suite.do(suite.owner, httptest.NewRequest(http.MethodGet, "/all-blogs-with-meta", nil))
+	assert.Nil(suite.T(), body["blogs"].([]interface{})[0].(map[string]interface{})["cover_image"])
+}
+
+// Test the identicon served to users without an uploaded avatar
+func (suite *ImagesTestSuite) TestIdenticonFallback() {
+	resp, err := suite.appAs(suite.other).Test(httptest.NewRequest(http.MethodGet, "/users/owner/avatar?size=64", nil))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "image/png", resp.Header.Get("Content-Type"))
+	img, err := png.Decode(resp.Body)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 64, img.Bounds().Dx())
+
+	resp, _ = suite.do(suite.other, httptest.NewRequest(http.MethodGet, "/users/owner/avatar?size=4096", nil))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	resp, _ = suite.do(suite.other, httptest.NewRequest(http.MethodGet, "/users/nobody/avatar", nil))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test uploading, serving and removing an avatar
+func (suite *ImagesTestSuite) TestAvatar() {
+	resp, body := suite.do(suite.owner, uploadRequest(http.MethodPut, "/me/avatar", "me.png", pngImage(500, 300)))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	avatar := body["avatar_url"].(string)
+	assert.FileExists(suite.T(), suite.stored(avatar))
+
+	resp, _ = suite.do(suite.other, httptest.NewRequest(http.MethodGet, "/users/owner/avatar", nil))
+	assert.Equal(suite.T(), http.StatusFound, resp.StatusCode)
+	assert.Equal(suite.T(), avatar, resp.Header.Get("Location"))
+
+	resp, body = suite.do(suite.owner, httptest.NewRequest(http.MethodDelete, "/me/avatar", nil))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Equal(suite.T(), "/api/users/owner/avatar", body["avatar_url"])
+	assert.NoFileExists(suite.T(), suite.stored(avatar))
+
+	resp, _ = suite.do(suite.owner, httptest.NewRequest(http.MethodDelete, "/me/avatar", nil))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test that deleting an account needs the password and removes everything
+func (suite *ImagesTestSuite) TestDeleteAccount() {
+	_, body := suite.setCover(suite.owner, 400, 300)
+	cover := body["cover_image"].(map[string]interface{})["url"].(string)
+	suite.db.Create(&model.Follow{FollowerID: suite.other.ID, FolloweeID: suite.owner.ID})
+	suite.db.Create(&model.Like{BlogID: suite.blog.ID, UserID: suite.other.ID})
+
+	deleteAccount := func(password string) *http.Response {
+		payload, _ := json.Marshal(map[string]string{"password": password})
+		req := httptest.NewRequest(http.MethodDelete, "/me", bytes.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, _ := suite.do(suite.owner, req)
+		return resp
+	}
+	assert.Equal(suite.T(), http.StatusUnauthorized, deleteAccount("wrong").StatusCode)
+	assert.Equal(suite.T(), http.StatusOK, deleteAccount("secret").StatusCode)
+
+	for table, want := range map[interface{}]int64{
+		&model.User{}: 1, &model.Blog{}: 0, &model.Like{}: 0, &model.Follow{}: 0, &model.Media{}: 0,
+	} {
+		var count int64
+		suite.db.Model(table).Count(&count)
+		assert.Equal(suite.T(), want, count)
+	}
+	assert.NoFileExists(suite.T(), suite.stored(cover))
+}
+
+func TestImagesSuite(t *testing.T) {
+	suite.Run(t, new(ImagesTestSuite))
+}
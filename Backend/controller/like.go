@@ -1,45 +1,67 @@
 package controller
 
 import (
-	"Gator_blog/database"
-	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/redis"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"errors"
+	"log"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func LikeBlog(c *fiber.Ctx) error {
-	userEmail := c.Locals("userEmail").(string)
-	var user model.User
-	if err := database.DBConn.Where("email = ?", userEmail).First(&user).Error; err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "User not found"})
-	}
+// LikeHandler serves the likes of a blog
+type LikeHandler struct {
+	likes *service.LikeService
+	users *service.UserService
+	store *redis.Store
+}
 
-	blogIDParam := c.Params("id")
-	var blog model.Blog
-	if err := database.DBConn.Where("id = ?", blogIDParam).First(&blog).Error; err != nil {
-		return c.Status(404).JSON(fiber.Map{"error": "Blog not found"})
-	}
+func NewLikeHandler(likes *service.LikeService, users *service.UserService, store *redis.Store) *LikeHandler {
+	return &LikeHandler{likes: likes, users: users, store: store}
+}
 
-	like := model.Like{
-		UserID: user.ID,
-		BlogID: blog.ID,
+// likes the blog, or takes the like back when the user already liked it
+func (h *LikeHandler) Toggle(c *fiber.Ctx) error {
+	userEmail, _ := c.Locals("userEmail").(string)
+	user, err := h.users.ByEmail(userEmail)
+	if err != nil {
+		return userProblem(err)
 	}
 
-	result := database.DBConn.Where("user_id = ? AND blog_id = ?", user.ID, blog.ID).Delete(&like)
-
-	if result.RowsAffected > 0 {
-		return c.Status(200).JSON(fiber.Map{"msg": "Like removed successfully"})
+	blogID := pathID(c, "id")
+	like, liked, err := h.likes.Toggle(user.ID, blogID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
+	if err != nil {
+		return problem.Failed("Failed to like blog", err)
 	}
 
-	if err := database.DBConn.Create(&like).Error; err != nil {
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to like blog"})
+	if !liked {
+		bumpBlogCounter(h.store, blogID, redis.CounterLikes, -1)
+		return c.Status(200).JSON(fiber.Map{"msg": "Like removed successfully"})
 	}
+	bumpBlogCounter(h.store, blogID, redis.CounterLikes, 1)
 	return c.Status(201).JSON(like)
 }
 
-func GetLikesByBlogID(c *fiber.Ctx) error {
-	blogID := c.Params("id")
-	var count int64
-	database.DBConn.Model(&model.Like{}).Where("blog_id = ?", blogID).Count(&count)
-	return c.JSON(fiber.Map{"likes": count})
+func (h *LikeHandler) Count(c *fiber.Ctx) error {
+	blogID := pathID(c, "id")
+	if blogID == 0 {
+		return c.JSON(fiber.Map{"likes": 0})
+	}
+	likes := blogCounter(h.store, blogID, redis.CounterLikes, func() (int64, bool) {
+		count, err := h.likes.Count(blogID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, false
+		}
+		if err != nil {
+			log.Println("Error counting likes", err)
+			return 0, false
+		}
+		return count, true
+	})
+	return c.JSON(fiber.Map{"likes": likes})
 }
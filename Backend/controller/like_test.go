@@ -1,8 +1,7 @@
 package controller_test
 
 import (
-	"Gator_blog/controller"
-	"Gator_blog/database"
+	"Gator_blog/database/dbtest"
 	"Gator_blog/model"
 	"encoding/json"
 	"fmt"
@@ -12,52 +11,45 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func setupTestDB() {
-	var err error
-	database.DBConn, err = gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		panic("Failed to connect to test database")
-	}
-
-	// Migrate the schema
-	database.DBConn.AutoMigrate(&model.User{}, &model.Blog{}, &model.Like{})
+func setupTestDB(t *testing.T) *gorm.DB {
+	return dbtest.Open(t, &model.User{}, &model.Blog{}, &model.Like{})
 }
 
-func setupApp() *fiber.App {
-	app := fiber.New()
+func setupApp(db *gorm.DB) *fiber.App {
+	deps := testContainer(db)
+	app := newApp()
 
 	// Setup routes for testing
 	app.Post("/blogs/:id/like", func(c *fiber.Ctx) error {
 		// Mock authentication middleware
 		c.Locals("userEmail", "test@example.com")
-		return controller.LikeBlog(c)
+		return deps.Likes.Toggle(c)
 	})
 
-	app.Get("/blogs/:id/likes", controller.GetLikesByBlogID)
+	app.Get("/blogs/:id/likes", deps.Likes.Count)
 
 	return app
 }
 
 func TestLikeBlog(t *testing.T) {
 	// Setup
-	setupTestDB()
-	app := setupApp()
+	db := setupTestDB(t)
+	app := setupApp(db)
 
 	// Create test user
 	user := model.User{
 		Email: "test@example.com",
 	}
-	database.DBConn.Create(&user)
+	db.Create(&user)
 
 	// Create test blog
 	blog := model.Blog{
 		Title: "Test Blog",
 	}
-	database.DBConn.Create(&blog)
+	db.Create(&blog)
 
 	// Test cases
 	t.Run("Successfully like a blog", func(t *testing.T) {
@@ -71,7 +63,7 @@ func TestLikeBlog(t *testing.T) {
 
 		// Check if like was created in DB
 		var count int64
-		database.DBConn.Model(&model.Like{}).Where("user_id = ? AND blog_id = ?", user.ID, blog.ID).Count(&count)
+		db.Model(&model.Like{}).Where("user_id = ? AND blog_id = ?", user.ID, blog.ID).Count(&count)
 		assert.Equal(t, int64(1), count)
 	})
 
@@ -87,45 +79,51 @@ func TestLikeBlog(t *testing.T) {
 		// Check response body
 		var respBody map[string]string
 		json.NewDecoder(resp.Body).Decode(&respBody)
-		assert.Equal(t, "Blog not found", respBody["error"])
+		assert.Equal(t, "Blog not found", respBody["detail"])
 	})
 
-	t.Run("Try to like a blog twice", func(t *testing.T) {
-		// First like
+	t.Run("Like a blog twice", func(t *testing.T) {
+		// The blog is liked already, liking it again takes the like back
 		req := httptest.NewRequest(http.MethodPost, "/blogs/"+fmt.Sprintf("%d", blog.ID)+"/like", nil)
-		_, _ = app.Test(req)
-
-		// Try to like again
-		req = httptest.NewRequest(http.MethodPost, "/blogs/"+fmt.Sprintf("%d", blog.ID)+"/like", nil)
 		resp, err := app.Test(req)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 		// Check response body
 		var respBody map[string]string
 		json.NewDecoder(resp.Body).Decode(&respBody)
-		assert.Equal(t, "Already liked", respBody["msg"])
+		assert.Equal(t, "Like removed successfully", respBody["msg"])
+
+		var count int64
+		db.Model(&model.Like{}).Where("user_id = ? AND blog_id = ?", user.ID, blog.ID).Count(&count)
+		assert.Equal(t, int64(0), count)
+
+		// and a third time likes it again
+		req = httptest.NewRequest(http.MethodPost, "/blogs/"+fmt.Sprintf("%d", blog.ID)+"/like", nil)
+		resp, err = app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
 	})
 }
 
 func TestGetLikesByBlogID(t *testing.T) {
 	// Setup
-	setupTestDB()
-	app := setupApp()
+	db := setupTestDB(t)
+	app := setupApp(db)
 
 	// Create test user
 	user := model.User{
 		Email: "test@example.com",
 	}
-	database.DBConn.Create(&user)
+	db.Create(&user)
 
 	// Create test blog
 	blog := model.Blog{
 		Title: "Test Blog",
 	}
-	database.DBConn.Create(&blog)
+	db.Create(&blog)
 
 	// Test cases
 	t.Run("Get likes count for blog with no likes", func(t *testing.T) {
@@ -149,7 +147,7 @@ func TestGetLikesByBlogID(t *testing.T) {
 			UserID: user.ID,
 			BlogID: blog.ID,
 		}
-		database.DBConn.Create(&like)
+		db.Create(&like)
 
 		// Setup request
 		req := httptest.NewRequest(http.MethodGet, "/blogs/"+fmt.Sprintf("%d", blog.ID)+"/likes", nil)
@@ -183,21 +181,22 @@ func TestGetLikesByBlogID(t *testing.T) {
 
 func TestLikeBlogWithMockedDependencies(t *testing.T) {
 	// Setup
-	setupTestDB()
+	db := setupTestDB(t)
 
 	// Create test blog
 	blog := model.Blog{
 		Title: "Test Blog",
 	}
-	database.DBConn.Create(&blog)
+	db.Create(&blog)
 
 	t.Run("User not found test", func(t *testing.T) {
 		// Setup custom app
-		customApp := fiber.New()
+		deps := testContainer(db)
+		customApp := newApp()
 		customApp.Post("/blogs/:id/like", func(c *fiber.Ctx) error {
 			// Mock different email that doesn't exist
 			c.Locals("userEmail", "nonexistent@example.com")
-			return controller.LikeBlog(c)
+			return deps.Likes.Toggle(c)
 		})
 
 		// Setup request
@@ -211,6 +210,6 @@ func TestLikeBlogWithMockedDependencies(t *testing.T) {
 		// Check response body
 		var respBody map[string]string
 		json.NewDecoder(resp.Body).Decode(&respBody)
-		assert.Equal(t, "User not found", respBody["error"])
+		assert.Equal(t, "User not found", respBody["detail"])
 	})
 }
\ No newline at end of file
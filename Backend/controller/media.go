@@ -0,0 +1,342 @@
+package controller
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/imaging"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// largest accepted upload in bytes
+const MaxUploadSize = 10 << 20
+
+// default and maximum page size of the media library
+const (
+	defaultMediaLimit = 30
+	maxMediaLimit     = 100
+)
+
+// a resized copy generated for every upload. Crop variants are cut to
+// exactly Width x Height, the others are scaled down to fit within it.
+type variantSpec struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// variants generated for images uploaded to the media library
+var libraryVariants = []variantSpec{
+	{Name: "thumbnail", Width: 200, Height: 200, Crop: true},
+	{Name: "medium", Width: 1024, Height: 1024},
+}
+
+// covers are cropped to the 1.91:1 ratio of link previews, avatars to squares
+var (
+	coverVariants = []variantSpec{
+		{Name: "cover", Width: 1200, Height: 630, Crop: true},
+		{Name: "cover_small", Width: 600, Height: 315, Crop: true},
+	}
+	avatarVariants = []variantSpec{
+		{Name: "avatar", Width: 256, Height: 256, Crop: true},
+		{Name: "avatar_small", Width: 64, Height: 64, Crop: true},
+	}
+)
+
+var errStorageNotConfigured = errors.New("upload storage not configured")
+
+// Uploads are the upload records of a MediaService with the storage holding
+// their files, nil when uploads are not configured
+type Uploads struct {
+	*service.MediaService
+	files storage.Storage
+}
+
+func NewUploads(media *service.MediaService, files storage.Storage) *Uploads {
+	return &Uploads{MediaService: media, files: files}
+}
+
+// MediaHandler serves uploads: the media library, blog covers and avatars
+type MediaHandler struct {
+	media *Uploads
+	blogs *service.BlogService
+	users *service.UserService
+	cache cache.Cache
+}
+
+func NewMediaHandler(media *Uploads, blogs *service.BlogService, users *service.UserService, responses cache.Cache) *MediaHandler {
+	return &MediaHandler{media: media, blogs: blogs, users: users, cache: responses}
+}
+
+// Uploads an image from the multipart field "file" to the signed in user's
+// media library
+func (h *MediaHandler) Upload(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Media uploaded successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	data, filename, err := readUpload(c)
+	if err != nil {
+		return err
+	}
+	media, err := storeImage(h.media, user.ID, model.MediaLibrary, filename, data, libraryVariants)
+	if err := uploadProblem(err); err != nil {
+		return err
+	}
+	context["media"] = media
+	return c.Status(201).JSON(context)
+}
+
+// Lists the signed in user's uploads, newest first. ?cursor is the id of the
+// last upload of the previous page.
+func (h *MediaHandler) List(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Media Library",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	limit := c.QueryInt("limit", defaultMediaLimit)
+	if limit < 1 || limit > maxMediaLimit {
+		return problem.BadRequest("Invalid limit")
+	}
+	var cursor uint
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return problem.BadRequest("Invalid cursor")
+		}
+		cursor = uint(parsed)
+	}
+
+	media, err := h.media.List(user.ID, model.MediaLibrary, cursor, limit)
+	if err != nil {
+		return problem.Failed("Could not fetch media", err)
+	}
+	for i := range media {
+		withURLs(h.media.files, &media[i])
+	}
+	context["media"] = media
+	if len(media) == limit {
+		context["next_cursor"] = strconv.FormatUint(uint64(media[len(media)-1].ID), 10)
+	}
+	return c.Status(200).JSON(context)
+}
+
+// Deletes one of the signed in user's uploads and its stored files. Covers
+// and avatars are removed through their own endpoints.
+func (h *MediaHandler) Delete(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Media deleted successfully",
+	}
+	user, err := signedInUser(c, h.users)
+	if err != nil {
+		return err
+	}
+
+	media, err := h.media.Owned(pathID(c, "id"), user.ID, model.MediaLibrary)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Media not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch media", err)
+	}
+	if err := deleteMedia(h.media, media); err != nil {
+		return problem.Failed("Could not delete media", err)
+	}
+	return c.Status(200).JSON(context)
+}
+
+// reads the multipart field "file", failing when it is missing or too large
+func readUpload(c *fiber.Ctx) ([]byte, string, error) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", problem.BadRequest("A file is required")
+	}
+	if header.Size > MaxUploadSize {
+		return nil, "", problem.New(413, problem.PayloadTooLarge, fmt.Sprintf("File is larger than %d MB", MaxUploadSize>>20))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", problem.BadRequest("Could not read file")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, MaxUploadSize+1))
+	if err != nil || len(data) > MaxUploadSize {
+		return nil, "", problem.BadRequest("Could not read file")
+	}
+	return data, filepath.Base(header.Filename), nil
+}
+
+// the problem answering an error from storeImage, nil when there was none
+func uploadProblem(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case imaging.ErrUnsupportedType:
+		return problem.New(415, problem.UnsupportedMediaType, "Only JPEG, PNG, GIF and WebP images are supported")
+	case imaging.ErrTooManyPixels:
+		return problem.New(413, problem.PayloadTooLarge, "Image dimensions are too large")
+	}
+	return problem.Failed("Could not store upload", err)
+}
+
+// decodes an uploaded image, stores a metadata free copy and its variants and
+// records them through media as uploads of the user with the given purpose
+func storeImage(uploads *Uploads, userID uint, purpose, filename string, data []byte, variants []variantSpec) (model.Media, error) {
+	if uploads.files == nil {
+		return model.Media{}, errStorageNotConfigured
+	}
+	img, sourceType, err := imaging.Decode(data)
+	if err != nil {
+		return model.Media{}, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return model.Media{}, err
+	}
+	prefix := fmt.Sprintf("media/%d/%s", userID, name)
+	media := model.Media{UserID: userID, Purpose: purpose, Filename: filename}
+	var stored []string
+
+	put := func(suffix string, img image.Image) (string, int64, error) {
+		body, contentType, err := imaging.Encode(img, sourceType)
+		if err != nil {
+			return "", 0, err
+		}
+		key := prefix + suffix + extension(contentType)
+		if err := uploads.files.Put(context.Background(), key, bytes.NewReader(body), int64(len(body)), contentType); err != nil {
+			return "", 0, err
+		}
+		stored = append(stored, key)
+		media.ContentType = contentType
+		return key, int64(len(body)), nil
+	}
+
+	err = func() error {
+		size := img.Bounds().Size()
+		media.Width, media.Height = size.X, size.Y
+		if media.Key, media.Size, err = put("", img); err != nil {
+			return err
+		}
+		for _, spec := range variants {
+			var resized image.Image
+			if spec.Crop {
+				resized = imaging.Fill(img, spec.Width, spec.Height)
+			} else {
+				resized = imaging.Fit(img, spec.Width, spec.Height)
+			}
+			key, size, err := put("_"+spec.Name, resized)
+			if err != nil {
+				return err
+			}
+			bounds := resized.Bounds().Size()
+			media.Variants = append(media.Variants, model.MediaVariant{
+				Name: spec.Name, Key: key, Width: bounds.X, Height: bounds.Y, Size: size,
+			})
+		}
+		return uploads.Create(&media)
+	}()
+	if err != nil {
+		for _, key := range stored {
+			uploads.files.Delete(context.Background(), key)
+		}
+		return model.Media{}, err
+	}
+	withURLs(uploads.files, &media)
+	return media, nil
+}
+
+// removes an upload's rows and then its stored files
+func deleteMedia(uploads *Uploads, media model.Media) error {
+	if err := uploads.Delete(&media); err != nil {
+		return err
+	}
+	deleteStoredFiles(uploads.files, media)
+	return nil
+}
+
+// removes an upload's original and variants from storage. Failures are only
+// logged, the rows are already gone.
+func deleteStoredFiles(files storage.Storage, media model.Media) {
+	if files == nil {
+		return
+	}
+	keys := []string{media.Key}
+	for _, variant := range media.Variants {
+		keys = append(keys, variant.Key)
+	}
+	for _, key := range keys {
+		if err := files.Delete(context.Background(), key); err != nil {
+			log.Println("Error deleting stored file", key, err)
+		}
+	}
+}
+
+// deletes the upload with the given id, if any. Used when a cover or avatar
+// is replaced or removed.
+func deleteMediaByID(uploads *Uploads, id *uint) {
+	if id == nil {
+		return
+	}
+	media, err := uploads.ByID(*id)
+	if err != nil {
+		return
+	}
+	if err := deleteMedia(uploads, media); err != nil {
+		log.Println("Error deleting media", media.ID, err)
+	}
+}
+
+// fills in the public URLs of an upload and its variants
+func withURLs(files storage.Storage, media *model.Media) {
+	if files == nil {
+		return
+	}
+	media.URL = files.URL(media.Key)
+	for i := range media.Variants {
+		media.Variants[i].URL = files.URL(media.Variants[i].Key)
+	}
+}
+
+// unguessable name for a stored file
+func randomName() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func extension(contentType string) string {
+	if contentType == "image/jpeg" {
+		return ".jpg"
+	}
+	return ".png"
+}
@@ -0,0 +1,187 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/storage"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// uses a temporary directory as upload storage for the test
+func setupTestStorage(t *testing.T) string {
+	root := t.TempDir()
+	storage.Default = storage.NewLocal(root, "/uploads")
+	t.Cleanup(func() { storage.Default = nil })
+	return root
+}
+
+// encodes a w x h PNG
+func pngImage(w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// builds a multipart request carrying data in the field "file"
+func uploadRequest(method, url, filename string, data []byte) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write(data)
+	writer.Close()
+	req := httptest.NewRequest(method, url, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// Define the test suite for media uploads
+type MediaTestSuite struct {
+	suite.Suite
+	db    *gorm.DB
+	root  string
+	owner model.User
+	other model.User
+}
+
+// Setup before each test
+func (suite *MediaTestSuite) SetupTest() {
+	setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+	suite.root = setupTestStorage(suite.T())
+
+	suite.owner = model.User{Username: "owner", Email: "owner@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.owner)
+	suite.other = model.User{Username: "other", Email: "other@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.other)
+}
+
+func (suite *MediaTestSuite) appAs(user model.User) *fiber.App {
+	app := fiber.New(fiber.Config{BodyLimit: controller.MaxUploadSize + 1<<20, ErrorHandler: problem.Handler})
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	deps := testContainer(suite.db)
+	app.Post("/media", deps.Media.Upload)
+	app.Get("/me/media", deps.Media.List)
+	app.Delete("/media/:id", deps.Media.Delete)
+	return app
+}
+
+func (suite *MediaTestSuite) do(user model.User, req *http.Request) (*http.Response, map[string]interface{}) {
+	resp, err := suite.appAs(user).Test(req, -1)
+	assert.Nil(suite.T(), err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func (suite *MediaTestSuite) upload(data []byte) (*http.Response, map[string]interface{}) {
+	return suite.do(suite.owner, uploadRequest(http.MethodPost, "/media", "photo.png", data))
+}
+
+// path on disk of an uploaded file from its URL
+func (suite *MediaTestSuite) stored(url string) string {
+	return filepath.Join(suite.root, filepath.FromSlash(strings.TrimPrefix(url, "/uploads/")))
+}
+
+// Test uploading an image and its generated variants
+func (suite *MediaTestSuite) TestUpload() {
+	resp, body := suite.upload(pngImage(1600, 800))
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	media := body["media"].(map[string]interface{})
+	assert.Equal(suite.T(), "image/png", media["content_type"])
+	assert.Equal(suite.T(), "photo.png", media["filename"])
+	assert.Equal(suite.T(), float64(1600), media["width"])
+	assert.NotContains(suite.T(), media, "Key")
+	assert.FileExists(suite.T(), suite.stored(media["url"].(string)))
+
+	sizes := map[string][2]float64{}
+	for _, v := range media["variants"].([]interface{}) {
+		variant := v.(map[string]interface{})
+		sizes[variant["name"].(string)] = [2]float64{variant["width"].(float64), variant["height"].(float64)}
+		assert.FileExists(suite.T(), suite.stored(variant["url"].(string)))
+	}
+	assert.Equal(suite.T(), map[string][2]float64{"thumbnail": {200, 200}, "medium": {1024, 512}}, sizes)
+}
+
+// Test rejected uploads
+func (suite *MediaTestSuite) TestUploadRejected() {
+	resp, _ := suite.upload([]byte("#!/bin/sh\necho not an image\n"))
+	assert.Equal(suite.T(), http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, _ = suite.upload(make([]byte, controller.MaxUploadSize+1))
+	assert.Equal(suite.T(), http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	req := httptest.NewRequest(http.MethodPost, "/media", nil)
+	resp, _ = suite.do(suite.owner, req)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	var count int64
+	suite.db.Model(&model.Media{}).Count(&count)
+	assert.Equal(suite.T(), int64(0), count)
+	entries, _ := os.ReadDir(suite.root)
+	assert.Empty(suite.T(), entries)
+}
+
+// Test listing and deleting media
+func (suite *MediaTestSuite) TestLibrary() {
+	var ids []float64
+	for i := 0; i < 3; i++ {
+		_, body := suite.upload(pngImage(10+i, 10))
+		ids = append(ids, body["media"].(map[string]interface{})["id"].(float64))
+	}
+
+	_, body := suite.do(suite.owner, httptest.NewRequest(http.MethodGet, "/me/media?limit=2", nil))
+	assert.Len(suite.T(), body["media"], 2)
+	_, body = suite.do(suite.owner, httptest.NewRequest(http.MethodGet, "/me/media?limit=2&cursor="+body["next_cursor"].(string), nil))
+	page := body["media"].([]interface{})
+	assert.Len(suite.T(), page, 1)
+	assert.Equal(suite.T(), ids[0], page[0].(map[string]interface{})["id"])
+
+	_, body = suite.do(suite.other, httptest.NewRequest(http.MethodGet, "/me/media", nil))
+	assert.Empty(suite.T(), body["media"])
+
+	url := fmt.Sprintf("/media/%v", ids[0])
+	file := suite.stored(page[0].(map[string]interface{})["url"].(string))
+	resp, _ := suite.do(suite.other, httptest.NewRequest(http.MethodDelete, url, nil))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	assert.FileExists(suite.T(), file)
+
+	resp, _ = suite.do(suite.owner, httptest.NewRequest(http.MethodDelete, url, nil))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.NoFileExists(suite.T(), file)
+	var variants int64
+	suite.db.Model(&model.MediaVariant{}).Where("media_id = ?", ids[0]).Count(&variants)
+	assert.Equal(suite.T(), int64(0), variants)
+}
+
+// Run the test suite
+func TestMediaSuite(t *testing.T) {
+	suite.Run(t, new(MediaTestSuite))
+}
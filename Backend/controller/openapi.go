@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/openapi"
+	"Gator_blog/ranking"
+	"Gator_blog/validate"
+	"fmt"
+	"net/http"
+)
+
+// APISpec describes every route under /api: v1, deprecated, and the same
+// routes under /api/v2 in their v2 shapes, see V2. Request bodies are those
+// of requests.go, with the limits of the validation settings in effect, so
+// call it after validate.Init. A test checks the operations against the
+// routes of router.SetupRoutes.
+func APISpec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:   "Gator Blog API",
+		Version: "2.0.0",
+		Description: "Failures are answered as application/problem+json, clients branch on its code. " +
+			"Send the token of /api/signin or /api/signup as the Authorization header. " +
+			"The v1 operations under /api are deprecated, their answers carry Deprecation and Sunset headers " +
+			"and Link the same operation under /api/v2.",
+	})
+	// tags are sent as names or {"name": ...} objects and always come back
+	// as objects
+	tagMax := 50
+	tagName := &openapi.Schema{Type: openapi.Types{"string"}, MaxLength: &tagMax, Pattern: validate.TagPattern}
+	doc.Generator().Define(model.Tag{}, openapi.AnyOf(tagName, &openapi.Schema{
+		Type:       openapi.Types{"object"},
+		Properties: map[string]*openapi.Schema{"name": tagName, "id": openapi.Integer()},
+		Required:   []string{"name"},
+	}))
+
+	for _, route := range apiRoutes() {
+		v2 := v2Route(route, doc)
+		doc.Add(v2.method, v2.path, v2.op)
+		route.op.ID += "V1"
+		route.op.Deprecated = true
+		doc.Add(route.method, route.path, route.op)
+	}
+
+	// This document, which is not versioned
+	doc.Add("GET", "/api/openapi.json", openapi.Op{ID: "getOpenAPI", Tag: "meta", Summary: "This OpenAPI document",
+		Responses: map[int]interface{}{200: openapi.Raw{Media: []string{"application/json"}, Description: "The document"}}})
+	doc.Add("GET", "/api/docs", openapi.Op{ID: "getDocs", Tag: "meta", Summary: "Browsable documentation of this API",
+		Responses: map[int]interface{}{200: openapi.Raw{Media: []string{"text/html"}, Description: "The documentation page"}}})
+	return doc
+}
+
+type apiRoute struct {
+	method, path string
+	op           openapi.Op
+}
+
+// body of a successful response: statusText, msg and the members of fields
+func reply(fields openapi.Object) openapi.Object {
+	out := openapi.Object{"statusText": "", "msg": ""}
+	for name, value := range fields {
+		out[name] = value
+	}
+	return out
+}
+
+// body of a response that only carries a message
+var message = openapi.Object{"msg": ""}
+
+func limitParam(def, max int) *openapi.Parameter {
+	one, most := int64(1), int64(max)
+	return openapi.Query("limit", &openapi.Schema{Type: openapi.Types{"integer"}, Minimum: &one, Maximum: &most},
+		fmt.Sprintf("page size, %d by default", def))
+}
+
+// cursor of the next page, the next_cursor of the previous one
+var cursorParam = openapi.Query("cursor", openapi.Integer(), "next_cursor of the previous page")
+
+var (
+	searchParam  = openapi.Query("search", openapi.String(), "words to look for in titles and posts")
+	ifMatch      = openapi.Header(http.CanonicalHeaderKey("If-Match"), `ETag or "v{version}" the change was made on, refused with 412 when stale`)
+	ifNoneMatch  = openapi.Header(http.CanonicalHeaderKey("If-None-Match"), "ETag of a copy held, answered with 304 while it is current")
+	feedFormat   = map[string]*openapi.Schema{"format": openapi.Enum("rss", "atom", "json")}
+	feedMedia    = openapi.Raw{Media: []string{"application/rss+xml", "application/atom+xml", "application/feed+json"}, Description: "The feed"}
+	unchanged    = openapi.Raw{Description: "The copy held is current"}
+	imageUpload  = &openapi.Schema{Type: openapi.Types{"object"}, Required: []string{"file"}, Properties: map[string]*openapi.Schema{"file": {Type: openapi.Types{"string"}, ContentMediaType: "application/octet-stream", Description: fmt.Sprintf("JPEG, PNG, GIF or WebP image of at most %d MB", MaxUploadSize>>20)}}}
+	multipart    = []string{"multipart/form-data"}
+	mergePatch   = []string{mimeMergePatch, "application/json"}
+	readingList  = reply(openapi.Object{"reading_list": model.ReadingList{}, "share_url": "", "blogs": []BlogWithMeta{}})
+	listCreated  = reply(openapi.Object{"reading_list": model.ReadingList{}, "share_url": ""})
+	blogListPage = reply(openapi.Object{"blogs": []BlogWithMeta{}, "next_cursor": openapi.Optional{Value: ""}})
+)
+
+func apiRoutes() []apiRoute {
+	windows := make([]string, len(ranking.Windows))
+	for i, w := range ranking.Windows {
+		windows[i] = string(w)
+	}
+	day := &openapi.Schema{Type: openapi.Types{"string"}, Pattern: `^\d{4}-\d{2}-\d{2}$`}
+	size := &openapi.Schema{Type: openapi.Types{"integer"}, Minimum: ptr(int64(minIdenticonSize)), Maximum: ptr(int64(maxIdenticonSize))}
+
+	return []apiRoute{
+		// Accounts
+		{"POST", "/api/signin", openapi.Op{ID: "signIn", Tag: "auth", Summary: "Sign in and get a session token",
+			Body:      signInRequest{},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"token": "", "username": "", "email": "", "avatar_url": ""})}}},
+		{"POST", "/api/signup", openapi.Op{ID: "signUp", Tag: "auth", Summary: "Register and get a session token",
+			Body:      signUpRequest{},
+			Responses: map[int]interface{}{201: reply(openapi.Object{"token": ""})}}},
+		{"POST", "/api/request-reset-code", openapi.Op{ID: "requestResetCode", Tag: "auth", Summary: "Mail a password reset code",
+			Body: resetCodeRequest{}, Responses: map[int]interface{}{200: message}}},
+		{"POST", "/api/verify-reset-code", openapi.Op{ID: "verifyResetCode", Tag: "auth", Summary: "Check a password reset code",
+			Body: verifyCodeRequest{}, Responses: map[int]interface{}{200: message}}},
+		{"POST", "/api/reset-password", openapi.Op{ID: "resetPassword", Tag: "auth", Summary: "Set a new password after verifying a reset code",
+			Body: resetPasswordRequest{}, Responses: map[int]interface{}{200: message}}},
+		{"DELETE", "/api/me", openapi.Op{ID: "deleteAccount", Tag: "auth", Auth: openapi.AuthRequired,
+			Summary: "Delete the signed in user with everything they wrote and uploaded",
+			Body:    deleteAccountRequest{}, Responses: map[int]interface{}{200: reply(nil)}}},
+
+		// Blogs
+		{"GET", "/api/blogs", openapi.Op{ID: "listBlogs", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "List the signed in user's blogs",
+			Params:    []*openapi.Parameter{openapi.Query("title", openapi.String(), "only blogs with this title"), ifNoneMatch},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blogs": []model.Blog{}}), 304: unchanged}}},
+		{"POST", "/api/blogs", openapi.Op{ID: "createBlog", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "Publish a blog",
+			Body:      createBlogRequest{},
+			Responses: map[int]interface{}{201: reply(openapi.Object{"blog": model.Blog{}})}}},
+		{"GET", "/api/blogs/:id", openapi.Op{ID: "getBlog", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "Fetch a blog",
+			Description: "The ETag names the version of the blog, send it back as If-Match to update that version only.",
+			Params:      []*openapi.Parameter{ifNoneMatch},
+			Responses:   map[int]interface{}{200: reply(openapi.Object{"blog": model.Blog{}}), 304: unchanged}}},
+		{"PUT", "/api/blogs/:id", openapi.Op{ID: "updateBlog", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "Change a blog, fields left out keep their value",
+			Params: []*openapi.Parameter{ifMatch}, Body: updateBlogRequest{},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blog": model.Blog{}})}}},
+		{"PATCH", "/api/blogs/:id", openapi.Op{ID: "patchBlog", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "Change a blog with a JSON Merge Patch",
+			Description: "null removes the tags, title and post cannot be removed and no other member may be sent.",
+			Params:      []*openapi.Parameter{ifMatch}, Body: updateBlogRequest{}, BodyMedia: mergePatch,
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blog": model.Blog{}})}}},
+		{"DELETE", "/api/blogs/:id", openapi.Op{ID: "deleteBlog", Tag: "blogs", Auth: openapi.AuthRequired, Summary: "Delete a blog",
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blog": model.Blog{}})}}},
+		{"GET", "/api/blogs-with-meta", openapi.Op{ID: "listBlogsWithMeta", Tag: "blogs", Auth: openapi.AuthRequired,
+			Summary:   "List the signed in user's blogs with their counters and latest comments",
+			Params:    []*openapi.Parameter{searchParam, ifNoneMatch},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blogs": []BlogWithMeta{}}), 304: unchanged}}},
+		{"GET", "/api/all-blogs-with-meta", openapi.Op{ID: "listAllBlogsWithMeta", Tag: "blogs", Auth: openapi.AuthOptional,
+			Summary:   "List every blog with its counters and latest comments",
+			Params:    []*openapi.Parameter{searchParam, ifNoneMatch},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blogs": []BlogWithMeta{}}), 304: unchanged}}},
+		{"GET", "/api/top-popular-blogs", openapi.Op{ID: "listPopularBlogs", Tag: "blogs", Auth: openapi.AuthOptional,
+			Summary: "List the hottest blogs, ranked by likes, comments and views decayed with age",
+			Params: []*openapi.Parameter{openapi.Query("window", openapi.Enum(windows...), "period ranked, week by default"),
+				limitParam(defaultPopularLimit, maxPopularLimit), ifNoneMatch},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blogs": []BlogWithMeta{}}), 304: unchanged}}},
+		{"GET", "/api/blogs/:id/meta", openapi.Op{ID: "getBlogMeta", Tag: "blogs", Summary: "Search engine and social card metadata of a blog",
+			Responses: map[int]interface{}{200: BlogSEO{}}}},
+
+		// Comments and likes
+		{"GET", "/api/blogs/:id/comments", openapi.Op{ID: "listComments", Tag: "comments", Auth: openapi.AuthRequired, Summary: "List the comments of a blog",
+			Params:    []*openapi.Parameter{ifNoneMatch},
+			Responses: map[int]interface{}{200: []model.Comment{}, 304: unchanged}}},
+		{"POST", "/api/blogs/:id/comments", openapi.Op{ID: "addComment", Tag: "comments", Auth: openapi.AuthRequired, Summary: "Comment on a blog",
+			Body: commentRequest{}, Responses: map[int]interface{}{201: model.Comment{}}}},
+		{"PUT", "/api/blogs/:id/comments/:commentId", openapi.Op{ID: "editComment", Tag: "comments", Auth: openapi.AuthRequired, Summary: "Change one of your comments",
+			Params: []*openapi.Parameter{ifMatch}, Body: commentRequest{},
+			Responses: map[int]interface{}{200: openapi.Object{"msg": "", "comment": model.Comment{}}}}},
+		{"DELETE", "/api/blogs/:id/comments/:commentId", openapi.Op{ID: "deleteComment", Tag: "comments", Auth: openapi.AuthRequired, Summary: "Delete one of your comments",
+			Responses: map[int]interface{}{200: message}}},
+		{"POST", "/api/blogs/:id/likes", openapi.Op{ID: "toggleLike", Tag: "likes", Auth: openapi.AuthRequired,
+			Summary:   "Like a blog, or take the like back when it is already liked",
+			Responses: map[int]interface{}{201: model.Like{}, 200: message}}},
+		{"GET", "/api/blogs/:id/likes", openapi.Op{ID: "countLikes", Tag: "likes", Auth: openapi.AuthRequired, Summary: "Count the likes of a blog",
+			Responses: map[int]interface{}{200: openapi.Object{"likes": int64(0)}}}},
+
+		// People
+		{"GET", "/api/users/:username", openapi.Op{ID: "getUserProfile", Tag: "users", Auth: openapi.AuthOptional,
+			Summary: "Public profile of a user with a page of their posts, newest first",
+			Params:  []*openapi.Parameter{limitParam(defaultProfilePostsLimit, maxProfilePostsLimit), cursorParam},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"profile": Profile{}, "blogs": []BlogWithMeta{},
+				"next_cursor": openapi.Optional{Value: ""}})}}},
+		{"GET", "/api/users/:username/avatar", openapi.Op{ID: "getUserAvatar", Tag: "users",
+			Summary: "Avatar of a user, their identicon when they have not uploaded one",
+			Params:  []*openapi.Parameter{openapi.Query("size", size, fmt.Sprintf("identicon size in pixels, %d by default", defaultIdenticonSize))},
+			Responses: map[int]interface{}{200: openapi.Raw{Media: []string{"image/png"}, Description: "The identicon"},
+				302: openapi.Raw{Description: "Redirects to the uploaded avatar"}}}},
+		{"GET", "/api/users/:username/followers", openapi.Op{ID: "listFollowers", Tag: "users", Summary: "List the followers of a user",
+			Params: []*openapi.Parameter{limitParam(defaultFollowLimit, maxFollowLimit), cursorParam},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"count": int64(0), "users": []PublicUser{},
+				"next_cursor": openapi.Optional{Value: uint(0)}})}}},
+		{"GET", "/api/users/:username/following", openapi.Op{ID: "listFollowing", Tag: "users", Summary: "List the users a user follows",
+			Params: []*openapi.Parameter{limitParam(defaultFollowLimit, maxFollowLimit), cursorParam},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"count": int64(0), "users": []PublicUser{},
+				"next_cursor": openapi.Optional{Value: uint(0)}})}}},
+		{"POST", "/api/users/:username/follow", openapi.Op{ID: "followUser", Tag: "users", Auth: openapi.AuthRequired, Summary: "Follow a user",
+			Responses: map[int]interface{}{201: model.Follow{}, 200: message}}},
+		{"DELETE", "/api/users/:username/follow", openapi.Op{ID: "unfollowUser", Tag: "users", Auth: openapi.AuthRequired, Summary: "Stop following a user",
+			Responses: map[int]interface{}{200: message}}},
+		{"GET", "/api/feed", openapi.Op{ID: "getFeed", Tag: "users", Auth: openapi.AuthRequired, Summary: "Posts of the users you follow, newest first",
+			Params:    []*openapi.Parameter{limitParam(defaultFeedLimit, maxFeedLimit), cursorParam},
+			Responses: map[int]interface{}{200: blogListPage}}},
+		{"GET", "/api/me/analytics", openapi.Op{ID: "getMyAnalytics", Tag: "users", Auth: openapi.AuthRequired,
+			Summary: "Daily views, readers, likes and comments of your posts",
+			Params: []*openapi.Parameter{openapi.Query("from", day, fmt.Sprintf("first day, %d days before to by default", defaultAnalyticsDays-1)),
+				openapi.Query("to", day, "last day, today by default")},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"from": "", "to": "", "totals": AnalyticsTotals{}, "posts": []PostAnalytics{}})}}},
+
+		// Syndication
+		{"GET", "/api/feeds/:format", openapi.Op{ID: "getSiteFeed", Tag: "feeds", Summary: "Latest posts of the site",
+			Path: feedFormat, Params: []*openapi.Parameter{ifNoneMatch}, Responses: map[int]interface{}{200: feedMedia, 304: unchanged}}},
+		{"GET", "/api/feeds/authors/:username/:format", openapi.Op{ID: "getAuthorFeed", Tag: "feeds", Summary: "Latest posts of an author",
+			Path: feedFormat, Params: []*openapi.Parameter{ifNoneMatch}, Responses: map[int]interface{}{200: feedMedia, 304: unchanged}}},
+		{"GET", "/api/feeds/tags/:tag/:format", openapi.Op{ID: "getTagFeed", Tag: "feeds", Summary: "Latest posts with a tag",
+			Path: feedFormat, Params: []*openapi.Parameter{ifNoneMatch}, Responses: map[int]interface{}{200: feedMedia, 304: unchanged}}},
+
+		// Media
+		{"POST", "/api/media", openapi.Op{ID: "uploadMedia", Tag: "media", Auth: openapi.AuthRequired, Summary: "Upload an image to your library",
+			Body: imageUpload, BodyMedia: multipart, Responses: map[int]interface{}{201: reply(openapi.Object{"media": model.Media{}})}}},
+		{"GET", "/api/me/media", openapi.Op{ID: "listMyMedia", Tag: "media", Auth: openapi.AuthRequired, Summary: "List your image library, newest first",
+			Params:    []*openapi.Parameter{limitParam(defaultMediaLimit, maxMediaLimit), cursorParam},
+			Responses: map[int]interface{}{200: reply(openapi.Object{"media": []model.Media{}, "next_cursor": openapi.Optional{Value: ""}})}}},
+		{"DELETE", "/api/media/:id", openapi.Op{ID: "deleteMedia", Tag: "media", Auth: openapi.AuthRequired, Summary: "Delete an image of your library",
+			Responses: map[int]interface{}{200: reply(nil)}}},
+		{"PUT", "/api/blogs/:id/cover", openapi.Op{ID: "setBlogCover", Tag: "media", Auth: openapi.AuthRequired, Summary: "Upload the cover image of one of your blogs",
+			Body: imageUpload, BodyMedia: multipart, Responses: map[int]interface{}{200: reply(openapi.Object{"cover_image": &CoverImage{}})}}},
+		{"DELETE", "/api/blogs/:id/cover", openapi.Op{ID: "removeBlogCover", Tag: "media", Auth: openapi.AuthRequired, Summary: "Remove the cover image of one of your blogs",
+			Responses: map[int]interface{}{200: reply(nil)}}},
+		{"PUT", "/api/me/avatar", openapi.Op{ID: "setAvatar", Tag: "media", Auth: openapi.AuthRequired, Summary: "Upload your avatar",
+			Body: imageUpload, BodyMedia: multipart, Responses: map[int]interface{}{200: reply(openapi.Object{"avatar_url": ""})}}},
+		{"DELETE", "/api/me/avatar", openapi.Op{ID: "removeAvatar", Tag: "media", Auth: openapi.AuthRequired, Summary: "Remove your avatar, falling back to your identicon",
+			Responses: map[int]interface{}{200: reply(openapi.Object{"avatar_url": ""})}}},
+
+		// Bookmarks and reading lists
+		{"POST", "/api/blogs/:id/bookmark", openapi.Op{ID: "bookmarkBlog", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "Bookmark a blog",
+			Responses: map[int]interface{}{201: model.Bookmark{}, 200: model.Bookmark{}}}},
+		{"DELETE", "/api/blogs/:id/bookmark", openapi.Op{ID: "removeBookmark", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "Remove a bookmark",
+			Responses: map[int]interface{}{200: message}}},
+		{"GET", "/api/me/bookmarks", openapi.Op{ID: "listBookmarks", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "List your bookmarked blogs",
+			Responses: map[int]interface{}{200: reply(openapi.Object{"blogs": []BlogWithMeta{}})}}},
+		{"GET", "/api/me/reading-lists", openapi.Op{ID: "listReadingLists", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "List your reading lists",
+			Responses: map[int]interface{}{200: reply(openapi.Object{"reading_lists": []model.ReadingList{}})}}},
+		{"POST", "/api/me/reading-lists", openapi.Op{ID: "createReadingList", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "Create a reading list",
+			Body: createReadingListRequest{}, Responses: map[int]interface{}{201: listCreated}}},
+		{"GET", "/api/me/reading-lists/:listId", openapi.Op{ID: "getReadingList", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "Fetch one of your reading lists with its blogs in order",
+			Responses: map[int]interface{}{200: readingList}}},
+		{"PUT", "/api/me/reading-lists/:listId", openapi.Op{ID: "updateReadingList", Tag: "reading lists", Auth: openapi.AuthRequired,
+			Summary: "Rename a reading list or share it, fields left out keep their value",
+			Body:    updateReadingListRequest{}, Responses: map[int]interface{}{200: listCreated}}},
+		{"DELETE", "/api/me/reading-lists/:listId", openapi.Op{ID: "deleteReadingList", Tag: "reading lists", Auth: openapi.AuthRequired, Summary: "Delete a reading list",
+			Responses: map[int]interface{}{200: reply(nil)}}},
+		{"POST", "/api/me/reading-lists/:listId/items", openapi.Op{ID: "addReadingListItem", Tag: "reading lists", Auth: openapi.AuthRequired,
+			Summary: "Add a blog to the end of a reading list",
+			Body:    readingListItemRequest{},
+			Responses: map[int]interface{}{201: reply(openapi.Object{"item": model.ReadingListItem{}}),
+				200: reply(openapi.Object{"item": model.ReadingListItem{}})}}},
+		{"DELETE", "/api/me/reading-lists/:listId/items/:blogId", openapi.Op{ID: "removeReadingListItem", Tag: "reading lists", Auth: openapi.AuthRequired,
+			Summary: "Remove a blog from a reading list", Responses: map[int]interface{}{200: reply(nil)}}},
+		{"PUT", "/api/me/reading-lists/:listId/order", openapi.Op{ID: "reorderReadingList", Tag: "reading lists", Auth: openapi.AuthRequired,
+			Summary: "Put a reading list in a new order, listing every blog in it once",
+			Body:    reorderReadingListRequest{}, Responses: map[int]interface{}{200: readingList}}},
+		{"GET", "/api/reading-lists/shared/:token", openapi.Op{ID: "getSharedReadingList", Tag: "reading lists", Auth: openapi.AuthOptional,
+			Summary: "Fetch a reading list shared by its owner", Responses: map[int]interface{}{200: readingList}}},
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
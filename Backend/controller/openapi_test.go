@@ -0,0 +1,163 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/openapi"
+	"Gator_blog/router"
+	"Gator_blog/validate"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fiberParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+func TestAPISpecCoversRoutes(t *testing.T) {
+	db := openTestDB(t)
+	app := newApp()
+	router.SetupRoutes(app, testContainer(db))
+
+	var served []string
+	for _, route := range app.GetRoutes(true) {
+		if !strings.HasPrefix(route.Path, "/api/") || route.Method == fiber.MethodHead {
+			continue
+		}
+		served = append(served, route.Method+" "+fiberParam.ReplaceAllString(route.Path, "{$1}"))
+	}
+	var documented []string
+	ids := map[string]bool{}
+	for _, op := range controller.APISpec().Operations() {
+		documented = append(documented, op.Method+" "+op.Path)
+		assert.False(t, ids[op.OperationID], "operation id %s is used twice", op.OperationID)
+		ids[op.OperationID] = true
+	}
+	sort.Strings(served)
+	sort.Strings(documented)
+	assert.Equal(t, served, documented)
+}
+
+func TestAPISpecDocument(t *testing.T) {
+	app := newApp()
+	app.Get("/api/openapi.json", openapi.Serve(controller.APISpec()))
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	// request limits follow the validation settings
+	title := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})["CreateBlogRequest"].(map[string]interface{})["properties"].(map[string]interface{})["title"].(map[string]interface{})
+	assert.EqualValues(t, validate.Limits().TitleMax, title["maxLength"])
+}
+
+// the app as the server runs it in development, failing the test on any
+// response the document does not allow
+func strictApp(t *testing.T) *fiber.App {
+	db := openTestDB(t)
+	setupTestRedis(t)
+	app := newApp()
+	app.Use(openapi.Validator(controller.APISpec(), func(c *fiber.Ctx, errs validate.Errors) {
+		t.Errorf("%s %s answered %d against the document: %v\n%s", c.Method(), c.OriginalURL(),
+			c.Response().StatusCode(), errs, c.Response().Body())
+	}))
+	router.SetupRoutes(app, testContainer(db))
+	return app
+}
+
+func call(t *testing.T, app *fiber.App, method, url, token string, body interface{}) (int, map[string]interface{}) {
+	var reader *bytes.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, url, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	var out map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestAPISpecMatchesResponses(t *testing.T) {
+	app := strictApp(t)
+
+	status, body := call(t, app, "POST", "/api/signup", "", map[string]string{
+		"username": "writer", "email": "writer@example.com", "password": "password123"})
+	require.Equal(t, http.StatusCreated, status, body)
+	token := body["token"].(string)
+
+	status, body = call(t, app, "POST", "/api/blogs", token, map[string]interface{}{
+		"title": "Hello", "post": "World", "tags": []interface{}{"go", map[string]string{"name": "web"}}})
+	require.Equal(t, http.StatusCreated, status, body)
+	id := body["blog"].(map[string]interface{})["ID"]
+	blog := fmt.Sprintf("/api/blogs/%v", id)
+
+	for _, step := range []struct {
+		method, url string
+		body        interface{}
+		status      int
+	}{
+		{"GET", blog, nil, http.StatusOK},
+		{"GET", "/api/blogs", nil, http.StatusOK},
+		{"GET", "/api/blogs-with-meta", nil, http.StatusOK},
+		{"GET", "/api/all-blogs-with-meta", nil, http.StatusOK},
+		{"GET", "/api/top-popular-blogs?window=all", nil, http.StatusOK},
+		{"PUT", blog, map[string]string{"title": "Hello again"}, http.StatusOK},
+		{"POST", blog + "/comments", map[string]string{"content": "Nice"}, http.StatusCreated},
+		{"GET", blog + "/comments", nil, http.StatusOK},
+		{"POST", blog + "/likes", nil, http.StatusCreated},
+		{"GET", blog + "/likes", nil, http.StatusOK},
+		{"POST", blog + "/likes", nil, http.StatusOK},
+		{"GET", blog + "/meta", nil, http.StatusOK},
+		{"POST", blog + "/bookmark", nil, http.StatusCreated},
+		{"GET", "/api/me/bookmarks", nil, http.StatusOK},
+		{"DELETE", blog + "/bookmark", nil, http.StatusOK},
+		{"POST", "/api/me/reading-lists", map[string]string{"name": "Later"}, http.StatusCreated},
+		{"GET", "/api/me/reading-lists", nil, http.StatusOK},
+		{"GET", "/api/me/media", nil, http.StatusOK},
+		{"GET", "/api/users/writer", nil, http.StatusOK},
+		{"GET", "/api/users/writer/followers", nil, http.StatusOK},
+		{"GET", "/api/feed", nil, http.StatusOK},
+		{"GET", "/api/me/analytics", nil, http.StatusOK},
+		{"GET", "/api/blogs/999", nil, http.StatusNotFound},
+	} {
+		status, body := call(t, app, step.method, step.url, token, step.body)
+		assert.Equal(t, step.status, status, "%s %s: %v", step.method, step.url, body)
+	}
+}
+
+func TestAPISpecRefusesRequests(t *testing.T) {
+	app := strictApp(t)
+
+	status, body := call(t, app, "POST", "/api/signup", "", map[string]interface{}{
+		"username": "a", "email": 7})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "validation_failed", body["code"])
+	fields := map[string]bool{}
+	for _, fe := range body["errors"].([]interface{}) {
+		fields[fe.(map[string]interface{})["field"].(string)] = true
+	}
+	assert.Equal(t, map[string]bool{"username": true, "email": true, "password": true}, fields)
+
+	status, body = call(t, app, "GET", "/api/top-popular-blogs?window=year", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "validation_failed", body["code"])
+}
@@ -0,0 +1,59 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopularBlogsRanking(t *testing.T) {
+	db := openTestDB(t)
+	setupTestRedis(t)
+
+	old := model.Blog{Title: "Old favourite", Post: "content", UserName: "author", CreatedAt: time.Now().AddDate(-1, 0, 0)}
+	fresh := model.Blog{Title: "Fresh", Post: "content", UserName: "author", CreatedAt: time.Now().Add(-time.Hour)}
+	db.Create(&old)
+	db.Create(&fresh)
+	for i := 1; i <= 10; i++ {
+		db.Create(&model.Like{UserID: uint(i), BlogID: old.ID})
+	}
+	db.Create(&model.Like{UserID: 1, BlogID: fresh.ID})
+
+	tests := []struct {
+		url    string
+		status int
+		titles []string
+	}{
+		{"/top-popular-blogs", http.StatusOK, []string{"Fresh", "Old favourite"}},
+		{"/top-popular-blogs?limit=1", http.StatusOK, []string{"Fresh"}},
+		{"/top-popular-blogs?window=day", http.StatusOK, []string{"Fresh"}},
+		{"/top-popular-blogs?window=decade", http.StatusBadRequest, nil},
+		{"/top-popular-blogs?limit=0", http.StatusBadRequest, nil},
+		{"/top-popular-blogs?limit=500", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		resp, err := metaApp(db).Test(httptest.NewRequest(http.MethodGet, tt.url, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.url)
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var result struct {
+			Blogs []struct {
+				Title string `json:"title"`
+			} `json:"blogs"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		var titles []string
+		for _, blog := range result.Blogs {
+			titles = append(titles, blog.Title)
+		}
+		assert.Equal(t, tt.titles, titles, tt.url)
+	}
+}
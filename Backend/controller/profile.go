@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// default and maximum number of posts per profile page
+const (
+	defaultProfilePostsLimit = 10
+	maxProfilePostsLimit     = 50
+)
+
+// public profile of an author, never carries the email, password or reset code
+type Profile struct {
+	ID             uint      `json:"id"`
+	Username       string    `json:"username"`
+	AvatarURL      string    `json:"avatar_url"`
+	JoinedAt       time.Time `json:"joined_at"`
+	PostsCount     int64     `json:"posts_count"`
+	LikesReceived  int64     `json:"likes_received"`
+	FollowersCount int64     `json:"followers_count"`
+	FollowingCount int64     `json:"following_count"`
+}
+
+// ProfileHandler serves the public profiles of authors
+type ProfileHandler struct {
+	profiles *service.ProfileService
+	users    *service.UserService
+	blogs    *service.BlogService
+	media    *Uploads
+	meta     *MetaLoader
+}
+
+func NewProfileHandler(profiles *service.ProfileService, users *service.UserService, blogs *service.BlogService, media *Uploads, meta *MetaLoader) *ProfileHandler {
+	return &ProfileHandler{profiles: profiles, users: users, blogs: blogs, media: media, meta: meta}
+}
+
+// Returns the public profile of the user named in the path with a page of
+// their posts, newest first. ?cursor is the id of the last post of the
+// previous page.
+func (h *ProfileHandler) Show(c *fiber.Ctx) error {
+	context := fiber.Map{
+		"statusText": "OK",
+		"msg":        "Profile",
+	}
+
+	user, err := h.users.ByUsername(c.Params("username"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("User not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch profile", err)
+	}
+	limit := c.QueryInt("limit", defaultProfilePostsLimit)
+	if limit < 1 || limit > maxProfilePostsLimit {
+		return problem.BadRequest("Invalid limit")
+	}
+	var cursor uint64
+	if raw := c.Query("cursor"); raw != "" {
+		parsed, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return problem.BadRequest("Invalid cursor")
+		}
+		cursor = parsed
+	}
+
+	counts, err := h.profiles.Counts(user.ID)
+	if err != nil {
+		return problem.Failed("Could not fetch profile", err)
+	}
+	profile := Profile{
+		ID:             user.ID,
+		Username:       user.Username,
+		AvatarURL:      avatarURL(h.media, user),
+		JoinedAt:       user.CreatedAt,
+		PostsCount:     counts.Posts,
+		LikesReceived:  counts.LikesReceived,
+		FollowersCount: counts.Followers,
+		FollowingCount: counts.Following,
+	}
+
+	blogs, err := h.blogs.ByAuthors([]uint{user.ID}, uint(cursor), limit)
+	if err != nil {
+		return problem.Failed("Could not fetch profile", err)
+	}
+	enriched, err := h.meta.Load(blogs, optionalUserID(c, h.users))
+	if err != nil {
+		return problem.Failed("Could not fetch profile", err)
+	}
+
+	context["profile"] = profile
+	context["blogs"] = enriched
+	if len(blogs) == limit {
+		context["next_cursor"] = strconv.FormatUint(uint64(blogs[len(blogs)-1].ID), 10)
+	}
+	return c.Status(200).JSON(context)
+}
@@ -0,0 +1,113 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/model"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for public profiles
+type ProfileTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	app    *fiber.App
+	author model.User
+}
+
+// Setup before each test
+func (suite *ProfileTestSuite) SetupTest() {
+	setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.author = model.User{Username: "author", Email: "author@example.com", Password: "hashed_password", ResetCode: "123456"}
+	suite.db.Create(&suite.author)
+	fans := make([]model.User, 2)
+	for i, name := range []string{"fan1", "fan2"} {
+		fans[i] = model.User{Username: name, Email: name + "@example.com", Password: "hashed_password"}
+		suite.db.Create(&fans[i])
+		suite.db.Create(&model.Follow{FollowerID: fans[i].ID, FolloweeID: suite.author.ID})
+	}
+	suite.db.Create(&model.Follow{FollowerID: suite.author.ID, FolloweeID: fans[0].ID})
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		blog := model.Blog{Title: title, Post: "content", UserID: suite.author.ID, UserName: suite.author.Username}
+		suite.db.Create(&blog)
+		for _, fan := range fans {
+			suite.db.Create(&model.Like{UserID: fan.ID, BlogID: blog.ID})
+		}
+	}
+	suite.db.Create(&model.Blog{Title: "Other", Post: "content", UserID: fans[0].ID, UserName: fans[0].Username})
+
+	suite.app = newApp()
+	suite.app.Get("/users/:username", testContainer(suite.db).Profiles.Show)
+}
+
+func (suite *ProfileTestSuite) get(url string) (*http.Response, []byte) {
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+	assert.Nil(suite.T(), err)
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, raw
+}
+
+// Test the profile counts and that private fields are never exposed
+func (suite *ProfileTestSuite) TestProfile() {
+	resp, raw := suite.get("/users/author")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.NotContains(suite.T(), string(raw), "author@example.com")
+	assert.NotContains(suite.T(), string(raw), "hashed_password")
+	assert.NotContains(suite.T(), string(raw), "123456")
+
+	var body struct {
+		Profile controller.Profile        `json:"profile"`
+		Blogs   []controller.BlogWithMeta `json:"blogs"`
+	}
+	json.Unmarshal(raw, &body)
+	assert.Equal(suite.T(), "author", body.Profile.Username)
+	assert.Equal(suite.T(), int64(3), body.Profile.PostsCount)
+	assert.Equal(suite.T(), int64(6), body.Profile.LikesReceived)
+	assert.Equal(suite.T(), int64(2), body.Profile.FollowersCount)
+	assert.Equal(suite.T(), int64(1), body.Profile.FollowingCount)
+	assert.False(suite.T(), body.Profile.JoinedAt.IsZero())
+	assert.Len(suite.T(), body.Blogs, 3)
+	assert.Equal(suite.T(), "Third", body.Blogs[0].Title)
+}
+
+// Test paginating the posts of a profile
+func (suite *ProfileTestSuite) TestProfilePosts() {
+	var body map[string]interface{}
+	_, raw := suite.get("/users/author?limit=2")
+	json.Unmarshal(raw, &body)
+	assert.Len(suite.T(), body["blogs"], 2)
+
+	_, raw = suite.get("/users/author?limit=2&cursor=" + body["next_cursor"].(string))
+	body = nil
+	json.Unmarshal(raw, &body)
+	blogs := body["blogs"].([]interface{})
+	assert.Len(suite.T(), blogs, 1)
+	assert.Equal(suite.T(), "First", blogs[0].(map[string]interface{})["title"])
+	assert.Nil(suite.T(), body["next_cursor"])
+}
+
+// Test unknown users and invalid parameters
+func (suite *ProfileTestSuite) TestProfileInvalid() {
+	resp, _ := suite.get("/users/nobody")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	resp, _ = suite.get("/users/author?limit=0")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	resp, _ = suite.get("/users/author?cursor=abc")
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+// Run the test suite
+func TestProfileSuite(t *testing.T) {
+	suite.Run(t, new(ProfileTestSuite))
+}
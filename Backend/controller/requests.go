@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/service"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The request bodies the handlers accept. Each lists the only fields a
+// client may set, everything else on the stored records (ids, owners,
+// timestamps, counters, versions) is filled in by the server, so extra
+// fields in a body are ignored rather than written. The validate tags are
+// checked with validate.Struct, see package validate for the rules.
+
+// body of a sign up
+type signUpRequest struct {
+	Username string `json:"username" validate:"required,min=username,max=username,username"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=password,max=password,password"`
+}
+
+func (r signUpRequest) user() model.User {
+	return model.User{Username: r.Username, Email: r.Email, Password: r.Password}
+}
+
+// body of a sign in. Accounts made before the rules were checked may not
+// follow them, so only presence is.
+type signInRequest struct {
+	Email    string `json:"email" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// body asking for a password reset code
+type resetCodeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// body checking a password reset code
+type verifyCodeRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Code  string `json:"code" validate:"required"`
+}
+
+// body of a password reset
+type resetPasswordRequest struct {
+	Email       string `json:"email" validate:"required,email"`
+	NewPassword string `json:"new_password" validate:"required,min=password,max=password,password"`
+}
+
+// body confirming the deletion of an account
+type deleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// body of a new blog. Tags are names or {"name": ...} objects.
+type createBlogRequest struct {
+	Title string      `json:"title" validate:"required,max=title"`
+	Post  string      `json:"post" validate:"required,max=post"`
+	Tags  []model.Tag `json:"tags" validate:"dive"`
+}
+
+func (r createBlogRequest) blog() model.Blog {
+	return model.Blog{Title: r.Title, Post: r.Post, Tags: r.Tags}
+}
+
+// body of a PUT on a blog, fields left out keep their value
+type updateBlogRequest struct {
+	Title *string      `json:"title" validate:"omitempty,required,max=title"`
+	Post  *string      `json:"post" validate:"omitempty,required,max=post"`
+	Tags  *[]model.Tag `json:"tags" validate:"dive"`
+}
+
+func (r updateBlogRequest) changes() service.BlogChanges {
+	changes := service.BlogChanges{Title: r.Title, Post: r.Post}
+	if r.Tags != nil {
+		changes.Tags = append([]model.Tag{}, *r.Tags...)
+	}
+	return changes
+}
+
+// body of a new comment, the author and the blog come from the token and
+// the path
+type commentRequest struct {
+	Content string `json:"content" validate:"required,max=comment"`
+}
+
+// body of a new reading list
+type createReadingListRequest struct {
+	Name   *string `json:"name" validate:"required,max=list_name"`
+	Public *bool   `json:"public"`
+}
+
+// body of a change to a reading list, fields left out keep their value
+type updateReadingListRequest struct {
+	Name   *string `json:"name" validate:"omitempty,required,max=list_name"`
+	Public *bool   `json:"public"`
+}
+
+// body adding a blog to a reading list
+type readingListItemRequest struct {
+	BlogID uint `json:"blog_id" validate:"required"`
+}
+
+// body ordering a reading list, every blog in it once
+type reorderReadingListRequest struct {
+	BlogIDs []uint `json:"blog_ids"`
+}
+
+// media type of JSON Merge Patch documents
+const mimeMergePatch = "application/merge-patch+json"
+
+// reads a JSON Merge Patch (RFC 7396) of a blog. Members set a field, null
+// removes it, which only tags allow. A member naming any other field is an
+// error, as the patch could not be applied as asked.
+func blogMergePatch(body []byte) (updateBlogRequest, error) {
+	var changes updateBlogRequest
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil || patch == nil {
+		return changes, errors.New("the patch must be a JSON object")
+	}
+	for field, raw := range patch {
+		null := bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+		switch field {
+		case "title", "post":
+			if null {
+				return changes, fmt.Errorf("%s cannot be removed", field)
+			}
+			var value string
+			if err := json.Unmarshal(raw, &value); err != nil {
+				return changes, fmt.Errorf("%s must be a string", field)
+			}
+			if field == "title" {
+				changes.Title = &value
+			} else {
+				changes.Post = &value
+			}
+		case "tags":
+			tags := []model.Tag{}
+			if !null {
+				if err := json.Unmarshal(raw, &tags); err != nil {
+					return changes, errors.New("tags must be a list of tag names")
+				}
+			}
+			changes.Tags = &tags
+		default:
+			return changes, fmt.Errorf("%s cannot be changed", field)
+		}
+	}
+	return changes, nil
+}
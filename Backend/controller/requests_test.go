@@ -0,0 +1,198 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/service"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// app editing blogs as user, with a blog of theirs tagged go and web
+func blogEditApp(t *testing.T) (*fiber.App, *gorm.DB, model.Blog) {
+	db := openTestDB(t)
+	user := model.User{Username: "author", Email: "test@example.com", Password: "hashed_password"}
+	db.Create(&user)
+	blog := model.Blog{Title: "Hello", Post: "content", UserID: user.ID, UserName: user.Username,
+		Tags: []model.Tag{{Name: "go"}, {Name: "web"}}}
+	db.Create(&blog)
+
+	deps := testContainer(db)
+	app := newApp()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", user.Email)
+		return c.Next()
+	})
+	app.Put("/blogs/:id", deps.Blogs.Update)
+	app.Patch("/blogs/:id", deps.Blogs.Patch)
+	return app, db, blog
+}
+
+func patchBlog(t *testing.T, app *fiber.App, url, contentType, match, patch string) (*http.Response, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(patch))
+	req.Header.Set("Content-Type", contentType)
+	if match != "" {
+		req.Header.Set("If-Match", match)
+	}
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func storedBlog(db *gorm.DB, id uint) model.Blog {
+	var blog model.Blog
+	db.Preload("Tags").First(&blog, id)
+	return blog
+}
+
+func TestBlogMergePatch(t *testing.T) {
+	app, db, blog := blogEditApp(t)
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+
+	// members left out keep their value
+	resp, body := patchBlog(t, app, url, "application/merge-patch+json", "", `{"title": "Hello again"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "OK", body["statusText"])
+	stored := storedBlog(db, blog.ID)
+	assert.Equal(t, "Hello again", stored.Title)
+	assert.Equal(t, "content", stored.Post)
+	assert.Len(t, stored.Tags, 2)
+
+	// null removes the tags
+	resp, _ = patchBlog(t, app, url, "application/merge-patch+json", "", `{"tags": null}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, storedBlog(db, blog.ID).Tags)
+
+	// fields outside the whitelist are refused, not ignored
+	for _, patch := range []string{`{"user_id": 2}`, `{"title": "Mine now", "UserName": "mallory"}`, `{"likes_count": 99}`, `{"version": 1}`} {
+		resp, body = patchBlog(t, app, url, "application/merge-patch+json", "", patch)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, patch)
+		assert.Equal(t, "invalid_input", body["code"])
+	}
+	for _, patch := range []string{`{"title": null}`, `{"post": 5}`, `["title"]`, `{`} {
+		resp, _ = patchBlog(t, app, url, "application/merge-patch+json", "", patch)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, patch)
+	}
+	resp, _ = patchBlog(t, app, url, "text/plain", "", `{"title": "Plain"}`)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	stored = storedBlog(db, blog.ID)
+	assert.Equal(t, "Hello again", stored.Title)
+	assert.Equal(t, blog.UserID, stored.UserID)
+	assert.Equal(t, int64(3), stored.Version)
+
+	// If-Match guards patches as it does updates
+	resp, _ = patchBlog(t, app, url, "application/json", `"v1"`, `{"post": "Lost"}`)
+	assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+	resp, _ = patchBlog(t, app, url, "application/json", `"v3"`, `{"post": "Kept", "tags": ["go"]}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	stored = storedBlog(db, blog.ID)
+	assert.Equal(t, "Kept", stored.Post)
+	assert.Len(t, stored.Tags, 1)
+}
+
+func TestBlogUpdateIgnoresOtherFields(t *testing.T) {
+	app, db, blog := blogEditApp(t)
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+
+	resp, _ := putIfMatch(t, app, url, "", fiber.Map{"post": "changed", "user_id": 99, "UserName": "mallory",
+		"likes_count": 42, "ID": 7})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	stored := storedBlog(db, blog.ID)
+	assert.Equal(t, "Hello", stored.Title)
+	assert.Equal(t, "changed", stored.Post)
+	assert.Equal(t, blog.UserID, stored.UserID)
+	assert.Equal(t, "author", stored.UserName)
+	assert.Zero(t, stored.LikesCount)
+	assert.Len(t, stored.Tags, 2)
+	var count int64
+	db.Model(&model.Blog{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// a malformed body changes nothing
+	req := httptest.NewRequest(http.MethodPut, url, bytes.NewBufferString(`{"title":`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, _ = app.Test(req, -1)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestBlogBodiesFollowLimits(t *testing.T) {
+	app, db, blog := blogEditApp(t)
+	deps := testContainer(db)
+	app.Post("/blogs", deps.Blogs.Create)
+	url := fmt.Sprintf("/blogs/%d", blog.ID)
+
+	send := func(method, url, body string) (*http.Response, map[string]interface{}) {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp, result
+	}
+	fields := func(result map[string]interface{}) []string {
+		var names []string
+		errs, _ := result["errors"].([]interface{})
+		for _, e := range errs {
+			names = append(names, e.(map[string]interface{})["field"].(string))
+		}
+		return names
+	}
+
+	long := strings.Repeat("x", 201)
+	resp, result := send(http.MethodPost, "/blogs", fmt.Sprintf(`{"title": %q, "post": " "}`, long))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "validation_failed", result["code"])
+	assert.Equal(t, "Invalid input", result["detail"])
+	assert.Equal(t, []string{"title", "post"}, fields(result))
+
+	// left out fields are fine on updates, blank ones are not
+	resp, result = send(http.MethodPut, url, `{"title": ""}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []string{"title"}, fields(result))
+	resp, result = send(http.MethodPatch, url, fmt.Sprintf(`{"title": %q}`, long))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []string{"title"}, fields(result))
+	resp, _ = send(http.MethodPatch, url, `{"post": "Still fine"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// tag names are checked one by one, their number once they are merged
+	resp, result = send(http.MethodPost, "/blogs", fmt.Sprintf(`{"title": "T", "post": "P", "tags": ["go", %q, {"name": "<b>"}]}`,
+		strings.Repeat("t", 51)))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []string{"tags[1].name", "tags[2].name"}, fields(result))
+	resp, result = send(http.MethodPut, url, `{"tags": ["c++", "c#", "semi;colon"]}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []string{"tags[2].name"}, fields(result))
+
+	many := make([]string, service.MaxBlogTags+1)
+	for i := range many {
+		many[i] = fmt.Sprint("tag", i)
+	}
+	tags, _ := json.Marshal(many)
+	resp, result = send(http.MethodPost, "/blogs", fmt.Sprintf(`{"title": "T", "post": "P", "tags": %s}`, tags))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "validation_failed", result["code"])
+	assert.Equal(t, []string{"tags"}, fields(result))
+	resp, result = send(http.MethodPatch, url, fmt.Sprintf(`{"tags": %s}`, tags))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, []string{"tags"}, fields(result))
+
+	var count int64
+	db.Model(&model.Blog{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, "Hello", storedBlog(db, blog.ID).Title)
+	assert.Len(t, storedBlog(db, blog.ID).Tags, 2)
+}
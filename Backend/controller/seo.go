@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/sitemap"
+	"Gator_blog/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// how long rendered sitemaps are cached and how long page descriptions are
+const (
+	sitemapCacheTTL   = time.Hour
+	descriptionLength = 160
+)
+
+// frontend pages crawlers should stay out of
+var privatePaths = []string{"/dashboard", "/new-post", "/edit-post/", "/api/"}
+
+// SiteHandler serves the public site to crawlers and feed readers: sitemaps,
+// post metadata and syndication feeds
+type SiteHandler struct {
+	site  *service.SiteService
+	blogs *service.BlogService
+	users *service.UserService
+	media *Uploads
+	cache cache.Cache
+}
+
+func NewSiteHandler(site *service.SiteService, blogs *service.BlogService, users *service.UserService, media *Uploads, responses cache.Cache) *SiteHandler {
+	return &SiteHandler{site: site, blogs: blogs, users: users, media: media, cache: responses}
+}
+
+// Serves the sitemap of the site, or a sitemap index once there are more
+// URLs than fit in one sitemap
+func (h *SiteHandler) Sitemap(c *fiber.Ctx) error {
+	return h.serveSitemap(c, 0)
+}
+
+// Serves one of the sitemaps listed in the sitemap index
+func (h *SiteHandler) SitemapPage(c *fiber.Ctx) error {
+	page, err := c.ParamsInt("page")
+	if err != nil || page < 1 {
+		return problem.Missing("Sitemap not found")
+	}
+	return h.serveSitemap(c, page)
+}
+
+// renders page 0 (the root sitemap.xml) or one numbered page of the sitemap
+func (h *SiteHandler) serveSitemap(c *fiber.Ctx, page int) error {
+	cacheKey := fmt.Sprintf("sitemap:%d", page)
+	var body []byte
+	found, err := h.cache.Get(cacheKey, &body)
+	logCacheError("Cache error: ", err)
+	if !found {
+		body, err = h.renderSitemap(baseURL(c), page)
+		if err == errSitemapNotFound {
+			return problem.Missing("Sitemap not found")
+		} else if err != nil {
+			return problem.Failed("Could not build sitemap", err)
+		}
+		logCacheError("Error setting cache", h.cache.Set(cacheKey, body, sitemapCacheTTL))
+	}
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	return c.Status(200).Send(body)
+}
+
+var errSitemapNotFound = errors.New("sitemap page not found")
+
+// lists every post, then every author page. The root sitemap holds them all
+// when they fit, otherwise it is an index of numbered pages.
+func (h *SiteHandler) renderSitemap(base string, page int) ([]byte, error) {
+	posts, authors, err := h.site.Counts()
+	if err != nil {
+		return nil, err
+	}
+	pages := sitemap.Pages(posts + authors)
+
+	if page == 0 && pages > 1 {
+		index := make([]sitemap.URL, pages)
+		for i := range index {
+			index[i] = sitemap.URL{Loc: fmt.Sprintf("%s/sitemaps/%d.xml", base, i+1)}
+		}
+		return sitemap.RenderIndex(index)
+	}
+	if page > pages || (page == 0 && pages > 1) {
+		return nil, errSitemapNotFound
+	}
+	if page == 0 {
+		page = 1
+	}
+
+	offset := (page - 1) * sitemap.MaxURLs
+	urls, err := h.sitemapURLs(base, offset, sitemap.MaxURLs, int(posts))
+	if err != nil {
+		return nil, err
+	}
+	return sitemap.RenderURLSet(urls)
+}
+
+// returns limit URLs starting at offset of the posts followed by the author
+// pages, posts being the number of posts
+func (h *SiteHandler) sitemapURLs(base string, offset, limit, posts int) ([]sitemap.URL, error) {
+	urls := make([]sitemap.URL, 0, limit)
+	if offset < posts {
+		blogs, err := h.site.Posts(offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, blog := range blogs {
+			urls = append(urls, sitemap.URL{Loc: fmt.Sprintf("%s/post/%d", base, blog.ID), LastMod: blog.UpdatedAt})
+		}
+	}
+	if len(urls) == limit {
+		return urls, nil
+	}
+
+	authorOffset := offset - posts
+	if authorOffset < 0 {
+		authorOffset = 0
+	}
+	authors, err := h.site.Authors(authorOffset, limit-len(urls))
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range authors {
+		urls = append(urls, sitemap.URL{Loc: base + "/users/" + author.Username, LastMod: author.UpdatedAt})
+	}
+	return urls, nil
+}
+
+// Serves robots.txt, keeping crawlers out of private pages and pointing them
+// at the sitemap
+func Robots(c *fiber.Ctx) error {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range privatePaths {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("Allow: /api/feeds/\n\n")
+	b.WriteString("Sitemap: " + baseURL(c) + "/sitemap.xml\n")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.Status(200).SendString(b.String())
+}
+
+// metadata describing a post to search engines and link previews
+type BlogSEO struct {
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	Excerpt       string            `json:"excerpt"`
+	CanonicalURL  string            `json:"canonical_url"`
+	Author        string            `json:"author"`
+	PublishedTime time.Time         `json:"published_time"`
+	ModifiedTime  time.Time         `json:"modified_time"`
+	Tags          []string          `json:"tags"`
+	OpenGraph     map[string]string `json:"open_graph"`
+	Twitter       map[string]string `json:"twitter"`
+}
+
+// Returns the OpenGraph, Twitter card and search metadata of a post
+func (h *SiteHandler) BlogMeta(c *fiber.Ctx) error {
+	blog, err := h.blogs.Get(pathID(c, "id"))
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.Missing("Blog not found")
+	}
+	if err != nil {
+		return problem.Failed("Could not fetch blog", err)
+	}
+	covers, err := coverImages(h.media, []model.Blog{blog})
+	if err != nil {
+		return problem.Failed("Could not fetch blog", err)
+	}
+	return c.Status(200).JSON(blogSEO(blog, covers[blog.ID], baseURL(c)))
+}
+
+func blogSEO(blog model.Blog, cover *CoverImage, base string) BlogSEO {
+	url := fmt.Sprintf("%s/post/%d", base, blog.ID)
+	description := utils.Excerpt(blog.Post, descriptionLength)
+	tags := make([]string, len(blog.Tags))
+	for i, tag := range blog.Tags {
+		tags[i] = tag.Name
+	}
+
+	og := map[string]string{
+		"og:type":                "article",
+		"og:site_name":           siteName,
+		"og:title":               blog.Title,
+		"og:description":         description,
+		"og:url":                 url,
+		"article:author":         blog.UserName,
+		"article:published_time": blog.CreatedAt.UTC().Format(time.RFC3339),
+		"article:modified_time":  blog.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(tags) > 0 {
+		og["article:tag"] = strings.Join(tags, ",")
+	}
+	twitter := map[string]string{
+		"twitter:card":        "summary",
+		"twitter:title":       blog.Title,
+		"twitter:description": description,
+	}
+	if cover != nil {
+		image := cover.URL
+		if strings.HasPrefix(image, "/") {
+			image = base + image
+		}
+		og["og:image"] = image
+		og["og:image:width"] = strconv.Itoa(cover.Width)
+		og["og:image:height"] = strconv.Itoa(cover.Height)
+		twitter["twitter:card"] = "summary_large_image"
+		twitter["twitter:image"] = image
+	}
+	return BlogSEO{
+		Title:         blog.Title + " | " + siteName,
+		Description:   description,
+		Excerpt:       utils.Excerpt(blog.Post, feedSummaryLen),
+		CanonicalURL:  url,
+		Author:        blog.UserName,
+		PublishedTime: blog.CreatedAt,
+		ModifiedTime:  blog.UpdatedAt,
+		Tags:          tags,
+		OpenGraph:     og,
+		Twitter:       twitter,
+	}
+}
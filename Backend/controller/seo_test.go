@@ -0,0 +1,155 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/model"
+	"Gator_blog/sitemap"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for the sitemap, robots.txt and post metadata
+type SEOTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	app    *fiber.App
+	author model.User
+}
+
+type sitemapDoc struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// Setup before each test
+func (suite *SEOTestSuite) SetupTest() {
+	setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.author = model.User{Username: "author", Email: "author@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.author)
+
+	deps := testContainer(suite.db)
+	suite.app = newApp()
+	suite.app.Get("/robots.txt", controller.Robots)
+	suite.app.Get("/sitemap.xml", deps.Site.Sitemap)
+	suite.app.Get("/sitemaps/:page.xml", deps.Site.SitemapPage)
+	suite.app.Get("/api/blogs/:id/meta", deps.Site.BlogMeta)
+}
+
+func (suite *SEOTestSuite) get(url string) (*http.Response, []byte) {
+	resp, err := suite.app.Test(httptest.NewRequest(http.MethodGet, url, nil), -1)
+	assert.Nil(suite.T(), err)
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, raw
+}
+
+func (suite *SEOTestSuite) sitemap(url string) sitemapDoc {
+	resp, raw := suite.get(url)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	var doc sitemapDoc
+	assert.NoError(suite.T(), xml.Unmarshal(raw, &doc))
+	return doc
+}
+
+// Test a sitemap small enough for a single file
+func (suite *SEOTestSuite) TestSitemap() {
+	suite.db.Create(&model.Blog{Title: "One", Post: "content", UserID: suite.author.ID, UserName: "author"})
+	suite.db.Create(&model.Blog{Title: "Two", Post: "content", UserID: suite.author.ID, UserName: "author"})
+	suite.db.Create(&model.User{Username: "lurker", Email: "lurker@example.com", Password: "hashed_password"})
+
+	doc := suite.sitemap("/sitemap.xml")
+	assert.Equal(suite.T(), "urlset", doc.XMLName.Local)
+	var locs []string
+	for _, u := range doc.URLs {
+		locs = append(locs, u.Loc)
+		assert.NotEmpty(suite.T(), u.LastMod)
+	}
+	assert.Equal(suite.T(), []string{"http://example.com/post/1", "http://example.com/post/2", "http://example.com/users/author"}, locs)
+
+	resp, _ := suite.get("/sitemaps/2.xml")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test splitting the sitemap into an index past the URL limit
+func (suite *SEOTestSuite) TestSitemapIndex() {
+	blogs := make([]model.Blog, sitemap.MaxURLs)
+	for i := range blogs {
+		blogs[i] = model.Blog{Title: "Post", Post: "content", UserID: suite.author.ID, UserName: "author"}
+	}
+	assert.NoError(suite.T(), suite.db.CreateInBatches(blogs, 500).Error)
+
+	index := suite.sitemap("/sitemap.xml")
+	assert.Equal(suite.T(), "sitemapindex", index.XMLName.Local)
+	assert.Len(suite.T(), index.Sitemaps, 2)
+	assert.Equal(suite.T(), "http://example.com/sitemaps/2.xml", index.Sitemaps[1].Loc)
+
+	assert.Len(suite.T(), suite.sitemap("/sitemaps/1.xml").URLs, sitemap.MaxURLs)
+	last := suite.sitemap("/sitemaps/2.xml")
+	assert.Len(suite.T(), last.URLs, 1)
+	assert.Equal(suite.T(), "http://example.com/users/author", last.URLs[0].Loc)
+
+	resp, _ := suite.get("/sitemaps/3.xml")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test robots.txt
+func (suite *SEOTestSuite) TestRobots() {
+	resp, raw := suite.get("/robots.txt")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.True(suite.T(), strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+	assert.Contains(suite.T(), string(raw), "Disallow: /dashboard\n")
+	assert.Contains(suite.T(), string(raw), "Sitemap: http://example.com/sitemap.xml\n")
+}
+
+// Test the metadata of a post
+func (suite *SEOTestSuite) TestBlogMeta() {
+	blog := model.Blog{
+		Title:    "Gators",
+		Post:     strings.Repeat("Alligators  live in\nFlorida. ", 20),
+		UserID:   suite.author.ID,
+		UserName: "author",
+		Tags:     []model.Tag{{Name: "nature"}, {Name: "florida"}},
+	}
+	suite.db.Create(&blog)
+
+	resp, raw := suite.get(fmt.Sprintf("/api/blogs/%d/meta", blog.ID))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	var meta controller.BlogSEO
+	json.Unmarshal(raw, &meta)
+
+	assert.Equal(suite.T(), "Gators | Gator Blog", meta.Title)
+	assert.LessOrEqual(suite.T(), len([]rune(meta.Description)), 160)
+	assert.True(suite.T(), strings.HasPrefix(meta.Description, "Alligators live in Florida."))
+	assert.True(suite.T(), strings.HasSuffix(meta.Description, "…"))
+	assert.Equal(suite.T(), fmt.Sprintf("http://example.com/post/%d", blog.ID), meta.CanonicalURL)
+	assert.ElementsMatch(suite.T(), []string{"nature", "florida"}, meta.Tags)
+	assert.Equal(suite.T(), "article", meta.OpenGraph["og:type"])
+	assert.Equal(suite.T(), meta.CanonicalURL, meta.OpenGraph["og:url"])
+	assert.Equal(suite.T(), "summary", meta.Twitter["twitter:card"])
+
+	resp, _ = suite.get("/api/blogs/999/meta")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Run the test suite
+func TestSEOSuite(t *testing.T) {
+	suite.Run(t, new(SEOTestSuite))
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"Gator_blog/cache"
+	"Gator_blog/model"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/syndication"
+	"Gator_blog/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// name of the site in feeds and page metadata
+const siteName = "Gator Blog"
+
+// public URL of the site, set from server.base_url. Links are built from the
+// request's host while it is empty.
+var SiteURL string
+
+func baseURL(c *fiber.Ctx) string {
+	if SiteURL != "" {
+		return strings.TrimSuffix(SiteURL, "/")
+	}
+	return c.BaseURL()
+}
+
+// number of posts in a feed and how long a rendered feed is cached
+const (
+	feedItemLimit  = 50
+	feedCacheTTL   = 10 * time.Minute
+	feedSummaryLen = 280
+)
+
+// rendered feed as stored in Redis
+type cachedFeed struct {
+	Body []byte `json:"body"`
+	ETag string `json:"etag"`
+}
+
+// Serves the newest posts of the whole blog as RSS, Atom or JSON Feed
+func (h *SiteHandler) SiteFeed(c *fiber.Ctx) error {
+	return h.serveFeed(c, "all", func(base string) (syndication.Feed, repository.FeedFilter, error) {
+		feed := syndication.Feed{
+			Title:       siteName,
+			Description: "The newest posts on " + siteName,
+			Link:        base + "/home",
+		}
+		return feed, repository.FeedFilter{}, nil
+	})
+}
+
+// Serves the newest posts of the author named in the path
+func (h *SiteHandler) AuthorFeed(c *fiber.Ctx) error {
+	username := c.Params("username")
+	return h.serveFeed(c, "author:"+username, func(base string) (syndication.Feed, repository.FeedFilter, error) {
+		user, err := h.users.ByUsername(username)
+		if err != nil {
+			return syndication.Feed{}, repository.FeedFilter{}, err
+		}
+		feed := syndication.Feed{
+			Title:       user.Username + " on " + siteName,
+			Description: "The newest posts by " + user.Username,
+			Link:        base + "/users/" + user.Username,
+		}
+		return feed, repository.FeedFilter{UserID: user.ID}, nil
+	})
+}
+
+// Serves the newest posts carrying the tag in the path
+func (h *SiteHandler) TagFeed(c *fiber.Ctx) error {
+	name := strings.ToLower(c.Params("tag"))
+	return h.serveFeed(c, "tag:"+name, func(base string) (syndication.Feed, repository.FeedFilter, error) {
+		tag, err := h.site.Tag(name)
+		if err != nil {
+			return syndication.Feed{}, repository.FeedFilter{}, err
+		}
+		feed := syndication.Feed{
+			Title:       "#" + tag.Name + " on " + siteName,
+			Description: "The newest posts tagged " + tag.Name,
+			Link:        base + "/tags/" + tag.Name,
+		}
+		return feed, repository.FeedFilter{TagID: tag.ID}, nil
+	})
+}
+
+// renders the feed of a scope in the format in the path, serving it from
+// the cache when possible and answering conditional requests with 304.
+// describe returns the feed metadata and the filter selecting its posts.
+func (h *SiteHandler) serveFeed(c *fiber.Ctx, scope string, describe func(base string) (syndication.Feed, repository.FeedFilter, error)) error {
+	format, err := syndication.ParseFormat(c.Params("format"))
+	if err != nil {
+		return problem.Missing("Unknown feed format")
+	}
+
+	cacheKey := fmt.Sprintf("feed:%s:%s", scope, format)
+	var feed cachedFeed
+	found, err := h.cache.Get(cacheKey, &feed)
+	logCacheError("Cache error: ", err)
+	if !found {
+		clock, clockErr := h.cache.Clock()
+		logCacheError("Cache error: ", clockErr)
+		base := baseURL(c)
+		meta, filter, err := describe(base)
+		if errors.Is(err, repository.ErrNotFound) {
+			return problem.Missing("Feed not found")
+		} else if err != nil {
+			return problem.Failed("Could not build feed", err)
+		}
+		meta.FeedURL = base + c.Path()
+
+		blogs, err := h.site.Newest(filter, feedItemLimit)
+		if err != nil {
+			return problem.Failed("Could not build feed", err)
+		}
+		feed, err = renderFeed(meta, blogs, base, format)
+		if err != nil {
+			return problem.Failed("Could not build feed", err)
+		}
+		if clockErr == nil {
+			logCacheError("Error setting cache", h.cache.SetAt(clock, cacheKey, feed, feedCacheTTL, feedsTag))
+		}
+	}
+
+	c.Set(fiber.HeaderETag, feed.ETag)
+	if notModified(c, feed.ETag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	return c.Status(200).Send(feed.Body)
+}
+
+// turns blogs into a rendered feed with its validators
+func renderFeed(meta syndication.Feed, blogs []model.Blog, base string, format syndication.Format) (cachedFeed, error) {
+	for _, blog := range blogs {
+		tags := make([]string, len(blog.Tags))
+		for i, tag := range blog.Tags {
+			tags[i] = tag.Name
+		}
+		link := fmt.Sprintf("%s/post/%d", base, blog.ID)
+		meta.Items = append(meta.Items, syndication.Item{
+			ID:        link,
+			Title:     blog.Title,
+			Link:      link,
+			Author:    blog.UserName,
+			Summary:   utils.Excerpt(blog.Post, feedSummaryLen),
+			Content:   blog.Post,
+			Tags:      tags,
+			Published: blog.CreatedAt,
+			Updated:   blog.UpdatedAt,
+		})
+		if blog.UpdatedAt.After(meta.Updated) {
+			meta.Updated = blog.UpdatedAt
+		}
+	}
+
+	body, err := syndication.Render(meta, format)
+	if err != nil {
+		return cachedFeed{}, err
+	}
+	sum := sha256.Sum256(body)
+	return cachedFeed{Body: body, ETag: `"` + hex.EncodeToString(sum[:16]) + `"`}, nil
+}
+
+// reports whether the client's cached copy is still current, comparing
+// If-None-Match weakly as for any GET. If-Modified-Since is not honored, a
+// deleted post changes a feed without a later date to show for it.
+func notModified(c *fiber.Ctx, etag string) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		etag = strings.TrimPrefix(etag, "W/")
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// drops every cached feed after a post changed
+func invalidateFeeds(responses cache.Cache) {
+	logCacheError("Error invalidating feeds", responses.DeleteTag(feedsTag))
+}
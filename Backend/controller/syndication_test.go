@@ -0,0 +1,146 @@
+package controller_test
+
+import (
+	"Gator_blog/model"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Define the test suite for syndication feeds and blog tags
+type SyndicationTestSuite struct {
+	suite.Suite
+	db     *gorm.DB
+	mr     *miniredis.Miniredis
+	app    *fiber.App
+	author model.User
+}
+
+// Setup before each test
+func (suite *SyndicationTestSuite) SetupTest() {
+	suite.mr = setupTestRedis(suite.T())
+	suite.db = openTestDB(suite.T())
+
+	suite.author = model.User{Username: "author", Email: "author@example.com", Password: "hashed_password"}
+	suite.db.Create(&suite.author)
+
+	deps := testContainer(suite.db)
+	suite.app = newApp()
+	suite.app.Use(func(c *fiber.Ctx) error {
+		c.Locals("userEmail", suite.author.Email)
+		return c.Next()
+	})
+	suite.app.Post("/blogs", deps.Blogs.Create)
+	suite.app.Put("/blogs/:id", deps.Blogs.Update)
+	suite.app.Get("/feeds/authors/:username/:format", deps.Site.AuthorFeed)
+	suite.app.Get("/feeds/tags/:tag/:format", deps.Site.TagFeed)
+	suite.app.Get("/feeds/:format", deps.Site.SiteFeed)
+}
+
+func (suite *SyndicationTestSuite) send(method, url string, payload interface{}) map[string]interface{} {
+	raw, _ := json.Marshal(payload)
+	req := httptest.NewRequest(method, url, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := suite.app.Test(req)
+	assert.Nil(suite.T(), err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return body["blog"].(map[string]interface{})
+}
+
+func (suite *SyndicationTestSuite) get(url string, headers map[string]string) (*http.Response, string) {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := suite.app.Test(req)
+	assert.Nil(suite.T(), err)
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, string(raw)
+}
+
+// Test the site feed in every format
+func (suite *SyndicationTestSuite) TestSiteFeedFormats() {
+	suite.send(http.MethodPost, "/blogs", fiber.Map{"title": "Hello feeds", "post": "content"})
+
+	for format, contentType := range map[string]string{
+		"rss":  "application/rss+xml; charset=utf-8",
+		"atom": "application/atom+xml; charset=utf-8",
+		"json": "application/feed+json; charset=utf-8",
+	} {
+		resp, body := suite.get("/feeds/"+format, nil)
+		assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, format)
+		assert.Equal(suite.T(), contentType, resp.Header.Get("Content-Type"))
+		assert.NotEmpty(suite.T(), resp.Header.Get("ETag"))
+		assert.Empty(suite.T(), resp.Header.Get("Last-Modified"))
+		assert.Contains(suite.T(), body, "Hello feeds")
+	}
+
+	resp, _ := suite.get("/feeds/xml", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Test conditional requests and cache invalidation
+func (suite *SyndicationTestSuite) TestConditionalRequests() {
+	suite.send(http.MethodPost, "/blogs", fiber.Map{"title": "First", "post": "content"})
+
+	resp, _ := suite.get("/feeds/rss", nil)
+	etag := resp.Header.Get("ETag")
+	assert.True(suite.T(), suite.mr.Exists("feed:all:rss"))
+
+	resp, body := suite.get("/feeds/rss", map[string]string{"If-None-Match": etag})
+	assert.Equal(suite.T(), http.StatusNotModified, resp.StatusCode)
+	assert.Empty(suite.T(), body)
+	resp, _ = suite.get("/feeds/rss", map[string]string{"If-Modified-Since": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	resp, _ = suite.get("/feeds/rss", map[string]string{"If-None-Match": `"stale"`})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// a new post makes the cached feeds stale and changes the ETag
+	suite.send(http.MethodPost, "/blogs", fiber.Map{"title": "Second", "post": "content"})
+	resp, body = suite.get("/feeds/rss", map[string]string{"If-None-Match": etag})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	assert.Contains(suite.T(), body, "Second")
+}
+
+// Test author and tag feeds and tags on blogs
+func (suite *SyndicationTestSuite) TestAuthorAndTagFeeds() {
+	first := suite.send(http.MethodPost, "/blogs", fiber.Map{"title": "Go post", "post": "content", "tags": []string{"Go", " web ", "go"}})
+	assert.Len(suite.T(), first["tags"], 2)
+	suite.send(http.MethodPost, "/blogs", fiber.Map{"title": "Web post", "post": "content", "tags": []string{"web"}})
+
+	_, body := suite.get("/feeds/tags/go/json", nil)
+	assert.Contains(suite.T(), body, "Go post")
+	assert.NotContains(suite.T(), body, "Web post")
+	_, body = suite.get("/feeds/tags/web/atom", nil)
+	assert.Contains(suite.T(), body, "Go post")
+	assert.Contains(suite.T(), body, "Web post")
+	_, body = suite.get("/feeds/authors/author/rss", nil)
+	assert.Contains(suite.T(), body, "Web post")
+
+	// replacing the tags of a post moves it between tag feeds
+	suite.send(http.MethodPut, fmt.Sprintf("/blogs/%v", first["ID"]), fiber.Map{"tags": []string{"web"}})
+	_, body = suite.get("/feeds/tags/go/json", nil)
+	assert.NotContains(suite.T(), body, "Go post")
+
+	resp, _ := suite.get("/feeds/tags/missing/rss", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	resp, _ = suite.get("/feeds/authors/nobody/rss", nil)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+// Run the test suite
+func TestSyndicationSuite(t *testing.T) {
+	suite.Run(t, new(SyndicationTestSuite))
+}
@@ -1,77 +1,71 @@
 package controller
 
 import (
-	"Gator_blog/database"
-	"Gator_blog/model"
-	"Gator_blog/utils"
-	"fmt"
-	"log"
+	"Gator_blog/middleware"
+	"Gator_blog/problem"
+	"Gator_blog/repository"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/exp/rand"
 )
 
-const SecretKey = "your_secret_key"
-
 // Function to generate JWT token
 func generateJWT(email string) (string, error) {
 	claims := jwt.MapClaims{
 		"email": email,
-		"exp":   time.Now().Add(time.Hour * 24).Unix(), // Token expires in 24 hours
+		"exp":   time.Now().Add(middleware.TokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(SecretKey))
+	return token.SignedString([]byte(middleware.SecretKey))
+}
+
+// UserHandler serves sign in, sign up and password resets
+type UserHandler struct {
+	users *service.UserService
+	media *Uploads
+}
+
+func NewUserHandler(users *service.UserService, media *Uploads) *UserHandler {
+	return &UserHandler{users: users, media: media}
 }
 
 // SignIn function
-func SignIn(c *fiber.Ctx) error {
+func (h *UserHandler) SignIn(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "SignIn user",
 	}
 
-	user_record := new(model.User)
-
 	// Parse the user input from the request
-	if err := c.BodyParser(&user_record); err != nil {
-		log.Println("Error parsing user record")
-		context["statusText"] = "error"
-		context["msg"] = "Invalid input"
-		return c.JSON(context)
+	var body signInRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
 	}
-
-	// Check if the user exists in the database
-	var existingUser model.User
-	result := database.DBConn.Where("email = ?", user_record.Email).First(&existingUser)
-
-	if result.Error != nil {
-		log.Println("User not found")
-		context["statusText"] = "error"
-		context["msg"] = "User not found"
-		return c.JSON(context)
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
 	}
-	// Compare the entered password with the stored hashed password
-	err := bcrypt.CompareHashAndPassword([]byte(existingUser.Password), []byte(user_record.Password))
-	if err != nil {
-		log.Println("Incorrect password")
-		context["statusText"] = "error"
-		context["msg"] = "Incorrect password"
-		return c.JSON(context)
+
+	// Check the user exists and the password matches the stored hash
+	existingUser, err := h.users.SignIn(body.Email, body.Password)
+	switch {
+	case errors.Is(err, service.ErrIncorrectPassword):
+		return problem.New(401, problem.IncorrectPassword, "Incorrect password")
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.New(404, problem.UserNotFound, "User not found")
+	case err != nil:
+		return problem.Failed("Could not sign in", err)
 	}
 
 	// Generate JWT token
 	token, err := generateJWT(existingUser.Email)
 	if err != nil {
-		log.Println("Error generating token", err)
-		context["statusText"] = "error"
-		context["msg"] = "Error generating token"
-		return c.JSON(context)
+		return problem.Failed("Error generating token", err)
 	}
-	println()
 	context["msg"] = "Login successful"
 	context["token"] = token
 
@@ -79,76 +73,47 @@ func SignIn(c *fiber.Ctx) error {
 	context["username"] = existingUser.Username
 	context["email"] = existingUser.Email
 	// new change Sritha - end
+	context["avatar_url"] = avatarURL(h.media, existingUser)
 
 	c.Status(200)
 	return c.JSON(context)
 }
 
 // SignUp function
-func SignUp(c *fiber.Ctx) error {
+func (h *UserHandler) SignUp(c *fiber.Ctx) error {
 	context := fiber.Map{
 		"statusText": "OK",
 		"msg":        "SignUp user",
 	}
 
-	user_record := new(model.User)
-
-	// Parse the user input from the request
-	if err := c.BodyParser(&user_record); err != nil {
-		log.Println("Error in parsing user record")
-		context["statusText"] = "error"
-		context["msg"] = "Invalid input"
-		return c.JSON(context)
-	}
-
-	// Check if the email and username already exists in the database
-	var existingUser model.User
-	result := database.DBConn.Where("email = ?", user_record.Email).First(&existingUser)
-
-	if result.Error == nil {
-		log.Println("Email already exists")
-		context["statusText"] = "error"
-		context["msg"] = "Email already registered"
-		return c.JSON(context)
-	}
-	result = database.DBConn.Where("username = ?", user_record.Username).First(&existingUser)
-
-	if result.Error == nil {
-		log.Println("Username already exists")
-		context["statusText"] = "error"
-		context["msg"] = "Username is already taken"
-		return c.JSON(context)
+	// Parse the user input from the request, only the fields of
+	// signUpRequest are taken
+	var body signUpRequest
+	if err := c.BodyParser(&body); err != nil {
+		return problem.BadRequest("Invalid input")
 	}
-
-	// Hash the password before saving it to the database
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user_record.Password), bcrypt.DefaultCost)
-	if err != nil {
-		log.Println("Error while hashing password", err)
-		context["statusText"] = "error"
-		context["msg"] = "Error hashing password"
-		return c.JSON(context)
+	if err := validate.Struct(body); err != nil {
+		return problem.Invalid(err)
 	}
+	user_record := body.user()
 
-	// Update the user_record with the hashed password
-	user_record.Password = string(hashedPassword)
-	user_record.ResetCodeExpiry = time.Now()
-	// If email does not exist, proceed to create the new user
-	result = database.DBConn.Create(user_record)
-
-	if result.Error != nil {
-		log.Println("Error saving user to db")
-		context["statusText"] = "error"
-		context["msg"] = "Error saving user to db"
-		return c.JSON(context)
+	// Check the email and username are free, hash the password and save
+	err := h.users.SignUp(&user_record)
+	switch {
+	case errors.Is(err, service.ErrEmailTaken):
+		return problem.New(409, problem.EmailTaken, "Email already registered")
+	case errors.Is(err, service.ErrUsernameTaken):
+		return problem.New(409, problem.UsernameTaken, "Username is already taken")
+	case errors.As(err, new(validate.Errors)):
+		return problem.Invalid(err)
+	case err != nil:
+		return problem.Failed("Error saving user to db", err)
 	}
 
 	// Generate JWT token
 	token, err := generateJWT(user_record.Email)
 	if err != nil {
-		log.Println("Error generating token", err)
-		context["statusText"] = "error"
-		context["msg"] = "Error generating token"
-		return c.JSON(context)
+		return problem.Failed("Error generating token", err)
 	}
 
 	context["msg"] = "User registered successfully"
@@ -157,84 +122,67 @@ func SignUp(c *fiber.Ctx) error {
 	return c.JSON(context)
 }
 
-func RequestResetCode(c *fiber.Ctx) error {
-	type Request struct {
-		Email string `json:"email"`
-	}
-	var req Request
+func (h *UserHandler) RequestResetCode(c *fiber.Ctx) error {
+	var req resetCodeRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"msg": "Invalid request"})
+		return problem.BadRequest("Invalid request")
 	}
-
-	var user model.User
-	result := database.DBConn.Where("email = ?", req.Email).First(&user)
-	if result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{"msg": "User not found"})
+	if err := validate.Struct(req); err != nil {
+		return problem.Invalid(err)
 	}
 
-	code := fmt.Sprintf("%06d", rand.Intn(1000000))
-	user.ResetCode = code
-	user.ResetCodeExpiry = time.Now().Add(10 * time.Minute)
-
-	database.DBConn.Save(&user)
-
-	err := utils.SendResetCodeEmail(user.Email, code)
+	err := h.users.RequestResetCode(req.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.New(404, problem.UserNotFound, "User not found")
+	}
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"msg": "Failed to send email"})
+		return problem.New(503, problem.Unavailable, "Failed to send email").Wrap(err)
 	}
 
 	return c.JSON(fiber.Map{"msg": "Verification code sent"})
 }
 
-func VerifyResetCode(c *fiber.Ctx) error {
-	type Request struct {
-		Email string `json:"email"`
-		Code  string `json:"code"`
-	}
-	var req Request
+func (h *UserHandler) VerifyResetCode(c *fiber.Ctx) error {
+	var req verifyCodeRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"msg": "Invalid request"})
+		return problem.BadRequest("Invalid request")
 	}
-
-	var user model.User
-	result := database.DBConn.Where("email = ?", req.Email).First(&user)
-	if result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{"msg": "User not found"})
-	}
-	if user.ResetCode != req.Code || time.Now().After(user.ResetCodeExpiry) {
-		return c.Status(401).JSON(fiber.Map{"msg": "Invalid or expired code"})
+	if err := validate.Struct(req); err != nil {
+		return problem.Invalid(err)
 	}
 
-	// Clear the reset code after successful verification
-	user.ResetCode = ""
-	database.DBConn.Save(&user)
+	err := h.users.VerifyResetCode(req.Email, req.Code)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return problem.New(404, problem.UserNotFound, "User not found")
+	case errors.Is(err, service.ErrInvalidCode):
+		return problem.New(401, problem.InvalidCode, "Invalid or expired code")
+	case err != nil:
+		return problem.Failed("Failed to verify code", err)
+	}
 
 	return c.JSON(fiber.Map{"msg": "Code verified. Proceed to reset password."})
 }
 
-func ResetPasswordWithEmail(c *fiber.Ctx) error {
-	type Request struct {
-		Email       string `json:"email"`
-		NewPassword string `json:"new_password"`
-	}
-	var req Request
+func (h *UserHandler) ResetPassword(c *fiber.Ctx) error {
+	var req resetPasswordRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"msg": "Invalid request"})
+		return problem.BadRequest("Invalid request")
 	}
-
-	var user model.User
-	result := database.DBConn.Where("email = ?", req.Email).First(&user)
-	if result.Error != nil {
-		return c.Status(404).JSON(fiber.Map{"msg": "User not found"})
+	if err := validate.Struct(req); err != nil {
+		return problem.Invalid(err)
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	err := h.users.ResetPassword(req.Email, req.NewPassword)
+	if errors.Is(err, repository.ErrNotFound) {
+		return problem.New(404, problem.UserNotFound, "User not found")
+	}
+	if errors.As(err, new(validate.Errors)) {
+		return problem.Invalid(err)
+	}
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"msg": "Error hashing password"})
+		return problem.Failed("Failed to update password", err)
 	}
 
-	user.Password = string(hashedPassword)
-	database.DBConn.Save(&user)
-
 	return c.JSON(fiber.Map{"msg": "Password updated successfully"})
 }
@@ -1,14 +1,16 @@
 package controller_test
 
 import (
-	"Gator_blog/controller"
-	"Gator_blog/database"
+	"Gator_blog/database/dbtest"
+	"Gator_blog/middleware"
 	"Gator_blog/model"
+	"Gator_blog/validate"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,7 +18,6 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
@@ -29,26 +30,23 @@ type AuthTestSuite struct {
 
 func (suite *AuthTestSuite) SetupTest() {
 
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
-	if err != nil {
-		suite.T().Fatal("Failed to connect to test database:", err)
-	}
-
-	db.AutoMigrate(&model.User{}, &model.Blog{})
-
-	database.DBConn = db
-	suite.db = db
+	suite.db = dbtest.Open(suite.T(), &model.User{}, &model.Blog{})
+	suite.app = authApp(suite.db)
+}
 
-	app := fiber.New()
+// auth routes served from db
+func authApp(db *gorm.DB) *fiber.App {
+	users := testContainer(db).Users
+	app := newApp()
 
-	app.Post("/auth/signup", controller.SignUp)
-	app.Post("/auth/signin", controller.SignIn)
+	app.Post("/auth/signup", users.SignUp)
+	app.Post("/auth/signin", users.SignIn)
 
-	app.Post("/auth/request-reset", controller.RequestResetCode)
-	app.Post("/auth/verify-code", controller.VerifyResetCode)
-	app.Post("/auth/reset-password", controller.ResetPasswordWithEmail)
+	app.Post("/auth/request-reset", users.RequestResetCode)
+	app.Post("/auth/verify-code", users.VerifyResetCode)
+	app.Post("/auth/reset-password", users.ResetPassword)
 
-	suite.app = app
+	return app
 }
 
 func (suite *AuthTestSuite) TearDownTest() {
@@ -103,13 +101,14 @@ func (suite *AuthTestSuite) TestSignUpDuplicateEmail() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+	assert.Equal(suite.T(), "application/problem+json", resp.Header.Get("Content-Type"))
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Email already registered", result["msg"])
+	assert.Equal(suite.T(), "email_taken", result["code"])
+	assert.Equal(suite.T(), "Email already registered", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestSignUpDuplicateUsername() {
@@ -129,13 +128,13 @@ func (suite *AuthTestSuite) TestSignUpDuplicateUsername() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Username is already taken", result["msg"])
+	assert.Equal(suite.T(), "username_taken", result["code"])
+	assert.Equal(suite.T(), "Username is already taken", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestSignUpInvalidJSON() {
@@ -147,13 +146,98 @@ func (suite *AuthTestSuite) TestSignUpInvalidJSON() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Invalid input", result["msg"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
+	assert.Equal(suite.T(), "Invalid input", result["detail"])
+}
+
+func (suite *AuthTestSuite) TestSignUpBreaksRules() {
+
+	userData := map[string]interface{}{
+		"username": "test user",
+		"email":    "not-an-email",
+		"password": "",
+	}
+
+	jsonData, _ := json.Marshal(userData)
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewReader(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := suite.app.Test(req)
+
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	var result struct {
+		Code   string `json:"code"`
+		Detail string `json:"detail"`
+		Errors []struct {
+			Field, Rule, Message string
+		} `json:"errors"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	assert.Equal(suite.T(), "validation_failed", result.Code)
+	assert.Equal(suite.T(), "Invalid input", result.Detail)
+	assert.Len(suite.T(), result.Errors, 3)
+	assert.Equal(suite.T(), "username", result.Errors[0].Field)
+	assert.Equal(suite.T(), "email", result.Errors[1].Rule)
+	assert.Equal(suite.T(), "password is required", result.Errors[2].Message)
+
+	// nothing was saved
+	var count int64
+	suite.db.Model(&model.User{}).Count(&count)
+	assert.Zero(suite.T(), count)
+}
+
+func (suite *AuthTestSuite) TestSignUpPasswordOverBcryptLimit() {
+
+	// 41 characters but 81 bytes, more than bcrypt takes
+	signUp := func() (int, string, string) {
+		jsonData, _ := json.Marshal(map[string]interface{}{
+			"username": "testuser",
+			"email":    "test@example.com",
+			"password": strings.Repeat("é", 40) + "1",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewReader(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := suite.app.Test(req, -1)
+		assert.Nil(suite.T(), err)
+
+		var result struct {
+			Errors []struct{ Field, Rule string } `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		if !assert.Len(suite.T(), result.Errors, 1) {
+			return resp.StatusCode, "", ""
+		}
+		return resp.StatusCode, result.Errors[0].Field, result.Errors[0].Rule
+	}
+
+	status, field, rule := signUp()
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+	assert.Equal(suite.T(), "password", field)
+	assert.Equal(suite.T(), "max", rule)
+
+	// a limit configured past what bcrypt takes is still a bad request
+	defaults := validate.Limits()
+	suite.T().Cleanup(func() { validate.Init(defaults) })
+	limits := defaults
+	limits.PasswordMax = 100
+	validate.Init(limits)
+
+	status, field, rule = signUp()
+	assert.Equal(suite.T(), http.StatusBadRequest, status)
+	assert.Equal(suite.T(), "password", field)
+	assert.Equal(suite.T(), "max", rule)
+
+	var count int64
+	suite.db.Model(&model.User{}).Count(&count)
+	assert.Zero(suite.T(), count)
 }
 
 func (suite *AuthTestSuite) TestSignInSuccess() {
@@ -195,13 +279,13 @@ func (suite *AuthTestSuite) TestSignInUserNotFound() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "user_not_found", result["code"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestSignInIncorrectPassword() {
@@ -220,13 +304,13 @@ func (suite *AuthTestSuite) TestSignInIncorrectPassword() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Incorrect password", result["msg"])
+	assert.Equal(suite.T(), "incorrect_password", result["code"])
+	assert.Equal(suite.T(), "Incorrect password", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestSignInInvalidJSON() {
@@ -238,13 +322,13 @@ func (suite *AuthTestSuite) TestSignInInvalidJSON() {
 	resp, err := suite.app.Test(req)
 
 	assert.Nil(suite.T(), err)
-	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode) // Note: Your API returns 200 even for errors
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
 
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Invalid input", result["msg"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
+	assert.Equal(suite.T(), "Invalid input", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestSignInMissingFields() {
@@ -262,7 +346,7 @@ func (suite *AuthTestSuite) TestSignInMissingFields() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
+	assert.Equal(suite.T(), "validation_failed", result["code"])
 
 	credentials = map[string]interface{}{
 		"email": "test@example.com",
@@ -275,7 +359,7 @@ func (suite *AuthTestSuite) TestSignInMissingFields() {
 	resp, _ = suite.app.Test(req)
 
 	json.NewDecoder(resp.Body).Decode(&result)
-	assert.Equal(suite.T(), "error", result["statusText"])
+	assert.Equal(suite.T(), "validation_failed", result["code"])
 }
 
 func (suite *AuthTestSuite) TestJWTTokenValidation() {
@@ -303,7 +387,7 @@ func (suite *AuthTestSuite) TestJWTTokenValidation() {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(controller.SecretKey), nil
+		return []byte(middleware.SecretKey), nil
 	})
 
 	assert.Nil(suite.T(), err)
@@ -335,16 +419,14 @@ func (suite *AuthTestSuite) TestMalformedContentType() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
+	assert.Equal(suite.T(), "invalid_input", result["code"])
 }
 
 func (suite *AuthTestSuite) TestDatabaseErrorHandling() {
 
 	tx := suite.db.Begin()
-	originalDB := database.DBConn
-	database.DBConn = tx
-
 	tx.Rollback()
+	suite.app = authApp(tx)
 
 	user := map[string]interface{}{
 		"username": "testuser",
@@ -361,9 +443,7 @@ func (suite *AuthTestSuite) TestDatabaseErrorHandling() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "error", result["statusText"])
-
-	database.DBConn = originalDB
+	assert.Equal(suite.T(), "internal", result["code"])
 }
 
 func (suite *AuthTestSuite) TestRequestResetCodeUserNotFound() {
@@ -383,7 +463,7 @@ func (suite *AuthTestSuite) TestRequestResetCodeUserNotFound() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestRequestResetCodeInvalidJSON() {
@@ -399,7 +479,7 @@ func (suite *AuthTestSuite) TestRequestResetCodeInvalidJSON() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid request", result["msg"])
+	assert.Equal(suite.T(), "Invalid request", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestVerifyResetCodeSuccess() {
@@ -471,7 +551,7 @@ func (suite *AuthTestSuite) TestVerifyResetCodeInvalid() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid or expired code", result["msg"])
+	assert.Equal(suite.T(), "Invalid or expired code", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestVerifyResetCodeExpired() {
@@ -505,7 +585,7 @@ func (suite *AuthTestSuite) TestVerifyResetCodeExpired() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid or expired code", result["msg"])
+	assert.Equal(suite.T(), "Invalid or expired code", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestVerifyResetCodeUserNotFound() {
@@ -526,7 +606,7 @@ func (suite *AuthTestSuite) TestVerifyResetCodeUserNotFound() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestVerifyResetCodeInvalidJSON() {
@@ -542,7 +622,7 @@ func (suite *AuthTestSuite) TestVerifyResetCodeInvalidJSON() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid request", result["msg"])
+	assert.Equal(suite.T(), "Invalid request", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestResetPasswordSuccess() {
@@ -606,7 +686,7 @@ func (suite *AuthTestSuite) TestResetPasswordUserNotFound() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "User not found", result["msg"])
+	assert.Equal(suite.T(), "User not found", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestResetPasswordInvalidJSON() {
@@ -622,7 +702,7 @@ func (suite *AuthTestSuite) TestResetPasswordInvalidJSON() {
 	var result map[string]interface{}
 	json.NewDecoder(resp.Body).Decode(&result)
 
-	assert.Equal(suite.T(), "Invalid request", result["msg"])
+	assert.Equal(suite.T(), "Invalid request", result["detail"])
 }
 
 func (suite *AuthTestSuite) TestResetPasswordFlow() {
@@ -694,8 +774,8 @@ func (suite *AuthTestSuite) TestResetPasswordFlow() {
 	resp, _ = suite.app.Test(req)
 
 	json.NewDecoder(resp.Body).Decode(&result)
-	assert.Equal(suite.T(), "error", result["statusText"])
-	assert.Equal(suite.T(), "Incorrect password", result["msg"])
+	assert.Equal(suite.T(), "incorrect_password", result["code"])
+	assert.Equal(suite.T(), "Incorrect password", result["detail"])
 }
 
 func TestAuthSuite(t *testing.T) {
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/openapi"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// The v2 contracts, served under /api/v2 by the v1 handlers with V2 in front
+// of them:
+//
+//   - a successful JSON answer is {"data": ...}, pages add a next_cursor,
+//     always a string, that is left out on the last page
+//   - the statusText and msg of v1 are dropped. What remains goes in data,
+//     unwrapped when it is a single object or list, e.g. the blog of
+//     {"statusText", "msg", "blog"}
+//   - answers that only carried a message are 204 No Content
+//   - blogs have the snake case members of the other records: id, title,
+//     post and user_name, which v1 sends as ID, Title, Post and UserName
+//
+// Failures are problem documents in both versions.
+
+// members of model.Blog its json tags leave in Go case in v1
+var v2Members = map[string]string{"ID": "id", "Title": "title", "Post": "post", "UserName": "user_name"}
+
+// the members of the v1 envelopes that hold a model.Blog or a list of them
+var v2BlogMembers = []string{"blog", "blogs"}
+
+// key of the Locals naming the prefix of the API version being served
+const apiPrefixKey = "apiPrefix"
+
+// the prefix of the API version serving c, for links in its answers
+func apiPrefix(c *fiber.Ctx) string {
+	if prefix, ok := c.Locals(apiPrefixKey).(string); ok {
+		return prefix
+	}
+	return "/api"
+}
+
+// V2 reshapes the answers of the handlers after it into the v2 contracts
+func V2(c *fiber.Ctx) error {
+	c.Locals(apiPrefixKey, "/api/v2")
+	if err := c.Next(); err != nil {
+		return err
+	}
+	res := c.Response()
+	media, _, _ := mime.ParseMediaType(string(res.Header.ContentType()))
+	if res.StatusCode() < 200 || res.StatusCode() >= 300 || media != fiber.MIMEApplicationJSON {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(res.Body()))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil
+	}
+	reshaped, ok := v2Body(body)
+	if !ok {
+		res.ResetBody()
+		res.Header.Del(fiber.HeaderContentType)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	return c.JSON(reshaped)
+}
+
+// body in its v2 shape, false when nothing is left of it
+func v2Body(body interface{}) (fiber.Map, bool) {
+	envelope, ok := body.(map[string]interface{})
+	if !ok || envelope["msg"] == nil {
+		return fiber.Map{"data": body}, true
+	}
+	delete(envelope, "statusText")
+	delete(envelope, "msg")
+	cursor, paged := envelope["next_cursor"]
+	delete(envelope, "next_cursor")
+	if len(envelope) == 0 {
+		return nil, false
+	}
+	v2Blogs(envelope)
+
+	out := fiber.Map{"data": envelope}
+	if len(envelope) == 1 {
+		for _, value := range envelope {
+			// objects and lists, which are null when empty
+			switch value.(type) {
+			case map[string]interface{}, []interface{}, nil:
+				out["data"] = value
+			}
+		}
+	}
+	if paged && cursor != nil {
+		out["next_cursor"] = fmt.Sprint(cursor)
+	}
+	return out, true
+}
+
+// renames the members of the blogs in the blog members of envelope. Nothing
+// below them is touched, whatever its members are called.
+func v2Blogs(envelope map[string]interface{}) {
+	for _, name := range v2BlogMembers {
+		switch value := envelope[name].(type) {
+		case map[string]interface{}:
+			envelope[name] = v2Blog(value)
+		case []interface{}:
+			for i, item := range value {
+				if blog, ok := item.(map[string]interface{}); ok {
+					value[i] = v2Blog(blog)
+				}
+			}
+		}
+	}
+}
+
+// blog with the members v1 leaves in Go case renamed
+func v2Blog(blog map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(blog))
+	for name, member := range blog {
+		if renamed, ok := v2Members[name]; ok {
+			name = renamed
+		}
+		out[name] = member
+	}
+	return out
+}
+
+// route as v2 serves it, described with the reshaped bodies V2 answers
+func v2Route(route apiRoute, doc *openapi.Document) apiRoute {
+	op := route.op
+	op.Responses = map[int]interface{}{}
+	for status, body := range route.op.Responses {
+		reshaped, ok := v2Schema(body, doc)
+		if !ok {
+			status = fiber.StatusNoContent
+		}
+		op.Responses[status] = reshaped
+	}
+	return apiRoute{route.method, "/api/v2" + strings.TrimPrefix(route.path, "/api"), op}
+}
+
+// the v2 description of a response body, as v2Body reshapes it
+func v2Schema(body interface{}, doc *openapi.Document) (interface{}, bool) {
+	switch body.(type) {
+	case nil, openapi.Raw:
+		return body, true
+	}
+	envelope, ok := body.(openapi.Object)
+	if !ok || envelope["msg"] == nil {
+		return openapi.Object{"data": body}, true
+	}
+	rest := openapi.Object{}
+	for name, value := range envelope {
+		if name != "statusText" && name != "msg" && name != "next_cursor" {
+			rest[name] = value
+		}
+	}
+	if len(rest) == 0 {
+		return nil, false
+	}
+
+	v2BlogSchemas(rest, doc)
+
+	out := openapi.Object{"data": rest}
+	if len(rest) == 1 {
+		for _, value := range rest {
+			if v2Unwrapped(value) {
+				out["data"] = value
+			}
+		}
+	}
+	if _, paged := envelope["next_cursor"]; paged {
+		out["next_cursor"] = openapi.Optional{Value: ""}
+	}
+	return out, true
+}
+
+// whether a lone member is a single object or list, which data holds
+// directly
+func v2Unwrapped(value interface{}) bool {
+	switch value.(type) {
+	case openapi.Object, *openapi.Schema:
+		return true
+	}
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		return t != reflect.TypeOf(openapi.Optional{})
+	}
+	return false
+}
+
+// describes the blogs in the blog members of envelope by the BlogV2
+// component, as v2Blogs renames them
+func v2BlogSchemas(envelope openapi.Object, doc *openapi.Document) {
+	for _, name := range v2BlogMembers {
+		switch envelope[name].(type) {
+		case model.Blog:
+			envelope[name] = v2BlogComponent(doc)
+		case []model.Blog:
+			envelope[name] = &openapi.Schema{Type: openapi.Types{"array", "null"}, Items: v2BlogComponent(doc)}
+		}
+	}
+}
+
+// refers to model.Blog with its members renamed, describing it the first
+// time
+func v2BlogComponent(doc *openapi.Document) *openapi.Schema {
+	schemas := doc.Components.Schemas
+	if _, described := schemas["BlogV2"]; !described {
+		v1 := doc.Resolve(doc.Generator().Schema(model.Blog{}))
+		v2 := *v1
+		v2.Properties = map[string]*openapi.Schema{}
+		for name, prop := range v1.Properties {
+			if renamed, ok := v2Members[name]; ok {
+				name = renamed
+			}
+			v2.Properties[name] = prop
+		}
+		v2.Required = nil
+		for _, name := range v1.Required {
+			if renamed, ok := v2Members[name]; ok {
+				name = renamed
+			}
+			v2.Required = append(v2.Required, name)
+		}
+		sort.Strings(v2.Required)
+		schemas["BlogV2"] = &v2
+	}
+	return openapi.Ref("BlogV2")
+}
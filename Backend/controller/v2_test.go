@@ -0,0 +1,131 @@
+package controller_test
+
+import (
+	"Gator_blog/controller"
+	"Gator_blog/middleware"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV2Shapes(t *testing.T) {
+	app := strictApp(t)
+
+	status, body := call(t, app, "POST", "/api/v2/signup", "", map[string]string{
+		"username": "writer", "email": "writer@example.com", "password": "password123"})
+	require.Equal(t, http.StatusCreated, status, body)
+	assert.NotContains(t, body, "msg")
+	token := body["data"].(map[string]interface{})["token"].(string)
+
+	status, body = call(t, app, "POST", "/api/v2/blogs", token, map[string]interface{}{"title": "Hello", "post": "World"})
+	require.Equal(t, http.StatusCreated, status, body)
+	blog := body["data"].(map[string]interface{})
+	assert.Equal(t, "Hello", blog["title"])
+	assert.Equal(t, "writer", blog["user_name"])
+	assert.NotContains(t, blog, "Title")
+	url := fmt.Sprintf("/api/v2/blogs/%v", blog["id"])
+
+	status, body = call(t, app, "GET", "/api/v2/blogs", token, nil)
+	assert.Equal(t, http.StatusOK, status)
+	blogs := body["data"].([]interface{})
+	require.Len(t, blogs, 1)
+	assert.Equal(t, "World", blogs[0].(map[string]interface{})["post"])
+
+	status, body = call(t, app, "POST", url+"/comments", token, map[string]string{"content": "Nice"})
+	require.Equal(t, http.StatusCreated, status, body)
+	comment := body["data"].(map[string]interface{})["id"]
+
+	status, body = call(t, app, "GET", url+"/comments", token, nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, body["data"], 1)
+
+	// answers that only carried a message have no body
+	status, _ = call(t, app, "DELETE", fmt.Sprintf("%s/comments/%v", url, comment), token, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, body = call(t, app, "POST", url+"/likes", token, nil)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.NotNil(t, body["data"])
+	status, _ = call(t, app, "POST", url+"/likes", token, nil)
+	assert.Equal(t, http.StatusNoContent, status)
+
+	status, body = call(t, app, "GET", "/api/v2/users/writer?limit=1", "", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body["data"], "profile")
+	assert.IsType(t, "", body["next_cursor"])
+
+	// failures are the same problem documents as in v1
+	status, body = call(t, app, "GET", "/api/v2/blogs/999", token, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "not_found", body["code"])
+}
+
+func TestV2RenamesOnlyBlogs(t *testing.T) {
+	app := newApp()
+	app.Get("/reading-list", controller.V2, func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"statusText": "OK", "msg": "List",
+			"blog":  fiber.Map{"ID": 1, "Title": "Hello", "tags": []fiber.Map{{"ID": 2, "Title": "tag"}}},
+			"blogs": []fiber.Map{{"ID": 3, "UserName": "writer"}},
+			"list":  fiber.Map{"Title": "written by a reader"}})
+	})
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/reading-list", nil), -1)
+	require.NoError(t, err)
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	blog := body.Data["blog"].(map[string]interface{})
+	assert.Equal(t, "Hello", blog["title"])
+	assert.Contains(t, blog["tags"].([]interface{})[0], "Title")
+	assert.Equal(t, "writer", body.Data["blogs"].([]interface{})[0].(map[string]interface{})["user_name"])
+	assert.Contains(t, body.Data["list"], "Title")
+}
+
+func TestV2ShareURL(t *testing.T) {
+	app := strictApp(t)
+	_, body := call(t, app, "POST", "/api/v2/signup", "", map[string]string{
+		"username": "reader", "email": "reader@example.com", "password": "password123"})
+	token := body["data"].(map[string]interface{})["token"].(string)
+
+	status, body := call(t, app, "POST", "/api/v2/me/reading-lists", token, map[string]interface{}{"name": "Picks", "public": true})
+	require.Equal(t, http.StatusCreated, status, body)
+	shareURL, _ := body["data"].(map[string]interface{})["share_url"].(string)
+	assert.True(t, strings.HasPrefix(shareURL, "/api/v2/reading-lists/shared/"), shareURL)
+
+	status, _ = call(t, app, "GET", shareURL, "", nil)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestV1Deprecation(t *testing.T) {
+	app := strictApp(t)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api/all-blogs-with-meta", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "@"+strconv.FormatInt(middleware.V1Deprecated.Unix(), 10), resp.Header.Get("Deprecation"))
+	assert.Equal(t, middleware.V1Sunset.Format(http.TimeFormat), resp.Header.Get("Sunset"))
+	assert.Equal(t, `</api/v2/all-blogs-with-meta>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	// failures of v1 are announced too
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/api/blogs", nil), -1)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Sunset"))
+
+	for _, path := range []string{"/api/v2/all-blogs-with-meta", "/api/openapi.json"} {
+		resp, err = app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Deprecation"), path)
+		assert.Empty(t, resp.Header.Get("Sunset"), path)
+	}
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"Gator_blog/config"
+	"Gator_blog/model"
+	"Gator_blog/redis"
+	"Gator_blog/service"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// a reader is counted once per blog within this window
+const viewDedupWindow = 30 * time.Minute
+
+// user agents of crawlers, link previewers and scripts, whose reads are not views
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|curl|wget|python|go-http-client|okhttp|headless|phantomjs|preview|facebookexternalhit|monitor|lighthouse`)
+
+func isBot(userAgent string) bool {
+	return userAgent == "" || botUserAgent.MatchString(userAgent)
+}
+
+// key viewer ids are derived with, see InitViews
+var viewerSecret []byte
+
+// function to set the key viewer ids are derived with, a random one for
+// this run when none is configured
+func InitViews(cfg config.AnalyticsConfig) {
+	viewerSecret = []byte(cfg.ViewerSecret)
+	if len(viewerSecret) > 0 {
+		return
+	}
+	viewerSecret = make([]byte, 32)
+	if _, err := rand.Read(viewerSecret); err != nil {
+		panic("Failed to generate a viewer secret: " + err.Error())
+	}
+	log.Println("No analytics.viewer_secret configured, using a random one for this run")
+}
+
+// identifies the reader by user id when signed in and by IP address otherwise.
+// The id is keyed with viewerSecret, so stored ids cannot be matched to
+// addresses by hashing guesses. It stays the same across days so unique
+// readers can be counted over any range, and is only kept as long as the
+// views, see analytics.view_retention.
+func viewerID(c *fiber.Ctx, userID uint) string {
+	raw := "ip:" + c.IP()
+	if userID != 0 {
+		raw = fmt.Sprintf("user:%d", userID)
+	}
+	mac := hmac.New(sha256.New, viewerSecret)
+	mac.Write([]byte(raw))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// records a view of blog through analytics unless it comes from a bot or the
+// same reader already viewed it that day within viewDedupWindow
+func trackBlogView(analytics *service.AnalyticsService, store *redis.Store, c *fiber.Ctx, userID uint, blog model.Blog) {
+	if isBot(c.Get(fiber.HeaderUserAgent)) {
+		return
+	}
+	viewer := viewerID(c, userID)
+
+	// a view is counted in the daily stats of each day it happens on
+	now := time.Now()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first, err := store.SetOnce(fmt.Sprintf("view:%d:%s:%s", blog.ID, day.Format(model.DayLayout), viewer), viewDedupWindow)
+	if err != nil {
+		// without Redis, deduplicate against the recorded views
+		since := now.Add(-viewDedupWindow)
+		if since.Before(day) {
+			since = day
+		}
+		viewed, err := analytics.Viewed(blog.ID, viewer, since)
+		if err != nil {
+			log.Println("Error checking views", err)
+		}
+		first = !viewed
+	}
+	if !first {
+		return
+	}
+
+	view := model.BlogView{BlogID: blog.ID, UserID: userID, Viewer: viewer}
+	if err := analytics.RecordView(&view); err != nil {
+		log.Println("Error recording view", err)
+		return
+	}
+	recordBlogView(store, blog)
+}
@@ -0,0 +1,30 @@
+package database
+
+import "gorm.io/gorm"
+
+// CountByBlog counts the rows of table in db per blog for the given blog ids
+// in a single grouped query. Blogs without rows are missing from the result.
+func CountByBlog(db *gorm.DB, table interface{}, blogIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(blogIDs))
+	if len(blogIDs) == 0 {
+		return counts, nil
+	}
+
+	type row struct {
+		BlogID uint
+		Count  int64
+	}
+	var rows []row
+	err := db.Model(table).
+		Select("blog_id, COUNT(*) as count").
+		Where("blog_id IN ?", blogIDs).
+		Group("blog_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		counts[r.BlogID] = r.Count
+	}
+	return counts, nil
+}
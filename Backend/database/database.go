@@ -1,32 +1,56 @@
 package database
 
 import (
-	"Gator_blog/model"
+	"Gator_blog/config"
+	"Gator_blog/migrations"
+	"fmt"
 	"log"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 var DBConn *gorm.DB
 
-func ConnectDB() {
-
-	dsn := "root:@tcp(localhost:3306)/gator_blog_db?charset=utf8mb4&parseTime=True&loc=Local"
+// Dialector returns the GORM dialector of a driver, mysql, postgres or sqlite
+func Dialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres":
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		return sqlite.Open(dsn), nil
+	}
+	return nil, fmt.Errorf("unknown database driver %q", driver)
+}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+// Open connects to the database without touching the schema
+func Open(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	dialector, err := Dialector(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	return gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Error),
 	})
+}
 
+// ConnectDB connects to the database and applies pending migrations. The
+// migration lock makes this safe when several instances start together.
+func ConnectDB(cfg config.DatabaseConfig) {
+	db, err := Open(cfg)
 	if err != nil {
 		panic("Database connection failed")
 	}
-	log.Println("DB Connection successful")
-	db.AutoMigrate(new(model.User))
-	db.AutoMigrate(new(model.Blog))
-	db.AutoMigrate(new(model.Comment))
-	db.AutoMigrate(new(model.Like))
+	log.Println("DB Connection successful:", cfg.Driver)
+
+	if _, err := migrations.Up(db); err != nil {
+		panic("Database migration failed: " + err.Error())
+	}
 
 	DBConn = db
 }
@@ -0,0 +1,71 @@
+// Package dbtest gives tests a fresh database and points database.DBConn at
+// it. It uses a private in-memory SQLite database unless
+// TEST_DATABASE_DRIVER and TEST_DATABASE_DSN name a MySQL or PostgreSQL
+// server, whose tables are dropped first; packages sharing such a server
+// must run one at a time with go test -p 1.
+package dbtest
+
+import (
+	"Gator_blog/database"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Driver is the dialect tests run against, sqlite by default
+func Driver() string {
+	if driver := os.Getenv("TEST_DATABASE_DRIVER"); driver != "" {
+		return driver
+	}
+	return "sqlite"
+}
+
+// numbers the SQLite databases opened, so each Open gets its own
+var opened int64
+
+// Open returns an empty database with the tables of models
+func Open(t testing.TB, models ...interface{}) *gorm.DB {
+	driver := Driver()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if driver == "sqlite" {
+		name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+		dsn = fmt.Sprintf("file:%s_%d?mode=memory&cache=shared", name, atomic.AddInt64(&opened, 1))
+	} else if dsn == "" {
+		t.Fatalf("TEST_DATABASE_DSN is required for %s", driver)
+	}
+
+	dialector, err := database.Dialector(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal("Failed to connect to test database:", err)
+	}
+	t.Cleanup(func() {
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	})
+
+	if driver != "sqlite" {
+		tables, err := db.Migrator().GetTables()
+		if err != nil {
+			t.Fatal("Failed to list test tables:", err)
+		}
+		for _, table := range tables {
+			if err := db.Migrator().DropTable(table); err != nil {
+				t.Fatal("Failed to reset test database:", err)
+			}
+		}
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal("Failed to migrate test database:", err)
+	}
+	database.DBConn = db
+	return db
+}
@@ -0,0 +1,20 @@
+package database
+
+import "strings"
+
+// Contains returns a condition matching rows where any of columns contains
+// term, ignoring case, and its arguments. LIKE is case sensitive on
+// PostgreSQL and not on MySQL, so both sides are lowered, and the wildcards
+// in term are escaped to match literally.
+func Contains(term string, columns ...string) (string, []interface{}) {
+	escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(strings.ToLower(term))
+	pattern := "%" + escaped + "%"
+
+	conditions := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		conditions[i] = "LOWER(" + column + ") LIKE ? ESCAPE '!'"
+		args[i] = pattern
+	}
+	return strings.Join(conditions, " OR "), args
+}
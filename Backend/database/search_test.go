@@ -0,0 +1,36 @@
+package database_test
+
+import (
+	"Gator_blog/database"
+	"Gator_blog/database/dbtest"
+	"Gator_blog/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func search(t *testing.T, term string) []string {
+	condition, args := database.Contains(term, "title", "post")
+	var titles []string
+	err := database.DBConn.Model(&model.Blog{}).Where("user_id = ?", 1).Where(condition, args...).Order("id").Pluck("title", &titles).Error
+	assert.Nil(t, err)
+	return titles
+}
+
+func TestContains(t *testing.T) {
+	db := dbtest.Open(t, &model.Blog{})
+	for _, blog := range []model.Blog{
+		{Title: "Gators Win", Post: "football", UserID: 1},
+		{Title: "Exam tips", Post: "Study for the GATOR exam", UserID: 1},
+		{Title: "100% done", Post: "finished", UserID: 1},
+		{Title: "snake_case", Post: "naming", UserID: 1},
+		{Title: "gator", Post: "other user", UserID: 2},
+	} {
+		db.Create(&blog)
+	}
+
+	assert.Equal(t, []string{"Gators Win", "Exam tips"}, search(t, "gAtOr"))
+	assert.Equal(t, []string{"100% done"}, search(t, "0%"))
+	assert.Equal(t, []string{"snake_case"}, search(t, "e_c"))
+	assert.Empty(t, search(t, "%_"))
+}
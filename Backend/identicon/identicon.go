@@ -0,0 +1,41 @@
+// Package identicon draws the symmetric block avatars shown for users who
+// have not uploaded one.
+package identicon
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// number of cells per row and column, the right half mirrors the left
+const grid = 5
+
+var background = color.RGBA{R: 240, G: 240, B: 240, A: 255}
+
+// Generate draws the identicon of seed as a size x size image. The same seed
+// always gives the same picture.
+func Generate(seed string, size int) image.Image {
+	sum := sha256.Sum256([]byte(seed))
+	fill := color.RGBA{R: sum[0]/2 + 64, G: sum[1]/2 + 64, B: sum[2]/2 + 64, A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	// a margin of half a cell on every side
+	cell := size / (grid + 1)
+	offset := (size - cell*grid) / 2
+	for row := 0; row < grid; row++ {
+		for col := 0; col < (grid+1)/2; col++ {
+			if sum[3+row*grid+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, grid - 1 - col} {
+				r := image.Rect(offset+c*cell, offset+row*cell, offset+(c+1)*cell, offset+(row+1)*cell)
+				draw.Draw(img, r, &image.Uniform{C: fill}, image.Point{}, draw.Src)
+			}
+		}
+	}
+	return img
+}
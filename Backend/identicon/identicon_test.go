@@ -0,0 +1,23 @@
+package identicon_test
+
+import (
+	"Gator_blog/identicon"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	a := identicon.Generate("alice", 120).(*image.RGBA)
+	assert.Equal(t, image.Pt(120, 120), a.Bounds().Size())
+	assert.Equal(t, a.Pix, identicon.Generate("alice", 120).(*image.RGBA).Pix)
+	assert.NotEqual(t, a.Pix, identicon.Generate("bob", 120).(*image.RGBA).Pix)
+
+	// the picture is mirrored around its vertical axis
+	for y := 0; y < 120; y++ {
+		for x := 0; x < 60; x++ {
+			assert.Equal(t, a.At(x, y), a.At(119-x, y))
+		}
+	}
+}
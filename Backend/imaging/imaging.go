@@ -0,0 +1,124 @@
+// Package imaging validates uploaded images and produces clean, resized
+// copies of them in pure Go. Re-encoding drops all metadata, EXIF included.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	_ "image/gif" // register decoders
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the decoded size of an image, protecting against small
+// files that expand into huge bitmaps
+const MaxPixels = 40_000_000
+
+// JPEG quality of re-encoded images
+const jpegQuality = 85
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image dimensions too large")
+)
+
+// content types accepted for upload
+var allowed = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Sniff returns the content type of data judged from its bytes, never from
+// what the client claimed, and rejects anything but supported images
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !allowed[contentType] {
+		return "", ErrUnsupportedType
+	}
+	return contentType, nil
+}
+
+// Decode sniffs and decodes an uploaded image, rotating JPEGs upright
+// according to their EXIF orientation
+func Decode(data []byte) (image.Image, string, error) {
+	contentType, err := Sniff(data)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedType
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedType
+	}
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, contentType, nil
+}
+
+// Encode writes img as JPEG when it came from a JPEG and as PNG otherwise,
+// returning the bytes and their content type
+func Encode(img image.Image, sourceType string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if sourceType == "image/jpeg" {
+		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+		return buf.Bytes(), "image/jpeg", err
+	}
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), "image/png", err
+}
+
+// Fit scales img down to fit within maxW x maxH keeping its aspect ratio.
+// Images that already fit are returned as they are.
+func Fit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxW && h <= maxH {
+		return img
+	}
+	if w*maxH > h*maxW {
+		h = max(1, h*maxW/w)
+		w = maxW
+	} else {
+		w = max(1, w*maxH/h)
+		h = maxH
+	}
+	return scale(img, b, w, h)
+}
+
+// Fill crops img around its centre to the aspect ratio of w x h and scales
+// the crop to exactly w x h
+func Fill(img image.Image, w, h int) image.Image {
+	b := img.Bounds()
+	crop := b
+	if b.Dx()*h > b.Dy()*w {
+		cw := b.Dy() * w / h
+		crop.Min.X = b.Min.X + (b.Dx()-cw)/2
+		crop.Max.X = crop.Min.X + cw
+	} else {
+		ch := b.Dx() * h / w
+		crop.Min.Y = b.Min.Y + (b.Dy()-ch)/2
+		crop.Max.Y = crop.Min.Y + ch
+	}
+	return scale(img, crop, w, h)
+}
+
+func scale(img image.Image, src image.Rectangle, w, h int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Src, nil)
+	return dst
+}
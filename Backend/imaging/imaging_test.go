@@ -0,0 +1,94 @@
+package imaging_test
+
+import (
+	"Gator_blog/imaging"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func solid(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	return img
+}
+
+// encodes a JPEG carrying an EXIF segment with the given orientation and a
+// camera make that must not survive re-encoding
+func jpegWithExif(t *testing.T, w, h int, orientation uint16) []byte {
+	var plain bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&plain, solid(w, h), nil))
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 2)
+	tiff = append(tiff, 0x01, 0x12, 0x00, 0x03, 0, 0, 0, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0)
+	tiff = append(tiff, 0x01, 0x0f, 0x00, 0x02, 0, 0, 0, 4, 'G', 'P', 'S', 0)
+	tiff = append(tiff, 0, 0, 0, 0)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, plain.Bytes()[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(2, 2))
+	contentType, err := imaging.Sniff(buf.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	_, err = imaging.Sniff([]byte("<html><body>not an image</body></html>"))
+	assert.Equal(t, imaging.ErrUnsupportedType, err)
+}
+
+func TestDecodeRotatesAndStripsExif(t *testing.T) {
+	data := jpegWithExif(t, 40, 20, 6)
+	assert.Contains(t, string(data), "Exif")
+
+	img, contentType, err := imaging.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, image.Pt(20, 40), img.Bounds().Size())
+
+	out, outType, err := imaging.Encode(img, contentType)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/jpeg", outType)
+	assert.NotContains(t, string(out), "Exif")
+	assert.NotContains(t, string(out), "GPS")
+}
+
+func TestDecodeRejectsHugeDimensions(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, solid(1, 1))
+	data := buf.Bytes()
+	// rewrite the IHDR chunk to claim 10000 x 10000 pixels
+	binary.BigEndian.PutUint32(data[16:], 10000)
+	binary.BigEndian.PutUint32(data[20:], 10000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	_, _, err := imaging.Decode(data)
+	assert.Equal(t, imaging.ErrTooManyPixels, err)
+}
+
+func TestFitAndFill(t *testing.T) {
+	img := solid(400, 200)
+	assert.Equal(t, image.Pt(100, 50), imaging.Fit(img, 100, 100).Bounds().Size())
+	assert.Equal(t, image.Pt(400, 200), imaging.Fit(img, 1000, 1000).Bounds().Size())
+	assert.Equal(t, image.Pt(100, 100), imaging.Fill(img, 100, 100).Bounds().Size())
+	assert.Equal(t, image.Pt(160, 90), imaging.Fill(solid(90, 160), 160, 90).Bounds().Size())
+}
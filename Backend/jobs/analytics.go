@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"Gator_blog/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StartAnalyticsRollup refreshes the daily stats of today and yesterday every
+// interval, yesterday is included so late events before midnight are counted
+func (r *Runner) StartAnalyticsRollup(interval time.Duration) {
+	go every(interval, "analytics rollup", func() error {
+		now := time.Now()
+		if err := r.RollupDailyStats(now.AddDate(0, 0, -1)); err != nil {
+			return err
+		}
+		return r.RollupDailyStats(now)
+	})
+}
+
+// StartViewPruning deletes the views older than retention every interval.
+// Their daily stats are kept.
+func (r *Runner) StartViewPruning(interval, retention time.Duration) {
+	go every(interval, "view pruning", func() error {
+		return r.PruneViews(time.Now().Add(-retention))
+	})
+}
+
+// PruneViews deletes the views recorded before t
+func (r *Runner) PruneViews(t time.Time) error {
+	return r.db.Where("created_at < ?", t).Delete(&model.BlogView{}).Error
+}
+
+// RollupDailyStats aggregates the views, unique readers, likes and comments
+// of every blog with activity on the day of t into model.BlogDailyStat. The
+// stats of blogs without activity that day, since their views, likes or
+// comments were deleted, are reset to zero.
+func (r *Runner) RollupDailyStats(t time.Time) error {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	end := start.AddDate(0, 0, 1)
+	day := start.Format(model.DayLayout)
+
+	stats := map[uint]*model.BlogDailyStat{}
+	stat := func(blogID uint) *model.BlogDailyStat {
+		if stats[blogID] == nil {
+			stats[blogID] = &model.BlogDailyStat{BlogID: blogID, Day: day}
+		}
+		return stats[blogID]
+	}
+
+	type viewRow struct {
+		BlogID  uint
+		Views   int64
+		Readers int64
+	}
+	var views []viewRow
+	err := r.db.Model(&model.BlogView{}).
+		Select("blog_id, COUNT(*) as views, COUNT(DISTINCT viewer) as readers").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("blog_id").
+		Scan(&views).Error
+	if err != nil {
+		return err
+	}
+	for _, v := range views {
+		stat(v.BlogID).Views = v.Views
+		stat(v.BlogID).UniqueReaders = v.Readers
+	}
+
+	likes, err := r.countByBlogBetween(&model.Like{}, start, end)
+	if err != nil {
+		return err
+	}
+	for id, n := range likes {
+		stat(id).Likes = n
+	}
+	comments, err := r.countByBlogBetween(&model.Comment{}, start, end)
+	if err != nil {
+		return err
+	}
+	for id, n := range comments {
+		stat(id).Comments = n
+	}
+
+	rows := make([]model.BlogDailyStat, 0, len(stats))
+	active := make([]uint, 0, len(stats))
+	for id, s := range stats {
+		rows = append(rows, *s)
+		active = append(active, id)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		idle := tx.Model(&model.BlogDailyStat{}).Where("day = ?", day)
+		if len(active) > 0 {
+			idle = idle.Where("blog_id NOT IN ?", active)
+		}
+		err := idle.Updates(map[string]interface{}{"views": 0, "unique_readers": 0, "likes": 0, "comments": 0}).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "blog_id"}, {Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"views", "unique_readers", "likes", "comments"}),
+		}).Create(&rows).Error
+	})
+}
+
+// counts the rows of table created in [start, end) per blog
+func (r *Runner) countByBlogBetween(table interface{}, start, end time.Time) (map[uint]int64, error) {
+	type row struct {
+		BlogID uint
+		Count  int64
+	}
+	var rows []row
+	err := r.db.Model(table).
+		Select("blog_id, COUNT(*) as count").
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Group("blog_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.BlogID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,159 @@
+package jobs
+
+import (
+	"Gator_blog/database"
+	"Gator_blog/model"
+	"Gator_blog/ranking"
+	"Gator_blog/redis"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// how many blogs are written back or reconciled per round trip
+const counterBatchSize = 500
+
+// column each Redis counter is written back to
+var counterColumns = map[string]string{
+	redis.CounterLikes:    "likes_count",
+	redis.CounterComments: "comments_count",
+	redis.CounterViews:    "views_count",
+}
+
+// Runner runs the background jobs of one database and Redis store
+type Runner struct {
+	db     *gorm.DB
+	store  *redis.Store
+	ranker *ranking.Ranker
+}
+
+func NewRunner(db *gorm.DB, store *redis.Store, ranker *ranking.Ranker) *Runner {
+	return &Runner{db: db, store: store, ranker: ranker}
+}
+
+// StartCounterSync writes dirty counters back every flushEvery and rebuilds
+// them from the source tables every reconcileEvery.
+func (r *Runner) StartCounterSync(flushEvery, reconcileEvery time.Duration) {
+	go every(flushEvery, "counter write-back", r.FlushCounters)
+	go every(reconcileEvery, "counter reconciliation", r.ReconcileCounters)
+}
+
+// runs fn on a fixed interval for the lifetime of the process
+func every(interval time.Duration, name string, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := fn(); err != nil {
+			log.Println("Error running", name, err)
+		}
+	}
+}
+
+// FlushCounters copies the Redis counters of every blog touched since the
+// last flush into the denormalized count columns of model.Blog.
+func (r *Runner) FlushCounters() error {
+	for {
+		ids, err := r.store.PopDirtyCounters(counterBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		for i, id := range ids {
+			if err := r.flushBlogCounters(id); err != nil {
+				r.store.MarkCountersDirty(ids[i:]...)
+				return err
+			}
+		}
+	}
+}
+
+func (r *Runner) flushBlogCounters(blogID uint) error {
+	updates := map[string]interface{}{}
+	for name, column := range counterColumns {
+		val, found, err := r.store.GetCounter(blogID, name)
+		if err != nil {
+			return err
+		}
+		if found {
+			updates[column] = val
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.Blog{}).Where("id = ?", blogID).UpdateColumns(updates).Error
+}
+
+// ReconcileCounters recounts likes and comments from their tables and
+// overwrites any Redis counter or count column that drifted. Views have no
+// source table, so a Redis counter behind its column is restored from it.
+func (r *Runner) ReconcileCounters() error {
+	var blogs []model.Blog
+	return r.db.Select("id", "likes_count", "comments_count", "views_count").
+		FindInBatches(&blogs, counterBatchSize, func(tx *gorm.DB, batch int) error {
+			ids := make([]uint, len(blogs))
+			for i, blog := range blogs {
+				ids[i] = blog.ID
+			}
+			likes, err := database.CountByBlog(r.db, &model.Like{}, ids)
+			if err != nil {
+				return err
+			}
+			comments, err := database.CountByBlog(r.db, &model.Comment{}, ids)
+			if err != nil {
+				return err
+			}
+			for _, blog := range blogs {
+				actual := map[string]int64{
+					redis.CounterLikes:    likes[blog.ID],
+					redis.CounterComments: comments[blog.ID],
+				}
+				if err := r.reconcileBlog(blog, actual); err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+func (r *Runner) reconcileBlog(blog model.Blog, actual map[string]int64) error {
+	stored := map[string]int64{
+		redis.CounterLikes:    blog.LikesCount,
+		redis.CounterComments: blog.CommentsCount,
+		redis.CounterViews:    blog.ViewsCount,
+	}
+	views, found, err := r.store.GetCounter(blog.ID, redis.CounterViews)
+	if err != nil {
+		return err
+	}
+	if found && views < blog.ViewsCount {
+		log.Printf("Counter drift on blog %d views: redis=%d db=%d", blog.ID, views, blog.ViewsCount)
+		if err := r.store.SetCounter(blog.ID, redis.CounterViews, blog.ViewsCount); err != nil {
+			return err
+		}
+	}
+
+	updates := map[string]interface{}{}
+	for name, want := range actual {
+		cached, found, err := r.store.GetCounter(blog.ID, name)
+		if err != nil {
+			return err
+		}
+		if found && cached != want {
+			log.Printf("Counter drift on blog %d %s: redis=%d actual=%d", blog.ID, name, cached, want)
+			if err := r.store.SetCounter(blog.ID, name, want); err != nil {
+				return err
+			}
+		}
+		if stored[name] != want {
+			updates[counterColumns[name]] = want
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.Blog{}).Where("id = ?", blog.ID).UpdateColumns(updates).Error
+}
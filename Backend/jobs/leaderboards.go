@@ -0,0 +1,8 @@
+package jobs
+
+import "time"
+
+// StartLeaderboardRefresh recomputes the trending leaderboards every interval
+func (r *Runner) StartLeaderboardRefresh(interval time.Duration) {
+	go every(interval, "leaderboard refresh", r.ranker.Refresh)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"Gator_blog/config"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// when v1 was deprecated and when it may stop being served, see
+// InitDeprecation
+var V1Deprecated, V1Sunset = apiDates(config.Defaults(config.Development).API)
+
+// function to set the dates announced by Deprecated. The configuration has
+// been validated, so they parse.
+func InitDeprecation(cfg config.APIConfig) {
+	V1Deprecated, V1Sunset = apiDates(cfg)
+}
+
+func apiDates(cfg config.APIConfig) (time.Time, time.Time) {
+	deprecated, _ := time.Parse(config.DateLayout, cfg.V1Deprecated)
+	sunset, _ := time.Parse(config.DateLayout, cfg.V1Sunset)
+	return deprecated, sunset
+}
+
+// Deprecated announces on every response of the routes under prefix that
+// they are deprecated (RFC 9745), the day they may be withdrawn (RFC 8594)
+// and the same route under successor. Paths under successor, which share
+// the prefix, are left alone.
+func Deprecated(prefix, successor string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		path := c.Path()
+		if path == successor || strings.HasPrefix(path, successor+"/") {
+			return c.Next()
+		}
+		c.Set("Deprecation", "@"+strconv.FormatInt(V1Deprecated.Unix(), 10))
+		c.Set("Sunset", V1Sunset.UTC().Format(http.TimeFormat))
+		c.Append(fiber.HeaderLink, `<`+successor+strings.TrimPrefix(path, prefix)+`>; rel="successor-version"`)
+		return c.Next()
+	}
+}
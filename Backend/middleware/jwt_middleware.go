@@ -1,23 +1,44 @@
 package middleware
 
 import (
+	"Gator_blog/config"
+	"Gator_blog/problem"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-const SecretKey = "your_secret_key"
+// key signing session tokens and how long the tokens last, see InitJWT
+var (
+	SecretKey string
+	TokenTTL  = 24 * time.Hour
+)
+
+// function to set the token signing key and lifetime. Without a configured
+// secret a random one is used, so sessions end when the server restarts.
+func InitJWT(cfg config.JWTConfig) {
+	SecretKey, TokenTTL = cfg.Secret, cfg.TTL
+	if SecretKey != "" {
+		return
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic("Failed to generate a JWT secret: " + err.Error())
+	}
+	SecretKey = hex.EncodeToString(buf)
+	log.Println("No jwt.secret configured, using a random one for this run")
+}
 
 func JWTMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		tokenString := c.Get("Authorization")
 
 		if tokenString == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"statusText": "error",
-				"msg":        "Missing or invalid token",
-			})
+			return problem.Unauthorized("Missing or invalid token")
 		}
 
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -29,26 +50,17 @@ func JWTMiddleware() fiber.Handler {
 
 		if err != nil || !token.Valid {
 			log.Println("Invalid token:", err)
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"statusText": "error",
-				"msg":        "Unauthorized",
-			})
+			return problem.Unauthorized("Unauthorized")
 		}
 		// Extract user email from token claims
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"statusText": "error",
-				"msg":        "Invalid token claims",
-			})
+			return problem.Unauthorized("Invalid token claims")
 		}
 
 		email, ok := claims["email"].(string)
 		if !ok {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"statusText": "error",
-				"msg":        "Email not found in token",
-			})
+			return problem.Unauthorized("Email not found in token")
 		}
 
 		// Store email in request context
@@ -59,3 +71,30 @@ func JWTMiddleware() fiber.Handler {
 
 	}
 }
+
+// OptionalJWTMiddleware sets userEmail like JWTMiddleware when the request
+// carries a valid token, and lets anonymous requests through untouched
+func OptionalJWTMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tokenString := c.Get("Authorization")
+		if tokenString == "" {
+			return c.Next()
+		}
+
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fiber.ErrUnauthorized
+			}
+			return []byte(SecretKey), nil
+		})
+		if err != nil || !token.Valid {
+			return c.Next()
+		}
+		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			if email, ok := claims["email"].(string); ok {
+				c.Locals("userEmail", email)
+			}
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,136 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The schema as AutoMigrate left it before versioned migrations. The types
+// are copies of the models at that point and must not follow later model
+// changes. Creating them is a no-op on databases that already have them, so
+// existing installs adopt the migrations without losing data.
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			type User struct {
+				ID              uint   `gorm:"primaryKey;autoIncrement"`
+				Username        string `gorm:"unique;not null;size:255"`
+				Email           string `gorm:"unique;not null;size:255"`
+				Password        string `gorm:"not null;column:password;size:255"`
+				ResetCode       string `gorm:"size:6"`
+				ResetCodeExpiry time.Time
+				CreatedAt       time.Time
+				AvatarMediaID   *uint
+			}
+			type Tag struct {
+				ID   uint   `gorm:"primaryKey"`
+				Name string `gorm:"uniqueIndex;not null;size:50"`
+			}
+			type Blog struct {
+				ID            uint      `gorm:"primaryKey"`
+				Title         string    `gorm:"not null;column:title;size:255"`
+				Post          string    `gorm:"not null;column:post;size:255"`
+				UserID        uint      `gorm:"not null;index"`
+				UserName      string    `gorm:"not null;column:user_name;size:50"`
+				CreatedAt     time.Time `gorm:"autoCreateTime"`
+				UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+				Tags          []Tag     `gorm:"many2many:blog_tags"`
+				CoverMediaID  *uint
+				LikesCount    int64 `gorm:"not null;default:0"`
+				CommentsCount int64 `gorm:"not null;default:0"`
+				ViewsCount    int64 `gorm:"not null;default:0"`
+			}
+			type Comment struct {
+				ID        uint      `gorm:"primaryKey"`
+				Content   string    `gorm:"not null"`
+				UserID    uint      `gorm:"not null"`
+				UserName  string    `gorm:"not null"`
+				BlogID    uint      `gorm:"not null"`
+				CreatedAt time.Time `gorm:"autoCreateTime"`
+				UpdatedAt time.Time `gorm:"autoUpdateTime"`
+			}
+			type Like struct {
+				ID        uint      `gorm:"primaryKey"`
+				UserID    uint      `gorm:"not null"`
+				BlogID    uint      `gorm:"not null"`
+				CreatedAt time.Time `gorm:"autoCreateTime"`
+			}
+			type BlogView struct {
+				ID        uint `gorm:"primaryKey"`
+				BlogID    uint `gorm:"not null;index:idx_blog_views_blog_created"`
+				UserID    uint
+				Viewer    string    `gorm:"not null;size:64"`
+				CreatedAt time.Time `gorm:"autoCreateTime;index;index:idx_blog_views_blog_created"`
+			}
+			type BlogDailyStat struct {
+				ID            uint   `gorm:"primaryKey"`
+				BlogID        uint   `gorm:"not null;uniqueIndex:idx_blog_daily_stats_blog_day"`
+				Day           string `gorm:"not null;size:10;uniqueIndex:idx_blog_daily_stats_blog_day"`
+				Views         int64
+				UniqueReaders int64
+				Likes         int64
+				Comments      int64
+			}
+			type Follow struct {
+				ID         uint      `gorm:"primaryKey"`
+				FollowerID uint      `gorm:"not null;uniqueIndex:idx_follows_pair"`
+				FolloweeID uint      `gorm:"not null;uniqueIndex:idx_follows_pair;index"`
+				CreatedAt  time.Time `gorm:"autoCreateTime"`
+			}
+			type Bookmark struct {
+				ID        uint      `gorm:"primaryKey"`
+				UserID    uint      `gorm:"not null;uniqueIndex:idx_bookmarks_user_blog"`
+				BlogID    uint      `gorm:"not null;uniqueIndex:idx_bookmarks_user_blog;index"`
+				CreatedAt time.Time `gorm:"autoCreateTime"`
+			}
+			type ReadingList struct {
+				ID         uint      `gorm:"primaryKey"`
+				UserID     uint      `gorm:"not null;index"`
+				Name       string    `gorm:"not null;size:100"`
+				Public     bool      `gorm:"not null;default:false"`
+				ShareToken *string   `gorm:"uniqueIndex;size:32"`
+				CreatedAt  time.Time `gorm:"autoCreateTime"`
+				UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+			}
+			type ReadingListItem struct {
+				ID            uint      `gorm:"primaryKey"`
+				ReadingListID uint      `gorm:"not null;uniqueIndex:idx_reading_list_items_list_blog"`
+				BlogID        uint      `gorm:"not null;uniqueIndex:idx_reading_list_items_list_blog;index"`
+				Position      int       `gorm:"not null"`
+				CreatedAt     time.Time `gorm:"autoCreateTime"`
+			}
+			type MediaVariant struct {
+				ID      uint   `gorm:"primaryKey"`
+				MediaID uint   `gorm:"not null;index"`
+				Name    string `gorm:"not null;size:20"`
+				Key     string `gorm:"not null;size:255"`
+				Width   int
+				Height  int
+				Size    int64
+			}
+			type Media struct {
+				ID          uint   `gorm:"primaryKey"`
+				UserID      uint   `gorm:"not null;index"`
+				Purpose     string `gorm:"not null;size:20;default:library"`
+				Key         string `gorm:"not null;size:255"`
+				Filename    string `gorm:"size:255"`
+				ContentType string `gorm:"not null;size:50"`
+				Size        int64
+				Width       int
+				Height      int
+				Variants    []MediaVariant `gorm:"foreignKey:MediaID"`
+				CreatedAt   time.Time
+			}
+
+			return tx.AutoMigrate(&User{}, &Tag{}, &Blog{}, &Comment{}, &Like{}, &BlogView{}, &BlogDailyStat{},
+				&Follow{}, &Bookmark{}, &ReadingList{}, &ReadingListItem{}, &Media{}, &MediaVariant{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("media_variants", "media", "reading_list_items", "reading_lists", "bookmarks",
+				"follows", "blog_daily_stats", "blog_views", "likes", "comments", "blog_tags", "blogs", "tags", "users")
+		},
+	})
+}
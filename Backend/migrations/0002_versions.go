@@ -0,0 +1,41 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Version columns for optimistic concurrency on blog and comment edits.
+// Existing rows start at version 1, columns already there are left alone.
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "versions",
+		Up: func(tx *gorm.DB) error {
+			type Blog struct {
+				Version int64 `gorm:"not null;default:1"`
+			}
+			type Comment struct {
+				Version int64 `gorm:"not null;default:1"`
+			}
+			for _, table := range []interface{}{&Blog{}, &Comment{}} {
+				if tx.Migrator().HasColumn(table, "Version") {
+					continue
+				}
+				if err := tx.Migrator().AddColumn(table, "Version"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			type Blog struct {
+				Version int64
+			}
+			type Comment struct {
+				Version int64
+			}
+			if err := tx.Migrator().DropColumn(&Comment{}, "Version"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&Blog{}, "Version")
+		},
+	})
+}
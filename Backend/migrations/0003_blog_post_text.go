@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Blog posts as text, they outgrew the 255 characters of the initial schema.
+// MySQL's TEXT holds 64KB, so it gets MEDIUMTEXT. SQLite does not enforce
+// the size of a column and is left alone.
+//
+// Rolling back cannot keep posts longer than 255 characters, so Down refuses
+// while there are any, on every database alike. Shorten or delete them first
+// to roll back.
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "blog_post_text",
+		Up: func(tx *gorm.DB) error {
+			switch tx.Dialector.Name() {
+			case "mysql":
+				return tx.Exec("ALTER TABLE blogs MODIFY post MEDIUMTEXT NOT NULL").Error
+			case "postgres":
+				return tx.Exec("ALTER TABLE blogs ALTER COLUMN post TYPE TEXT").Error
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			length := "LENGTH(post)"
+			if tx.Dialector.Name() == "mysql" {
+				// LENGTH counts bytes there
+				length = "CHAR_LENGTH(post)"
+			}
+			var longest int64
+			if err := tx.Table("blogs").Select("COALESCE(MAX(" + length + "), 0)").Scan(&longest).Error; err != nil {
+				return err
+			}
+			if longest > 255 {
+				return fmt.Errorf("the longest post has %d characters, posts must fit in 255 to roll back", longest)
+			}
+			switch tx.Dialector.Name() {
+			case "mysql":
+				return tx.Exec("ALTER TABLE blogs MODIFY post VARCHAR(255) NOT NULL").Error
+			case "postgres":
+				return tx.Exec("ALTER TABLE blogs ALTER COLUMN post TYPE VARCHAR(255)").Error
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,4 @@
+package migrations
+
+// WithLock runs fn while holding the migration lock
+var WithLock = withLock
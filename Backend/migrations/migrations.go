@@ -0,0 +1,262 @@
+// Package migrations keeps the database schema in step with the code through
+// numbered up/down migrations. Applied versions are recorded in the
+// schema_migrations table and a row in schema_migrations_lock makes sure only
+// one instance migrates at a time.
+package migrations
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Migration is one versioned change to the schema. Up and Down run in a
+// transaction together with the bookkeeping, but MySQL commits DDL
+// implicitly, so a migration should make a single change where possible.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// State of a migration in a database. Unknown migrations were applied by a
+// newer build and have no Up or Down here.
+type State struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Unknown   bool
+}
+
+// how long to wait for another instance to finish migrating, and when a lock
+// left behind by a crashed instance may be taken over
+var (
+	LockTimeout    = 2 * time.Minute
+	StaleLockAfter = 15 * time.Minute
+	lockPoll       = 500 * time.Millisecond
+)
+
+var ErrLocked = errors.New("migrations are locked by another instance")
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null;size:255"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string { return "schema_migrations" }
+
+// at most one row, held by the instance running migrations
+type migrationLock struct {
+	ID       int       `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string    `gorm:"not null;size:32"`
+	LockedAt time.Time `gorm:"not null"`
+}
+
+func (migrationLock) TableName() string { return "schema_migrations_lock" }
+
+var registry []Migration
+
+// adds a migration, called from the init of each migration file
+func register(m Migration) {
+	registry = append(registry, m)
+}
+
+// All returns the known migrations ordered by version
+func All() []Migration {
+	all := append([]Migration(nil), registry...)
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all
+}
+
+// Up applies every pending migration in order and returns the ones applied
+func Up(db *gorm.DB) ([]Migration, error) {
+	var done []Migration
+	err := withLock(db, func() error {
+		applied, err := appliedVersions(db)
+		if err != nil {
+			return err
+		}
+		for _, m := range All() {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := m.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d %s: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Applied migration %d %s", m.Version, m.Name)
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down rolls back the last steps applied migrations, newest first, and
+// returns the ones rolled back
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	var done []Migration
+	err := withLock(db, func() error {
+		var rows []SchemaMigration
+		if err := db.Order("version DESC").Limit(steps).Find(&rows).Error; err != nil {
+			return err
+		}
+		known := map[int64]Migration{}
+		for _, m := range All() {
+			known[m.Version] = m
+		}
+		for _, row := range rows {
+			m, ok := known[row.Version]
+			if !ok || m.Down == nil {
+				return fmt.Errorf("migration %d %s cannot be rolled back by this build", row.Version, row.Name)
+			}
+			err := db.Transaction(func(tx *gorm.DB) error {
+				if err := m.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, row.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rolling back migration %d %s: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Rolled back migration %d %s", m.Version, m.Name)
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists every known or applied migration ordered by version
+func Status(db *gorm.DB) ([]State, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	var states []State
+	for _, m := range All() {
+		state := State{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			state.Applied, state.AppliedAt = true, &row.AppliedAt
+			delete(applied, m.Version)
+		}
+		states = append(states, state)
+	}
+	for _, row := range applied {
+		row := row
+		states = append(states, State{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: &row.AppliedAt, Unknown: true})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Version < states[j].Version })
+	return states, nil
+}
+
+// applied migrations keyed by version
+func appliedVersions(db *gorm.DB) (map[int64]SchemaMigration, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// creates the lock table unless it exists. It cannot be left to
+// AutoMigrate, which checks and creates in two steps that instances starting
+// together would race through before any of them holds the lock.
+func createLockTable(db *gorm.DB) error {
+	lockedAt := "DATETIME"
+	switch db.Dialector.Name() {
+	case "mysql":
+		lockedAt = "DATETIME(3)"
+	case "postgres":
+		lockedAt = "TIMESTAMPTZ"
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return tx.Exec("CREATE TABLE IF NOT EXISTS schema_migrations_lock (" +
+			"id INTEGER NOT NULL PRIMARY KEY, owner VARCHAR(32) NOT NULL, locked_at " + lockedAt + " NOT NULL)").Error
+	})
+	// PostgreSQL may still refuse one of two concurrent creates
+	if err != nil && db.Migrator().HasTable(&migrationLock{}) {
+		return nil
+	}
+	return err
+}
+
+// runs fn while holding the migration lock, waiting up to LockTimeout for it.
+// The lock is renewed while fn runs, so that only an instance that stopped
+// leaves it to go stale.
+func withLock(db *gorm.DB, fn func() error) error {
+	if err := createLockTable(db); err != nil {
+		return err
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	owner := hex.EncodeToString(buf)
+
+	// the primary key makes the insert fail while someone else holds the
+	// lock, which is expected and not worth logging
+	quiet := db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		if quiet.Create(&migrationLock{ID: 1, Owner: owner, LockedAt: time.Now()}).Error == nil {
+			break
+		}
+		var held migrationLock
+		if err := quiet.First(&held, 1).Error; err == nil && time.Since(held.LockedAt) > StaleLockAfter {
+			log.Printf("Taking over stale migration lock from %s", held.Owner)
+			db.Where("id = 1 AND owner = ?", held.Owner).Delete(&migrationLock{})
+			continue
+		}
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+		time.Sleep(lockPoll)
+	}
+	defer db.Where("id = 1 AND owner = ?", owner).Delete(&migrationLock{})
+
+	done := make(chan struct{})
+	defer close(done)
+	go renewLock(db, owner, StaleLockAfter/3, done)
+	return fn()
+}
+
+// moves the lock of owner on every interval until done is closed
+func renewLock(db *gorm.DB, owner string, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			err := db.Model(&migrationLock{}).Where("id = 1 AND owner = ?", owner).Update("locked_at", time.Now()).Error
+			if err != nil {
+				log.Println("Error renewing migration lock", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package migrations_test
+
+import (
+	"Gator_blog/database/dbtest"
+	"Gator_blog/migrations"
+	"Gator_blog/model"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	return dbtest.Open(t)
+}
+
+var models = []interface{}{&model.User{}, &model.Tag{}, &model.Blog{}, &model.Comment{}, &model.Like{}, &model.BlogView{},
+	&model.BlogDailyStat{}, &model.Follow{}, &model.Bookmark{}, &model.ReadingList{}, &model.ReadingListItem{},
+	&model.Media{}, &model.MediaVariant{}}
+
+// Test that the migrations create every column the models use, so a model
+// change without a migration fails here
+func TestSchemaMatchesModels(t *testing.T) {
+	db := openDB(t)
+	_, err := migrations.Up(db)
+	assert.Nil(t, err)
+
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		assert.Nil(t, stmt.Parse(m))
+		assert.True(t, db.Migrator().HasTable(m), stmt.Schema.Table)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" {
+				assert.True(t, db.Migrator().HasColumn(m, field.DBName), stmt.Schema.Table+"."+field.DBName)
+			}
+		}
+	}
+	assert.True(t, db.Migrator().HasTable("blog_tags"))
+}
+
+func TestUpDownStatus(t *testing.T) {
+	db := openDB(t)
+	all := migrations.All()
+
+	applied, err := migrations.Up(db)
+	assert.Nil(t, err)
+	assert.Len(t, applied, len(all))
+
+	applied, err = migrations.Up(db)
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+
+	states, err := migrations.Status(db)
+	assert.Nil(t, err)
+	assert.Len(t, states, len(all))
+	for _, state := range states {
+		assert.True(t, state.Applied)
+		assert.NotNil(t, state.AppliedAt)
+	}
+
+	rolledBack, err := migrations.Down(db, len(all))
+	assert.Nil(t, err)
+	assert.Len(t, rolledBack, len(all))
+	assert.False(t, db.Migrator().HasTable(&model.Blog{}))
+	states, _ = migrations.Status(db)
+	assert.False(t, states[0].Applied)
+}
+
+// Test that existing databases created by AutoMigrate adopt the migrations
+// without losing rows
+func TestBaselineOnExistingSchema(t *testing.T) {
+	db := openDB(t)
+	assert.Nil(t, db.AutoMigrate(models...))
+	db.Create(&model.User{Username: "alice", Email: "alice@example.com", Password: "x"})
+
+	_, err := migrations.Up(db)
+	assert.Nil(t, err)
+	var count int64
+	db.Model(&model.User{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// Test that a second instance waits for the lock and takes over stale ones
+func TestLock(t *testing.T) {
+	db := openDB(t)
+	_, err := migrations.Up(db)
+	assert.Nil(t, err)
+
+	timeout := migrations.LockTimeout
+	migrations.LockTimeout = 10 * time.Millisecond
+	t.Cleanup(func() { migrations.LockTimeout = timeout })
+
+	db.Exec("INSERT INTO schema_migrations_lock (id, owner, locked_at) VALUES (1, 'other', ?)", time.Now())
+	_, err = migrations.Up(db)
+	assert.Equal(t, migrations.ErrLocked, err)
+
+	db.Exec("UPDATE schema_migrations_lock SET locked_at = ?", time.Now().Add(-migrations.StaleLockAfter-time.Minute))
+	_, err = migrations.Up(db)
+	assert.Nil(t, err)
+
+	var count int64
+	db.Table("schema_migrations_lock").Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+// Test that the lock is kept fresh while migrations run longer than it
+// takes to go stale
+func TestLockRenewed(t *testing.T) {
+	db := openDB(t)
+	stale := migrations.StaleLockAfter
+	migrations.StaleLockAfter = 60 * time.Millisecond
+	t.Cleanup(func() { migrations.StaleLockAfter = stale })
+
+	err := migrations.WithLock(db, func() error {
+		time.Sleep(150 * time.Millisecond)
+		var lockedAt time.Time
+		assert.Nil(t, db.Table("schema_migrations_lock").Select("locked_at").Row().Scan(&lockedAt))
+		assert.Less(t, time.Since(lockedAt), migrations.StaleLockAfter)
+		return nil
+	})
+	assert.Nil(t, err)
+}
+
+// Test that posts as text are not rolled back while a post would not fit
+func TestBlogPostTextRollbackKeepsLongPosts(t *testing.T) {
+	db := openDB(t)
+	_, err := migrations.Up(db)
+	assert.Nil(t, err)
+	blog := model.Blog{Title: "Long", Post: strings.Repeat("é", 256), UserName: "alice"}
+	assert.Nil(t, db.Create(&blog).Error)
+
+	// roll back to before blog_post_text
+	steps := 0
+	for _, m := range migrations.All() {
+		if m.Version >= 3 {
+			steps++
+		}
+	}
+	_, err = migrations.Down(db, steps)
+	assert.ErrorContains(t, err, "256 characters")
+	var stored model.Blog
+	db.First(&stored, blog.ID)
+	assert.Equal(t, blog.Post, stored.Post)
+	states, _ := migrations.Status(db)
+	assert.True(t, states[2].Applied)
+
+	db.Model(&blog).Update("post", strings.Repeat("é", 255))
+	rolledBack, err := migrations.Down(db, steps)
+	assert.Nil(t, err)
+	assert.Len(t, rolledBack, steps)
+}
@@ -5,9 +5,21 @@ import "time"
 type Blog struct {
 	ID        uint      `json: "id" gorm:"primaryKey"`
 	Title     string    `json: "title" gorm:"not null;column:title;size:255"`
-	Post      string    `json: "post" gorm:"not null;column:post;size:255"`
+	Post      string    `json: "post" gorm:"not null;column:post;type:text"`
 	UserID    uint      `json:"user_id" gorm:"not null;index"` // Foreign key
 	UserName  string    `json: "user_name" gorm:"not null;column:user_name;size:50"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	Tags      []Tag     `json:"tags,omitempty" gorm:"many2many:blog_tags"`
+
+	CoverMediaID *uint `json:"cover_media_id"` // cropped cover image, see Media
+
+	// Denormalized counters, written back periodically from Redis
+	LikesCount    int64 `json:"likes_count" gorm:"not null;default:0"`
+	CommentsCount int64 `json:"comments_count" gorm:"not null;default:0"`
+	ViewsCount    int64 `json:"views_count" gorm:"not null;default:0"`
+
+	// Version counts the edits of the blog, an update only applies to the
+	// version it was made on
+	Version int64 `json:"version" gorm:"not null;default:1"`
 }
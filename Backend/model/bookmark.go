@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+type Bookmark struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_bookmarks_user_blog"`
+	BlogID    uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_bookmarks_user_blog;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ReadingList is a named, ordered collection of blogs kept by a user
+type ReadingList struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;index"`
+	Name       string    `json:"name" gorm:"not null;size:100"`
+	Public     bool      `json:"public" gorm:"not null;default:false"`
+	ShareToken *string   `json:"share_token,omitempty" gorm:"uniqueIndex;size:32"` // set while the list is public
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type ReadingListItem struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ReadingListID uint      `json:"reading_list_id" gorm:"not null;uniqueIndex:idx_reading_list_items_list_blog"`
+	BlogID        uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_reading_list_items_list_blog;index"`
+	Position      int       `json:"position" gorm:"not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -10,4 +10,5 @@ type Comment struct {
 	BlogID    uint      `json:"blog_id" gorm:"not null"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	Version   int64     `json:"version" gorm:"not null;default:1"` // see Blog.Version
 }
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Follow struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	FollowerID uint      `json:"follower_id" gorm:"not null;uniqueIndex:idx_follows_pair"`
+	FolloweeID uint      `json:"followee_id" gorm:"not null;uniqueIndex:idx_follows_pair;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
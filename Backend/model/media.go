@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// purposes of an upload
+const (
+	MediaLibrary = "library"
+	MediaCover   = "cover"
+	MediaAvatar  = "avatar"
+)
+
+// Media is an image uploaded by a user. The stored original has had its
+// metadata stripped, Variants are resized copies of it.
+type Media struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	Purpose     string         `json:"purpose" gorm:"not null;size:20;default:library"`
+	Key         string         `json:"-" gorm:"not null;size:255"`
+	URL         string         `json:"url" gorm:"-"`
+	Filename    string         `json:"filename" gorm:"size:255"`
+	ContentType string         `json:"content_type" gorm:"not null;size:50"`
+	Size        int64          `json:"size"`
+	Width       int            `json:"width"`
+	Height      int            `json:"height"`
+	Variants    []MediaVariant `json:"variants" gorm:"foreignKey:MediaID"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+type MediaVariant struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	MediaID uint   `json:"-" gorm:"not null;index"`
+	Name    string `json:"name" gorm:"not null;size:20"`
+	Key     string `json:"-" gorm:"not null;size:255"`
+	URL     string `json:"url" gorm:"-"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Size    int64  `json:"size"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "encoding/json"
+
+type Tag struct {
+	ID   uint   `json:"id" gorm:"primaryKey"`
+	Name string `json:"name" gorm:"uniqueIndex;not null;size:50" validate:"max=50,tag"`
+}
+
+// UnmarshalJSON accepts a tag either as an object or as its bare name, so
+// clients can send "tags": ["go", "web"]
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = Tag{Name: name}
+		return nil
+	}
+	type plain Tag
+	return json.Unmarshal(data, (*plain)(t))
+}
@@ -10,5 +10,7 @@ type User struct {
 	Password         string `json:"password" gorm:"not null;column:password;size:255"`
 	ResetCode        string `json:"-" gorm:"size:6"`
 	ResetCodeExpiry  time.Time `json:"-"`
+	CreatedAt        time.Time `json:"created_at"`
+	AvatarMediaID    *uint     `json:"avatar_media_id"` // uploaded avatar, an identicon is shown without one
 }
 
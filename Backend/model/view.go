@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// BlogView is a single deduplicated read of a blog by a human reader
+type BlogView struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BlogID    uint      `json:"blog_id" gorm:"not null;index:idx_blog_views_blog_created"`
+	UserID    uint      `json:"user_id"`                   // 0 for anonymous readers
+	Viewer    string    `json:"-" gorm:"not null;size:64"` // hashed user id or IP address
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime;index;index:idx_blog_views_blog_created"`
+}
+
+// layout of BlogDailyStat.Day
+const DayLayout = "2006-01-02"
+
+// BlogDailyStat is the per day rollup of views, readers, likes and comments of a blog
+type BlogDailyStat struct {
+	ID            uint   `json:"-" gorm:"primaryKey"`
+	BlogID        uint   `json:"blog_id" gorm:"not null;uniqueIndex:idx_blog_daily_stats_blog_day"`
+	Day           string `json:"day" gorm:"not null;size:10;uniqueIndex:idx_blog_daily_stats_blog_day"` // YYYY-MM-DD
+	Views         int64  `json:"views"`
+	UniqueReaders int64  `json:"unique_readers"`
+	Likes         int64  `json:"likes"`
+	Comments      int64  `json:"comments"`
+}
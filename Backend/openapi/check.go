@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"Gator_blog/validate"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Check reports every way value departs from s. value is JSON decoded with
+// UseNumber, what is named in errors is the path to the offending member,
+// e.g. tags[1].name, or body for the whole value.
+func (d *Document) Check(s *Schema, value interface{}) validate.Errors {
+	var errs validate.Errors
+	d.check(s, value, "", &errs)
+	return errs
+}
+
+func (d *Document) check(s *Schema, value interface{}, path string, errs *validate.Errors) {
+	s = d.Resolve(s)
+	if s == nil {
+		return
+	}
+	name := path
+	if name == "" {
+		name = "body"
+	}
+	fail := func(rule, format string, args ...interface{}) {
+		*errs = append(*errs, validate.FieldError{Field: name, Rule: rule, Message: name + " " + fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.AnyOf) > 0 {
+		for _, alternative := range s.AnyOf {
+			var none validate.Errors
+			if d.check(alternative, value, path, &none); len(none) == 0 {
+				return
+			}
+		}
+		fail("anyOf", "does not match any of its allowed forms")
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.Has(jsonType(value)) && !(jsonType(value) == "integer" && s.Type.Has("number")) {
+		fail("type", "must be %s", strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, value) {
+		fail("enum", "must be one of %v", s.Enum)
+		return
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("minLength", "must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("maxLength", "must be at most %d characters", *s.MaxLength)
+		}
+		if s.Pattern != "" && !compiled(s.Pattern).MatchString(v) {
+			fail("pattern", "must match %s", s.Pattern)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, v); err != nil {
+				fail("format", "must be a date-time")
+			}
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			if s.Minimum != nil && n < *s.Minimum {
+				fail("minimum", "must be at least %d", *s.Minimum)
+			}
+			if s.Maximum != nil && n > *s.Maximum {
+				fail("maximum", "must be at most %d", *s.Maximum)
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			fail("minItems", "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			fail("maxItems", "must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				d.check(s.Items, item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case map[string]interface{}:
+		for _, required := range s.Required {
+			if _, ok := v[required]; !ok {
+				*errs = append(*errs, validate.FieldError{Field: join(path, required), Rule: "required",
+					Message: join(path, required) + " is required"})
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if prop, ok := s.Properties[key]; ok {
+				d.check(prop, v[key], join(path, key), errs)
+				continue
+			}
+			switch extra := s.AdditionalProperties.(type) {
+			case bool:
+				if !extra {
+					*errs = append(*errs, validate.FieldError{Field: join(path, key), Rule: "additionalProperties",
+						Message: join(path, key) + " is not allowed"})
+				}
+			case *Schema:
+				d.check(extra, v[key], join(path, key), errs)
+			}
+		}
+	}
+}
+
+func join(path, member string) string {
+	if path == "" {
+		return member
+	}
+	return path + "." + member
+}
+
+// the JSON Schema type of a decoded value
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+var patterns sync.Map
+
+func compiled(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
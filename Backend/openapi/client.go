@@ -0,0 +1,412 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoClient writes the Go source of package pkg calling the operations of d:
+// a type per component schema and a method of Client per operation. The
+// package supplies Client and its send, upload and RequestOption itself,
+// see package client.
+func (d *Document) GoClient(pkg string) ([]byte, error) {
+	w := &clientWriter{doc: d}
+
+	names := make([]string, 0, len(d.Components.Schemas))
+	for name := range d.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := d.Components.Schemas[name]
+		if s.Description != "" {
+			w.printf("// %s %s\n", goName(name), s.Description)
+		}
+		w.printf("type %s %s\n\n", goName(name), w.goType(s, goName(name), true))
+	}
+
+	for _, route := range d.Operations() {
+		if err := w.operation(route); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by cmd/openapi from the OpenAPI document of the API. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\nimport (\n", pkg)
+	for _, imp := range []string{"context", "encoding/json", "io", "net/http", "net/url", "strconv", "time"} {
+		short := imp[strings.LastIndex(imp, "/")+1:]
+		if regexp.MustCompile(`\b`+short+`\.`).Match(w.types.Bytes()) ||
+			regexp.MustCompile(`\b`+short+`\.`).Match(w.body.Bytes()) {
+			fmt.Fprintf(&out, "\t%q\n", imp)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(w.body.Bytes())
+	out.Write(w.types.Bytes())
+	return format.Source(out.Bytes())
+}
+
+type clientWriter struct {
+	doc *Document
+	// component types and methods, then the types of inline bodies and
+	// parameters
+	body, types bytes.Buffer
+	// declares the query and header of the operation being written
+	paramsCode string
+}
+
+func (w *clientWriter) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&w.body, format, args...)
+}
+
+// the Go type of s. Objects with properties are written as struct types,
+// named ones are declared among the types when top is false.
+func (w *clientWriter) goType(s *Schema, name string, top bool) string {
+	if s == nil {
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		return goName(strings.TrimPrefix(s.Ref, "#/components/schemas/"))
+	}
+	if len(s.AnyOf) > 0 {
+		// s or null is a pointer, other unions take their first alternative
+		if len(s.AnyOf) == 2 && s.AnyOf[1].Type.Has("null") && len(s.AnyOf[1].Type) == 1 {
+			return "*" + w.goType(s.AnyOf[0], name, false)
+		}
+		return w.goType(s.AnyOf[0], name, top)
+	}
+	pointer := ""
+	if s.Type.Has("null") {
+		pointer = "*"
+	}
+	switch {
+	case s.Type.Has("string"):
+		if s.Format == "date-time" {
+			return pointer + "time.Time"
+		}
+		return pointer + "string"
+	case s.Type.Has("integer"):
+		return pointer + "int64"
+	case s.Type.Has("number"):
+		return pointer + "float64"
+	case s.Type.Has("boolean"):
+		return pointer + "bool"
+	case s.Type.Has("array"):
+		return "[]" + w.goType(s.Items, name+"Item", false)
+	case s.Type.Has("object"):
+		if len(s.Properties) == 0 {
+			if extra, ok := s.AdditionalProperties.(*Schema); ok && extra != nil {
+				return "map[string]" + w.goType(extra, name+"Value", false)
+			}
+			return "map[string]json.RawMessage"
+		}
+		fields := w.structType(s, name)
+		if top {
+			return fields
+		}
+		fmt.Fprintf(&w.types, "type %s %s\n\n", name, fields)
+		return pointer + name
+	}
+	return "json.RawMessage"
+}
+
+func (w *clientWriter) structType(s *Schema, name string) string {
+	props := make([]string, 0, len(s.Properties))
+	for prop := range s.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, prop := range props {
+		field := goName(prop)
+		tag := prop
+		if !contains(s.Required, prop) {
+			tag += ",omitempty"
+		}
+		if desc := s.Properties[prop].Description; desc != "" {
+			fmt.Fprintf(&b, "\t// %s %s\n", field, desc)
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, w.goType(s.Properties[prop], name+field, false), tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (w *clientWriter) operation(route Route) error {
+	method := goName(route.OperationID)
+	args := []string{"ctx context.Context"}
+	call := []string{"ctx", strconv.Quote(route.Method)}
+
+	// the path, with its parameters spliced in
+	var path []string
+	literal := ""
+	for i, part := range strings.Split(route.Path, "/") {
+		if i > 0 {
+			literal += "/"
+		}
+		if !strings.HasPrefix(part, "{") {
+			literal += part
+			continue
+		}
+		if literal != "" {
+			path = append(path, strconv.Quote(literal))
+			literal = ""
+		}
+		name := part[1 : len(part)-1]
+		arg := argName(name)
+		p := parameter(route.Parameters, name, "path")
+		if p == nil {
+			return fmt.Errorf("openapi: %s has no path parameter %s", route.OperationID, name)
+		}
+		if p.Schema.Type.Has("integer") {
+			args = append(args, arg+" int64")
+			path = append(path, "strconv.FormatInt("+arg+", 10)")
+		} else {
+			args = append(args, arg+" string")
+			path = append(path, "url.PathEscape("+arg+")")
+		}
+	}
+	if literal != "" {
+		path = append(path, strconv.Quote(literal))
+	}
+	call = append(call, strings.Join(path, "+"))
+
+	// query and header parameters are fields of a struct
+	var params []*Parameter
+	for _, p := range route.Parameters {
+		if p.In == "query" || p.In == "header" {
+			params = append(params, p)
+		}
+	}
+	if len(params) > 0 {
+		name := method + "Params"
+		var fields, set strings.Builder
+		for _, p := range params {
+			field := goName(p.Name)
+			typ, value := "string", "params."+field
+			if p.Schema.Type.Has("integer") {
+				typ, value = "int64", "strconv.FormatInt(params."+field+", 10)"
+			}
+			if p.Description != "" {
+				fmt.Fprintf(&fields, "\t// %s is the %s %s, %s\n", field, p.In, p.Name, p.Description)
+			}
+			fmt.Fprintf(&fields, "\t%s %s\n", field, typ)
+			zero := `""`
+			if typ == "int64" {
+				zero = "0"
+			}
+			target := "query"
+			if p.In == "header" {
+				target = "header"
+			}
+			fmt.Fprintf(&set, "\tif params.%s != %s {\n\t\t%s.Set(%q, %s)\n\t}\n", field, zero, target, p.Name, value)
+		}
+		fmt.Fprintf(&w.types, "// %s are the parameters of %s, zero values are left out\ntype %s struct {\n%s}\n\n",
+			name, method, name, fields.String())
+		args = append(args, "params *"+name)
+		call = append(call, "query", "header")
+		w.paramsCode = "\tquery, header := url.Values{}, http.Header{}\n\tif params != nil {\n" +
+			indent(set.String()) + "\t}\n"
+	} else {
+		call = append(call, "nil", "nil")
+		w.paramsCode = ""
+	}
+
+	// the body, JSON or a multipart upload
+	upload := false
+	if rb := route.RequestBody; rb != nil {
+		media, content := requestMedia(rb.Content)
+		switch {
+		case media == "multipart/form-data":
+			upload = true
+			field := "file"
+			if s := w.doc.Resolve(content.Schema); s != nil {
+				for prop, ps := range s.Properties {
+					if ps.ContentMediaType != "" {
+						field = prop
+					}
+				}
+			}
+			args = append(args, "filename string", "file io.Reader")
+			call = append(call, strconv.Quote(field), "filename", "file")
+		default:
+			args = append(args, "body "+w.goType(content.Schema, method+"Request", false))
+			call = append(call, "body", strconv.Quote(media))
+		}
+	} else {
+		call = append(call, "nil", `""`)
+	}
+
+	// the result, typed when every successful answer has the same schema
+	result, raw := w.result(route, method)
+	if result == "" {
+		call = append(call, "nil", "opts")
+	} else {
+		call = append(call, "&out", "opts")
+	}
+	args = append(args, "opts ...RequestOption")
+	send := "send"
+	if upload {
+		send = "upload"
+	}
+
+	w.printf("// %s calls %s %s", method, route.Method, route.Path)
+	if route.Summary != "" {
+		// a sentence, or gofmt takes the line for a heading
+		w.printf("\n//\n// %s.", strings.TrimSuffix(route.Summary, "."))
+	}
+	if route.Deprecated {
+		w.printf("\n//\n// Deprecated: the operation is deprecated.")
+	}
+	w.printf("\n")
+	switch {
+	case result == "":
+		w.printf("func (c *Client) %s(%s) error {\n%s\treturn c.%s(%s)\n}\n\n",
+			method, strings.Join(args, ", "), w.paramsCode, send, strings.Join(call, ", "))
+	case raw:
+		w.printf("func (c *Client) %s(%s) (%s, error) {\n%s\tvar out %s\n\terr := c.%s(%s)\n\treturn out, err\n}\n\n",
+			method, strings.Join(args, ", "), result, w.paramsCode, result, send, strings.Join(call, ", "))
+	default:
+		w.printf("func (c *Client) %s(%s) (*%s, error) {\n%s\tvar out %s\n\tif err := c.%s(%s); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &out, nil\n}\n\n",
+			method, strings.Join(args, ", "), result, w.paramsCode, result, send, strings.Join(call, ", "))
+	}
+	return nil
+}
+
+// the Go type of the successful answers of route, and whether it is
+// returned by value: []byte, json.RawMessage and slices are
+func (w *clientWriter) result(route Route, method string) (string, bool) {
+	var schemas []*Schema
+	body := false
+	statuses := make([]string, 0, len(route.Responses))
+	for status := range route.Responses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for _, content := range route.Responses[status].Content {
+			body = true
+			if content.Schema != nil {
+				schemas = append(schemas, content.Schema)
+			}
+		}
+	}
+	switch {
+	case !body:
+		return "", false
+	case len(schemas) == 0:
+		return "[]byte", true
+	}
+	first, _ := json.Marshal(schemas[0])
+	for _, s := range schemas[1:] {
+		if other, _ := json.Marshal(s); !bytes.Equal(first, other) {
+			return "json.RawMessage", true
+		}
+	}
+	typ := w.goType(schemas[0], method+"Response", false)
+	if strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") {
+		return typ, true
+	}
+	return typ, false
+}
+
+// the media type a body is sent as: a JSON variant such as JSON Merge Patch
+// over plain JSON, which is the default
+func requestMedia(content map[string]MediaType) (string, MediaType) {
+	media := make([]string, 0, len(content))
+	for m := range content {
+		media = append(media, m)
+	}
+	sort.Slice(media, func(i, j int) bool {
+		vi, vj := strings.HasSuffix(media[i], "+json"), strings.HasSuffix(media[j], "+json")
+		if vi != vj {
+			return vi
+		}
+		return media[i] < media[j]
+	})
+	return media[0], content[media[0]]
+}
+
+func parameter(params []*Parameter, name, in string) *Parameter {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return p
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func indent(code string) string {
+	return strings.ReplaceAll(strings.TrimSuffix("\t"+strings.ReplaceAll(code, "\n", "\n\t"), "\t"), "\t\t\n", "\n")
+}
+
+// words written in capitals in Go names
+var initialisms = map[string]bool{"API": true, "ID": true, "URL": true, "SEO": true, "JSON": true, "HTML": true, "HTTP": true}
+
+// the words of a JSON or operation name: statusText, next_cursor, BlogSEO
+// and If-None-Match have two or three
+func words(name string) []string {
+	var out []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		boundary := r == '_' || r == '-' || r == ' ' || r == '.'
+		if boundary || (i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1])) {
+			if len(word) > 0 {
+				out = append(out, string(word))
+			}
+			word = nil
+			if boundary {
+				continue
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		out = append(out, string(word))
+	}
+	return out
+}
+
+// goName is the exported Go name of a JSON or operation name
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			b.WriteString(exported(word))
+		}
+	}
+	return b.String()
+}
+
+// the Go argument of a path parameter: id, commentID
+func argName(name string) string {
+	parts := words(name)
+	if len(parts) == 0 {
+		return name
+	}
+	return strings.ToLower(parts[0]) + goName(strings.Join(parts[1:], "_"))
+}
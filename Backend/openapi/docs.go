@@ -0,0 +1,36 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed docs.html
+var docsPage string
+
+// Docs serves a page browsing the document found at specURL. The page is
+// self-contained, it loads nothing but the document.
+func Docs(specURL string) fiber.Handler {
+	page := []byte(strings.ReplaceAll(docsPage, "{{SPEC_URL}}", html.EscapeString(specURL)))
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.Send(page)
+	}
+}
+
+// Serve answers with doc as JSON, rendered once
+func Serve(doc *Document) fiber.Handler {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		panic("openapi: " + err.Error())
+	}
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.Send(body)
+	}
+}
@@ -0,0 +1,283 @@
+// Package openapi describes the HTTP API as an OpenAPI 3.1 document. The
+// operations are added one per route with the Go types of their bodies,
+// which are reflected into JSON Schemas, the validate tags of request
+// bodies included, so the document follows the handlers and DTOs as they
+// change. Validator checks traffic against the document in development and
+// GoClient writes a Go client for it.
+package openapi
+
+import (
+	"Gator_blog/problem"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version of the OpenAPI specification documents follow
+const Version = "3.1.0"
+
+// Document is an OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	gen *Generator
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Description string `json:"description,omitempty"`
+	In          string `json:"in,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// name of the session token security scheme
+const token = "token"
+
+// New starts the document of an API. Every operation answers failures with
+// the Problem schema, see package problem.
+func New(info Info) *Document {
+	gen := NewGenerator()
+	gen.Name(problem.Document{}, "Problem")
+	gen.Schema(problem.Document{})
+	// extensions such as the current copy of a record may follow
+	gen.Components["Problem"].AdditionalProperties = &Schema{}
+	return &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: gen.Components,
+			SecuritySchemes: map[string]SecurityScheme{
+				token: {Type: "apiKey", In: "header", Name: "Authorization",
+					Description: "The JWT of a session, sent as it is without a scheme"},
+			},
+		},
+		gen: gen,
+	}
+}
+
+// Generator reflects the bodies of the document's operations
+func (d *Document) Generator() *Generator { return d.gen }
+
+// Auth is how an operation takes the session token
+type Auth int
+
+const (
+	AuthNone     Auth = iota
+	AuthRequired      // only with a token
+	AuthOptional      // personalised when a token is sent
+)
+
+// Op describes an operation for Add. Bodies are Go values reflected into
+// schemas: a struct, an Object, a *Schema, a Raw body that is not JSON or
+// nil for none.
+type Op struct {
+	ID          string
+	Summary     string
+	Description string
+	Tag         string
+	Auth        Auth
+	Deprecated  bool
+	// Path overrides the schemas of path parameters. Parameters named id
+	// or ending in Id are integers, the others strings.
+	Path map[string]*Schema
+	// Query and header parameters
+	Params []*Parameter
+	Body   interface{}
+	// BodyMedia are the media types Body is accepted as, JSON by default
+	BodyMedia []string
+	Responses map[int]interface{}
+}
+
+// Query is an optional query parameter
+func Query(name string, schema *Schema, description string) *Parameter {
+	return &Parameter{Name: name, In: "query", Schema: schema, Description: description}
+}
+
+// Header is an optional request header
+func Header(name, description string) *Parameter {
+	return &Parameter{Name: name, In: "header", Schema: String(), Description: description}
+}
+
+var routeParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Add documents the operation served at the Fiber route path, e.g.
+// /api/blogs/:id
+func (d *Document) Add(method, path string, op Op) {
+	o := &Operation{OperationID: op.ID, Summary: op.Summary, Description: op.Description,
+		Deprecated: op.Deprecated, Responses: map[string]*Response{}}
+	if op.Tag != "" {
+		o.Tags = []string{op.Tag}
+	}
+	switch op.Auth {
+	case AuthRequired:
+		o.Security = []map[string][]string{{token: {}}}
+	case AuthOptional:
+		o.Security = []map[string][]string{{}, {token: {}}}
+	}
+
+	for _, match := range routeParam.FindAllStringSubmatch(path, -1) {
+		name := match[1]
+		schema, ok := op.Path[name]
+		if !ok {
+			schema = String()
+			if name == "id" || strings.HasSuffix(name, "Id") {
+				min := int64(1)
+				schema = &Schema{Type: Types{"integer"}, Minimum: &min}
+			}
+		}
+		o.Parameters = append(o.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	o.Parameters = append(o.Parameters, op.Params...)
+
+	if op.Body != nil {
+		o.RequestBody = &RequestBody{Required: true, Content: d.content(op.Body, op.BodyMedia, true)}
+	}
+	for status, body := range op.Responses {
+		response := &Response{Description: http.StatusText(status)}
+		if raw, ok := body.(Raw); ok && raw.Description != "" {
+			response.Description = raw.Description
+		}
+		if body != nil {
+			response.Content = d.content(body, nil, false)
+		}
+		o.Responses[strconv.Itoa(status)] = response
+	}
+	o.Responses["default"] = &Response{Description: "Problem",
+		Content: map[string]MediaType{problem.MIME: {Schema: Ref("Problem")}}}
+
+	key := routeParam.ReplaceAllString(path, "{$1}")
+	if d.Paths[key] == nil {
+		d.Paths[key] = PathItem{}
+	}
+	d.Paths[key][strings.ToLower(method)] = o
+	if op.Tag != "" && !d.hasTag(op.Tag) {
+		d.Tags = append(d.Tags, Tag{Name: op.Tag})
+		sort.Slice(d.Tags, func(i, j int) bool { return d.Tags[i].Name < d.Tags[j].Name })
+	}
+}
+
+func (d *Document) hasTag(name string) bool {
+	for _, tag := range d.Tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// the content of a body by media type
+func (d *Document) content(body interface{}, media []string, input bool) map[string]MediaType {
+	if raw, ok := body.(Raw); ok {
+		content := map[string]MediaType{}
+		for _, m := range raw.Media {
+			content[m] = MediaType{}
+		}
+		return content
+	}
+	if len(media) == 0 {
+		media = []string{"application/json"}
+	}
+	var schema *Schema
+	if input {
+		schema = d.gen.Input(body)
+	} else {
+		schema = d.gen.Schema(body)
+	}
+	content := map[string]MediaType{}
+	for _, m := range media {
+		content[m] = MediaType{Schema: schema}
+	}
+	return content
+}
+
+// Operations lists the operations of the document by method and path
+func (d *Document) Operations() []Route {
+	var routes []Route
+	for path, item := range d.Paths {
+		for method, op := range item {
+			routes = append(routes, Route{Method: strings.ToUpper(method), Path: path, Operation: op})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// Route is an operation with the method and path it is served at
+type Route struct {
+	Method string
+	Path   string // with {name} parameters
+	*Operation
+}
+
+// Resolve follows the $ref of s to its component
+func (d *Document) Resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
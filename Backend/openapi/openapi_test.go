@@ -0,0 +1,172 @@
+package openapi_test
+
+import (
+	"Gator_blog/openapi"
+	"Gator_blog/problem"
+	"Gator_blog/validate"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type note struct {
+	ID       uint       `json:"id"`
+	Text     string     `json:"text"`
+	Labels   []label    `json:"labels,omitempty"`
+	Archived *time.Time `json:"archived_at"`
+	secret   string
+}
+
+type label struct {
+	Name string `json:"name"`
+}
+
+type noteRequest struct {
+	Text   string   `json:"text" validate:"required,max=comment"`
+	Labels []string `json:"labels" validate:"max=2"`
+	Pin    *bool    `json:"pin" validate:"omitempty,required"`
+}
+
+func testDocument() *openapi.Document {
+	doc := openapi.New(openapi.Info{Title: "Notes", Version: "1"})
+	doc.Add("GET", "/notes/:id", openapi.Op{ID: "getNote", Tag: "notes",
+		Params:    []*openapi.Parameter{openapi.Query("format", openapi.Enum("short", "long"), "")},
+		Responses: map[int]interface{}{200: note{}}})
+	doc.Add("POST", "/notes", openapi.Op{ID: "createNote", Tag: "notes", Auth: openapi.AuthRequired,
+		Body: noteRequest{}, Responses: map[int]interface{}{201: openapi.Object{"note": note{}, "msg": ""}}})
+	return doc
+}
+
+func TestSchemas(t *testing.T) {
+	doc := testDocument()
+
+	out := doc.Components.Schemas["Note"]
+	require.NotNil(t, out)
+	assert.ElementsMatch(t, []string{"id", "text", "archived_at"}, out.Required)
+	assert.Equal(t, openapi.Types{"string", "null"}, out.Properties["archived_at"].Type)
+	assert.Equal(t, "date-time", out.Properties["archived_at"].Format)
+	assert.Equal(t, "#/components/schemas/Label", out.Properties["labels"].Items.Ref)
+	assert.NotContains(t, out.Properties, "secret")
+
+	in := doc.Components.Schemas["NoteRequest"]
+	require.NotNil(t, in)
+	assert.Equal(t, []string{"text"}, in.Required)
+	assert.Equal(t, validate.Limits().CommentMax, *in.Properties["text"].MaxLength)
+	assert.Equal(t, 1, *in.Properties["text"].MinLength)
+	assert.Equal(t, 2, *in.Properties["labels"].MaxItems)
+
+	op := doc.Paths["/notes/{id}"]["get"]
+	require.NotNil(t, op)
+	assert.Equal(t, "id", op.Parameters[0].Name)
+	assert.Equal(t, "path", op.Parameters[0].In)
+	assert.True(t, op.Parameters[0].Schema.Type.Has("integer"))
+	assert.Contains(t, op.Responses, "default")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"openapi":"3.1.0"`)
+	assert.Contains(t, string(data), `"type":["string","null"]`)
+}
+
+func TestCheck(t *testing.T) {
+	doc := testDocument()
+	schema := doc.Paths["/notes"]["post"].RequestBody.Content["application/json"].Schema
+
+	var ok interface{}
+	json.Unmarshal([]byte(`{"text":"hi","labels":["a"]}`), &ok)
+	assert.Empty(t, doc.Check(schema, ok))
+
+	var bad interface{}
+	json.Unmarshal([]byte(`{"text":"","labels":["a",2,"c"],"pin":"yes"}`), &bad)
+	rules := map[string]string{}
+	for _, fe := range doc.Check(schema, bad) {
+		rules[fe.Field] = fe.Rule
+	}
+	assert.Equal(t, map[string]string{"text": "minLength", "labels": "maxItems", "labels[1]": "type", "pin": "type"}, rules)
+
+	assert.Equal(t, "required", doc.Check(schema, map[string]interface{}{})[0].Rule)
+}
+
+func TestValidator(t *testing.T) {
+	var reported validate.Errors
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Use(openapi.Validator(testDocument(), func(c *fiber.Ctx, errs validate.Errors) { reported = errs }))
+	app.Get("/notes/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "2" {
+			return c.JSON(fiber.Map{"id": 2, "text": 7, "archived_at": nil})
+		}
+		return c.JSON(note{ID: 1, Text: "hi"})
+	})
+	app.Post("/notes", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"msg": "ok", "note": note{ID: 1, Text: "hi"}})
+	})
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	get := func(url string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, url, nil))
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("valid exchange", func(t *testing.T) {
+		reported = nil
+		assert.Equal(t, http.StatusOK, get("/notes/1?format=short").StatusCode)
+		assert.Empty(t, reported)
+	})
+
+	t.Run("parameters are checked", func(t *testing.T) {
+		resp := get("/notes/abc?format=tiny")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		assert.Equal(t, "validation_failed", body["code"])
+		assert.Len(t, body["errors"], 2)
+	})
+
+	t.Run("bodies are checked", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(`{"labels":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		req = httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(`{"text":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		reported = nil
+		resp, err = app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Empty(t, reported)
+	})
+
+	t.Run("responses departing from the document are reported", func(t *testing.T) {
+		reported = nil
+		assert.Equal(t, http.StatusOK, get("/notes/2").StatusCode)
+		require.Len(t, reported, 1)
+		assert.Equal(t, "text", reported[0].Field)
+	})
+
+	t.Run("undocumented paths pass", func(t *testing.T) {
+		reported = nil
+		assert.Equal(t, http.StatusOK, get("/health").StatusCode)
+		assert.Empty(t, reported)
+	})
+}
+
+func TestGoClient(t *testing.T) {
+	src, err := testDocument().GoClient("notes")
+	require.NoError(t, err)
+	code := string(src)
+	assert.Contains(t, code, "package notes")
+	assert.Contains(t, code, "type Note struct")
+	assert.Contains(t, code, "ArchivedAt *time.Time `json:\"archived_at\"`")
+	assert.Contains(t, code, "func (c *Client) GetNote(ctx context.Context, id int64, params *GetNoteParams, opts ...RequestOption) (*Note, error)")
+	assert.Contains(t, code, "func (c *Client) CreateNote(ctx context.Context, body NoteRequest, opts ...RequestOption) (*CreateNoteResponse, error)")
+}
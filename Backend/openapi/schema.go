@@ -0,0 +1,382 @@
+package openapi
+
+import (
+	"Gator_blog/validate"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is a JSON Schema, in the 2020-12 dialect OpenAPI 3.1 uses
+type Schema struct {
+	Ref         string        `json:"$ref,omitempty"`
+	Type        Types         `json:"type,omitempty"`
+	Format      string        `json:"format,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	AnyOf       []*Schema     `json:"anyOf,omitempty"`
+
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Required   []string           `json:"required,omitempty"`
+	// AdditionalProperties is the schema of members not in Properties, or
+	// false when there may be none
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	MinLength        *int   `json:"minLength,omitempty"`
+	MaxLength        *int   `json:"maxLength,omitempty"`
+	Pattern          string `json:"pattern,omitempty"`
+	ContentMediaType string `json:"contentMediaType,omitempty"`
+
+	Minimum *int64 `json:"minimum,omitempty"`
+	Maximum *int64 `json:"maximum,omitempty"`
+}
+
+// Types are the JSON types a value may have, written as a single name when
+// there is one
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Has reports whether name is one of the types
+func (t Types) Has(name string) bool {
+	for _, typ := range t {
+		if typ == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Object describes a JSON object by example: each member is reflected from
+// its value, and is always sent unless wrapped in Optional
+type Object map[string]interface{}
+
+// Optional marks a member of an Object that may be left out
+type Optional struct{ Value interface{} }
+
+// Raw is a body that is not JSON, sent as one of Media
+type Raw struct {
+	Media       []string
+	Description string
+}
+
+// schema helpers for values reflection cannot describe
+func String() *Schema  { return &Schema{Type: Types{"string"}} }
+func Integer() *Schema { return &Schema{Type: Types{"integer"}} }
+
+// Enum is a string that is one of values
+func Enum(values ...string) *Schema {
+	s := String()
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// AnyOf is a value matching at least one of schemas
+func AnyOf(schemas ...*Schema) *Schema { return &Schema{AnyOf: schemas} }
+
+// Ref points at the component schema name
+func Ref(name string) *Schema { return &Schema{Ref: "#/components/schemas/" + name} }
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generator reflects Go types into schemas. Named struct types become
+// components, referred to with $ref wherever they appear.
+type Generator struct {
+	Components map[string]*Schema
+	names      map[reflect.Type]string
+	// request body schemas of types whose JSON form reflection cannot see,
+	// see Define
+	inputs map[reflect.Type]*Schema
+}
+
+func NewGenerator() *Generator {
+	return &Generator{Components: map[string]*Schema{}, names: map[reflect.Type]string{}, inputs: map[reflect.Type]*Schema{}}
+}
+
+// Name makes the type of v the component name
+func (g *Generator) Name(v interface{}, name string) {
+	g.names[reflect.TypeOf(v)] = name
+}
+
+// Define replaces the reflected schema of the type of v in request bodies,
+// for types that unmarshal more forms than they marshal
+func (g *Generator) Define(v interface{}, input *Schema) {
+	g.inputs[reflect.TypeOf(v)] = input
+}
+
+// Schema is the schema of v as a response sends it
+func (g *Generator) Schema(v interface{}) *Schema {
+	return g.value(v, false)
+}
+
+// Input is the schema of v as a request body. Only the fields its validate
+// tags require must be sent, and the rules become constraints.
+func (g *Generator) Input(v interface{}) *Schema {
+	return g.value(v, true)
+}
+
+func (g *Generator) value(v interface{}, input bool) *Schema {
+	switch v := v.(type) {
+	case *Schema:
+		return v
+	case Object:
+		return g.object(v, input)
+	}
+	return g.reflect(reflect.TypeOf(v), input)
+}
+
+func (g *Generator) object(o Object, input bool) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	for name, value := range o {
+		if opt, ok := value.(Optional); ok {
+			s.Properties[name] = g.value(opt.Value, input)
+			continue
+		}
+		s.Properties[name] = g.value(value, input)
+		s.Required = append(s.Required, name)
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *Generator) reflect(t reflect.Type, input bool) *Schema {
+	if s, ok := g.inputs[t]; ok && input {
+		return s
+	}
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+	if t == reflect.TypeOf(json.RawMessage{}) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := int64(0)
+		return &Schema{Type: Types{"integer"}, Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return String()
+	case reflect.Ptr:
+		return nullable(g.reflect(t.Elem(), input))
+	case reflect.Slice:
+		// nil slices are sent as null
+		return &Schema{Type: Types{"array", "null"}, Items: g.reflect(t.Elem(), input)}
+	case reflect.Array:
+		return &Schema{Type: Types{"array"}, Items: g.reflect(t.Elem(), input)}
+	case reflect.Map:
+		return &Schema{Type: Types{"object", "null"}, AdditionalProperties: g.reflect(t.Elem(), input)}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, input)
+		}
+		return g.component(t, input)
+	}
+	panic(fmt.Sprintf("openapi: cannot describe %s", t))
+}
+
+// refers to the component of the named struct t, describing it the first
+// time it is seen
+func (g *Generator) component(t reflect.Type, input bool) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = exported(t.Name())
+		for taken := range g.names {
+			if g.names[taken] == name {
+				name = exported(t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]) + name
+				break
+			}
+		}
+		g.names[t] = name
+	}
+	if _, described := g.Components[name]; !described {
+		// claimed before describing so recursive types end
+		g.Components[name] = &Schema{}
+		*g.Components[name] = *g.structSchema(t, input)
+	}
+	return Ref(name)
+}
+
+func (g *Generator) structSchema(t reflect.Type, input bool) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+	var rules map[string][]validate.Rule
+	if input {
+		rules = validate.Rules(t)
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, omitempty, skip := jsonName(sf)
+		if skip {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			inner := g.structSchema(embedded, input)
+			for prop, schema := range inner.Properties {
+				s.Properties[prop] = schema
+			}
+			s.Required = append(s.Required, inner.Required...)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		prop := g.reflect(sf.Type, input)
+		if input {
+			prop = constrain(prop, sf.Type, rules[name])
+			if requires(rules[name]) {
+				s.Required = append(s.Required, name)
+			}
+		} else if !omitempty {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// the JSON member name of a field as encoding/json reads it, empty for the
+// Go name, and whether the field is left out
+func jsonName(sf reflect.StructField) (name string, omitempty, skip bool) {
+	if !sf.IsExported() && !sf.Anonymous {
+		return "", false, true
+	}
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+// whether a field with rules must be sent
+func requires(rules []validate.Rule) bool {
+	required := false
+	for _, r := range rules {
+		switch r.Name {
+		case "omitempty":
+			return false
+		case "required":
+			required = true
+		}
+	}
+	return required
+}
+
+// adds the validate rules of a field of type t to its schema
+func constrain(s *Schema, t reflect.Type, rules []validate.Rule) *Schema {
+	if len(rules) == 0 {
+		return s
+	}
+	out := *s
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	kind := t.Kind()
+	for _, r := range rules {
+		limit := r.Limit
+		switch r.Name {
+		case "required":
+			// present and not blank, empty or zero
+			one := 1
+			switch kind {
+			case reflect.String:
+				out.MinLength = &one
+			case reflect.Slice, reflect.Map:
+				out.MinItems = &one
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				min := int64(1)
+				out.Minimum = &min
+			}
+		case "min", "max":
+			switch kind {
+			case reflect.String:
+				if r.Name == "min" {
+					out.MinLength = &limit
+				} else {
+					out.MaxLength = &limit
+				}
+			case reflect.Slice, reflect.Map:
+				if r.Name == "min" {
+					out.MinItems = &limit
+				} else {
+					out.MaxItems = &limit
+				}
+			default:
+				bound := int64(limit)
+				if r.Name == "min" {
+					out.Minimum = &bound
+				} else {
+					out.Maximum = &bound
+				}
+			}
+		case "email":
+			out.Format = "email"
+		case "username":
+			out.Pattern = validate.Limits().UsernamePattern
+		case "tag":
+			out.Pattern = validate.TagPattern
+		case "password":
+			out.Description = fmt.Sprintf("must mix at least %d of lower case letters, upper case letters, digits and symbols",
+				validate.Limits().PasswordClasses)
+		}
+	}
+	return &out
+}
+
+// s or null
+func nullable(s *Schema) *Schema {
+	switch {
+	case s.Ref != "" || len(s.AnyOf) > 0:
+		return AnyOf(s, &Schema{Type: Types{"null"}})
+	case len(s.Type) == 0 || s.Type.Has("null"):
+		return s // already allows null
+	}
+	out := *s
+	out.Type = append(append(Types{}, s.Type...), "null")
+	return &out
+}
+
+func exported(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
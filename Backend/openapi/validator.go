@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"Gator_blog/problem"
+	"Gator_blog/validate"
+	"bytes"
+	"encoding/json"
+	"log"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validator checks requests and responses against the operations of doc.
+// Requests with parameters or a JSON body that break their schemas are
+// refused with the broken rules, as validate.Struct would. Responses are
+// passed on as they are and report is called with the ways they depart
+// from doc; nil logs them. Requests for paths doc does not describe pass
+// through unchecked. Meant for development, it decodes every JSON body
+// twice.
+func Validator(doc *Document, report func(c *fiber.Ctx, errs validate.Errors)) fiber.Handler {
+	if report == nil {
+		report = func(c *fiber.Ctx, errs validate.Errors) {
+			log.Printf("openapi: %s %s answered %d, which the specification does not allow: %v",
+				c.Method(), c.OriginalURL(), c.Response().StatusCode(), errs)
+		}
+	}
+	routes := doc.Operations()
+
+	return func(c *fiber.Ctx) error {
+		route, params, ok := match(routes, c.Method(), c.Path())
+		if !ok {
+			return c.Next()
+		}
+		if errs := doc.checkRequest(c, route, params); len(errs) > 0 {
+			return problem.Invalid(errs)
+		}
+		if err := c.Next(); err != nil {
+			// answered as a problem by the error handler
+			return err
+		}
+		if errs := doc.checkResponse(c, route); len(errs) > 0 {
+			report(c, errs)
+		}
+		return nil
+	}
+}
+
+// the route serving method and path, with the values of its path
+// parameters. Like Fiber, paths match without regard to case or a
+// trailing slash.
+func match(routes []Route, method, path string) (Route, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range routes {
+		if route.Method != method {
+			continue
+		}
+		pattern := strings.Split(strings.Trim(route.Path, "/"), "/")
+		if len(pattern) != len(segments) {
+			continue
+		}
+		params := map[string]string{}
+		matched := true
+		for i, part := range pattern {
+			if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+				params[part[1:len(part)-1]] = segments[i]
+			} else if !strings.EqualFold(part, segments[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route, params, true
+		}
+	}
+	return Route{}, nil, false
+}
+
+func (d *Document) checkRequest(c *fiber.Ctx, route Route, params map[string]string) validate.Errors {
+	var errs validate.Errors
+	for _, p := range route.Parameters {
+		var raw string
+		var present bool
+		switch p.In {
+		case "path":
+			raw, present = params[p.Name], true
+		case "query":
+			raw = c.Query(p.Name)
+			present = raw != ""
+		default:
+			continue
+		}
+		if !present {
+			continue
+		}
+		d.check(p.Schema, parameterValue(p.Schema, raw), p.Name, &errs)
+	}
+
+	if route.RequestBody == nil {
+		return errs
+	}
+	media, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	content, ok := route.RequestBody.Content[media]
+	if !ok || content.Schema == nil || !strings.HasSuffix(media, "json") {
+		// refused or read by the handler
+		return errs
+	}
+	body, ok := decode(c.Body())
+	if !ok {
+		// the handler answers unreadable bodies itself
+		return errs
+	}
+	return append(errs, d.Check(content.Schema, body)...)
+}
+
+func (d *Document) checkResponse(c *fiber.Ctx, route Route) validate.Errors {
+	status := c.Response().StatusCode()
+	if status == fiber.StatusNotModified {
+		return nil
+	}
+	response, ok := route.Responses[strconv.Itoa(status)]
+	if !ok {
+		response = route.Responses["default"]
+		if status < 400 {
+			return validate.Errors{{Field: "status", Rule: "responses",
+				Message: "status " + strconv.Itoa(status) + " is not documented"}}
+		}
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+	media, _, _ := mime.ParseMediaType(string(c.Response().Header.ContentType()))
+	content, ok := response.Content[media]
+	if !ok {
+		return validate.Errors{{Field: "Content-Type", Rule: "content",
+			Message: "Content-Type " + media + " is not documented"}}
+	}
+	if content.Schema == nil {
+		return nil
+	}
+	body, ok := decode(c.Response().Body())
+	if !ok {
+		return validate.Errors{{Field: "body", Rule: "type", Message: "body is not JSON"}}
+	}
+	return d.Check(content.Schema, body)
+}
+
+// a path or query parameter as the JSON value its schema describes
+func parameterValue(s *Schema, raw string) interface{} {
+	if s.Type.Has("integer") {
+		if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return json.Number(raw)
+		}
+	}
+	return raw
+}
+
+func decode(data []byte) (interface{}, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
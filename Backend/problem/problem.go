@@ -0,0 +1,204 @@
+// Package problem describes failed requests. Handlers return a *Problem
+// and Handler, the Fiber error handler, sends it as an RFC 7807 problem
+// details document:
+//
+//	HTTP/1.1 404 Not Found
+//	Content-Type: application/problem+json
+//
+//	{"type": "about:blank", "title": "Not Found", "status": 404,
+//	 "detail": "Blog not found", "instance": "/api/blogs/7", "code": "not_found"}
+//
+// code tells failures with the same status apart, clients should branch on
+// it rather than on detail, which is meant for people.
+package problem
+
+import (
+	"Gator_blog/validate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIME is the media type of problem documents
+const MIME = "application/problem+json"
+
+// Code identifies the kind of a failure
+type Code string
+
+const (
+	InvalidInput         Code = "invalid_input"     // the body or a parameter could not be read
+	ValidationFailed     Code = "validation_failed" // fields break their rules, see Problem.Errors
+	Unauthenticated      Code = "unauthenticated"   // no valid session token
+	IncorrectPassword    Code = "incorrect_password"
+	InvalidCode          Code = "invalid_code" // a wrong or expired password reset code
+	Forbidden            Code = "forbidden"
+	NotFound             Code = "not_found"
+	UserNotFound         Code = "user_not_found"
+	EmailTaken           Code = "email_taken"
+	UsernameTaken        Code = "username_taken"
+	Conflict             Code = "conflict"
+	VersionMismatch      Code = "version_mismatch" // If-Match named an older version
+	PayloadTooLarge      Code = "payload_too_large"
+	UnsupportedMediaType Code = "unsupported_media_type"
+	MethodNotAllowed     Code = "method_not_allowed"
+	Internal             Code = "internal"
+	Unavailable          Code = "unavailable"
+)
+
+// codes of the failures Fiber reports itself, such as unknown routes and
+// bodies over the limit
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            InvalidInput,
+	http.StatusUnauthorized:          Unauthenticated,
+	http.StatusForbidden:             Forbidden,
+	http.StatusNotFound:              NotFound,
+	http.StatusMethodNotAllowed:      MethodNotAllowed,
+	http.StatusConflict:              Conflict,
+	http.StatusPreconditionFailed:    VersionMismatch,
+	http.StatusRequestEntityTooLarge: PayloadTooLarge,
+	http.StatusUnsupportedMediaType:  UnsupportedMediaType,
+	http.StatusServiceUnavailable:    Unavailable,
+}
+
+// Problem is a failed request
+type Problem struct {
+	Status int
+	Code   Code
+	Detail string
+	// Errors lists the fields that break their rules
+	Errors []validate.FieldError
+	// Extensions are sent as further members, e.g. the current copy of a
+	// record an update could not be applied to
+	Extensions map[string]interface{}
+	// Err is the cause, logged for server errors and never sent
+	Err error
+}
+
+// New describes a failure with the HTTP status it is answered with
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{Status: status, Code: code, Detail: detail}
+}
+
+// BadRequest is a body or parameter that could not be read
+func BadRequest(detail string) *Problem {
+	return New(http.StatusBadRequest, InvalidInput, detail)
+}
+
+// Invalid lists the broken rules of the validate.Errors in err
+func Invalid(err error) *Problem {
+	p := New(http.StatusBadRequest, ValidationFailed, "Invalid input")
+	var errs validate.Errors
+	if errors.As(err, &errs) {
+		p.Errors = errs
+	}
+	return p.Wrap(err)
+}
+
+// Unauthorized is a request without a valid session
+func Unauthorized(detail string) *Problem {
+	return New(http.StatusUnauthorized, Unauthenticated, detail)
+}
+
+// Forbid is a request the signed in user may not make
+func Forbid(detail string) *Problem {
+	return New(http.StatusForbidden, Forbidden, detail)
+}
+
+// Missing is a record that does not exist or the user may not see
+func Missing(detail string) *Problem {
+	return New(http.StatusNotFound, NotFound, detail)
+}
+
+// Failed is a server side failure, err is logged and detail sent
+func Failed(detail string, err error) *Problem {
+	return New(http.StatusInternalServerError, Internal, detail).Wrap(err)
+}
+
+// With adds the extension member key
+func (p *Problem) With(key string, value interface{}) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = map[string]interface{}{}
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// Wrap records err as the cause of p
+func (p *Problem) Wrap(err error) *Problem {
+	p.Err = err
+	return p
+}
+
+func (p *Problem) Error() string {
+	if p.Err != nil {
+		return fmt.Sprintf("%d %s: %s: %v", p.Status, p.Code, p.Detail, p.Err)
+	}
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Code, p.Detail)
+}
+
+func (p *Problem) Unwrap() error { return p.Err }
+
+// Document is the body sent for a problem, extensions aside
+type Document struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     Code                  `json:"code"`
+	Errors   []validate.FieldError `json:"errors,omitempty"`
+}
+
+// renders p as the problem of a request for instance
+func (p *Problem) document(instance string) ([]byte, error) {
+	doc, err := json.Marshal(Document{Type: "about:blank", Title: http.StatusText(p.Status), Status: p.Status,
+		Detail: p.Detail, Instance: instance, Code: p.Code, Errors: p.Errors})
+	if err != nil || len(p.Extensions) == 0 {
+		return doc, err
+	}
+	members := map[string]json.RawMessage{}
+	json.Unmarshal(doc, &members)
+	for key, value := range p.Extensions {
+		if _, taken := members[key]; taken {
+			continue
+		}
+		if members[key], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(members)
+}
+
+// Handler is the Fiber error handler. It answers a *Problem as it is, the
+// errors Fiber raises itself with their status and anything else as an
+// internal error.
+func Handler(c *fiber.Ctx, err error) error {
+	var p *Problem
+	var fe *fiber.Error
+	switch {
+	case errors.As(err, &p):
+	case errors.As(err, &fe):
+		code, ok := statusCodes[fe.Code]
+		if !ok {
+			code = Internal
+		}
+		p = New(fe.Code, code, fe.Message)
+	default:
+		p = Failed("Internal server error", err)
+	}
+	if p.Status >= 500 {
+		log.Println("Error serving", c.Method(), c.OriginalURL()+":", p)
+	}
+
+	body, err := p.document(c.OriginalURL())
+	if err != nil {
+		log.Println("Error encoding problem:", err)
+		return c.Status(http.StatusInternalServerError).SendString(http.StatusText(http.StatusInternalServerError))
+	}
+	c.Set(fiber.HeaderContentType, MIME)
+	return c.Status(p.Status).Send(body)
+}
@@ -0,0 +1,87 @@
+package problem_test
+
+import (
+	"Gator_blog/problem"
+	"Gator_blog/validate"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// answers GET /fail with whatever fail returns
+func call(t *testing.T, fail func(c *fiber.Ctx) error, path string) (*http.Response, map[string]interface{}) {
+	app := fiber.New(fiber.Config{ErrorHandler: problem.Handler})
+	app.Get("/fail", fail)
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	assert.NoError(t, err)
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func TestHandler(t *testing.T) {
+	t.Run("problem", func(t *testing.T) {
+		resp, body := call(t, func(c *fiber.Ctx) error {
+			return problem.Missing("Blog not found")
+		}, "/fail?id=7")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, problem.MIME, resp.Header.Get("Content-Type"))
+		assert.Equal(t, map[string]interface{}{
+			"type": "about:blank", "title": "Not Found", "status": float64(404),
+			"detail": "Blog not found", "instance": "/fail?id=7", "code": "not_found",
+		}, body)
+	})
+
+	t.Run("validation errors and extensions", func(t *testing.T) {
+		resp, body := call(t, func(c *fiber.Ctx) error {
+			err := validate.Struct(struct {
+				Title string `json:"title" validate:"required"`
+			}{})
+			return problem.Invalid(err).With("hint", "see errors").With("status", 200)
+		}, "/fail")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "validation_failed", body["code"])
+		assert.Equal(t, "see errors", body["hint"])
+		// extensions never replace the standard members
+		assert.Equal(t, float64(400), body["status"])
+		errs := body["errors"].([]interface{})
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "title", errs[0].(map[string]interface{})["field"])
+	})
+
+	t.Run("fiber errors keep their status", func(t *testing.T) {
+		resp, body := call(t, func(c *fiber.Ctx) error { return nil }, "/missing")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, problem.MIME, resp.Header.Get("Content-Type"))
+		assert.Equal(t, "not_found", body["code"])
+
+		resp, body = call(t, func(c *fiber.Ctx) error { return fiber.ErrRequestEntityTooLarge }, "/fail")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Equal(t, "payload_too_large", body["code"])
+	})
+
+	t.Run("other errors are internal and not leaked", func(t *testing.T) {
+		resp, body := call(t, func(c *fiber.Ctx) error {
+			return errors.New("dial tcp: connection refused")
+		}, "/fail")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "internal", body["code"])
+		assert.Equal(t, "Internal server error", body["detail"])
+	})
+}
+
+func TestProblemWrapsCause(t *testing.T) {
+	cause := errors.New("disk full")
+	p := problem.Failed("Could not save", cause)
+	assert.ErrorIs(t, p, cause)
+	assert.Equal(t, "500 internal: Could not save: disk full", p.Error())
+
+	var target *problem.Problem
+	assert.True(t, errors.As(errors.Join(errors.New("context"), p), &target))
+	assert.Equal(t, http.StatusInternalServerError, target.Status)
+}
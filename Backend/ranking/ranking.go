@@ -0,0 +1,227 @@
+package ranking
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/redis"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Window limits a leaderboard to posts published within a period of time
+type Window string
+
+const (
+	Day   Window = "day"
+	Week  Window = "week"
+	Month Window = "month"
+	All   Window = "all"
+)
+
+// Windows lists every window a leaderboard is kept for
+var Windows = []Window{Day, Week, Month, All}
+
+var ErrUnknownWindow = errors.New("unknown ranking window")
+
+// weights of each kind of engagement in the hot score
+const (
+	likeWeight    = 3.0
+	commentWeight = 2.0
+	viewWeight    = 0.1
+	// how quickly a post loses its score as it ages
+	gravity = 1.5
+)
+
+// number of posts kept in each precomputed leaderboard
+const leaderboardSize = 1000
+
+// ParseWindow converts a query value into a Window, empty means All
+func ParseWindow(s string) (Window, error) {
+	if s == "" {
+		return All, nil
+	}
+	for _, w := range Windows {
+		if Window(s) == w {
+			return w, nil
+		}
+	}
+	return "", ErrUnknownWindow
+}
+
+// Since returns the earliest publication time included in the window, the
+// zero time for All
+func (w Window) Since(now time.Time) time.Time {
+	switch w {
+	case Day:
+		return now.AddDate(0, 0, -1)
+	case Week:
+		return now.AddDate(0, 0, -7)
+	case Month:
+		return now.AddDate(0, -1, 0)
+	}
+	return time.Time{}
+}
+
+func leaderboardKey(w Window) string {
+	return "leaderboard:hot:" + string(w)
+}
+
+// HotScore weighs likes, comments and views of a post and decays the total
+// with the age of the post, so recent engagement outranks old popularity
+func HotScore(likes, comments, views int64, publishedAt, now time.Time) float64 {
+	points := likeWeight*float64(likes) + commentWeight*float64(comments) + viewWeight*float64(views)
+	age := now.Sub(publishedAt).Hours()
+	if age < 0 {
+		age = 0
+	}
+	return points / math.Pow(age+2, gravity)
+}
+
+// Ranker ranks the posts of a database, keeping the leaderboards in a
+// Redis store
+type Ranker struct {
+	db    *gorm.DB
+	store *redis.Store
+}
+
+func NewRanker(db *gorm.DB, store *redis.Store) *Ranker {
+	return &Ranker{db: db, store: store}
+}
+
+// Top returns the ids of the n hottest posts in the window, best first. It
+// reads the precomputed leaderboard and computes it on the spot when missing.
+// Without Redis it ranks by the counters written back to the blogs.
+func (r *Ranker) Top(w Window, n int) ([]uint, error) {
+	ids, found, err := r.store.LeaderboardTop(leaderboardKey(w), int64(n))
+	if err != nil {
+		log.Println("Redis error: ", err)
+		return r.topByCounters(w, n, time.Now())
+	}
+	if found {
+		return ids, nil
+	}
+
+	scores, err := r.Scores(w, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := r.store.StoreLeaderboard(leaderboardKey(w), scores); err != nil {
+		log.Println("Error storing leaderboard", err)
+	}
+	ids = rank(scores)
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids, nil
+}
+
+// ranks the posts of the window by the engagement counters stored with them,
+// which lag Redis by a flush. The database picks the leaderboardSize posts
+// with the most points, their age is weighed in here.
+func (r *Ranker) topByCounters(w Window, n int, now time.Time) ([]uint, error) {
+	var blogs []model.Blog
+	query := r.db.Select("id", "created_at", "likes_count", "comments_count", "views_count").
+		Order(fmt.Sprintf("likes_count * %g + comments_count * %g + views_count * %g DESC", likeWeight, commentWeight, viewWeight)).
+		Order("id DESC").
+		Limit(leaderboardSize)
+	if since := w.Since(now); !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	if err := query.Find(&blogs).Error; err != nil {
+		return nil, err
+	}
+	scores := make(map[uint]float64, len(blogs))
+	for _, blog := range blogs {
+		scores[blog.ID] = HotScore(blog.LikesCount, blog.CommentsCount, blog.ViewsCount, blog.CreatedAt, now)
+	}
+	ids := rank(scores)
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids, nil
+}
+
+// Refresh recomputes and stores the leaderboard of every window
+func (r *Ranker) Refresh() error {
+	now := time.Now()
+	for _, w := range Windows {
+		scores, err := r.Scores(w, now)
+		if err != nil {
+			return err
+		}
+		if err := r.store.StoreLeaderboard(leaderboardKey(w), scores); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Scores computes the hot score of the best posts published within the
+// window. The database counts their likes and comments and picks the
+// leaderboardSize posts with the most points, their age and the views not
+// yet written back from Redis are weighed in here.
+func (r *Ranker) Scores(w Window, now time.Time) (map[uint]float64, error) {
+	type row struct {
+		ID         uint
+		CreatedAt  time.Time
+		ViewsCount int64
+		Likes      int64
+		Comments   int64
+	}
+	likes := r.db.Model(&model.Like{}).Select("blog_id, COUNT(*) AS total").Group("blog_id")
+	comments := r.db.Model(&model.Comment{}).Select("blog_id, COUNT(*) AS total").Group("blog_id")
+	query := r.db.Model(&model.Blog{}).
+		Select("blogs.id, blogs.created_at, blogs.views_count, COALESCE(l.total, 0) AS likes, COALESCE(c.total, 0) AS comments").
+		Joins("LEFT JOIN (?) AS l ON l.blog_id = blogs.id", likes).
+		Joins("LEFT JOIN (?) AS c ON c.blog_id = blogs.id", comments).
+		Order(fmt.Sprintf("COALESCE(l.total, 0) * %g + COALESCE(c.total, 0) * %g + blogs.views_count * %g DESC", likeWeight, commentWeight, viewWeight)).
+		Order("blogs.id DESC").
+		Limit(leaderboardSize)
+	if since := w.Since(now); !since.IsZero() {
+		query = query.Where("blogs.created_at >= ?", since)
+	}
+	var rows []row
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	// views live in Redis until they are written back
+	views, err := r.store.GetCounters(ids, redis.CounterViews)
+	if err != nil {
+		views = map[uint]int64{}
+	}
+
+	scores := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		v, ok := views[row.ID]
+		if !ok {
+			v = row.ViewsCount
+		}
+		scores[row.ID] = HotScore(row.Likes, row.Comments, v, row.CreatedAt, now)
+	}
+	return scores, nil
+}
+
+// orders ids by descending score, newer ids first on ties
+func rank(scores map[uint]float64) []uint {
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] > ids[j]
+	})
+	return ids
+}
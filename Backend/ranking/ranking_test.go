@@ -0,0 +1,153 @@
+package ranking_test
+
+import (
+	"Gator_blog/database/dbtest"
+	"Gator_blog/model"
+	"Gator_blog/ranking"
+	"Gator_blog/redis"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// a private database and Redis with a ranker on both
+func setup(t *testing.T) (*gorm.DB, *miniredis.Miniredis, *ranking.Ranker) {
+	db := dbtest.Open(t, &model.Blog{}, &model.Comment{}, &model.Like{})
+
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return db, mr, ranking.NewRanker(db, redis.NewStore(client))
+}
+
+// creates a blog published age ago with the given number of likes
+func createBlog(t *testing.T, db *gorm.DB, age time.Duration, likes int) uint {
+	blog := model.Blog{Title: "Blog", Post: "content", UserName: "author", CreatedAt: time.Now().Add(-age)}
+	db.Create(&blog)
+	for i := 0; i < likes; i++ {
+		db.Create(&model.Like{UserID: uint(i + 1), BlogID: blog.ID})
+	}
+	return blog.ID
+}
+
+func TestParseWindow(t *testing.T) {
+	w, err := ranking.ParseWindow("")
+	assert.NoError(t, err)
+	assert.Equal(t, ranking.All, w)
+
+	w, err = ranking.ParseWindow("week")
+	assert.NoError(t, err)
+	assert.Equal(t, ranking.Week, w)
+
+	_, err = ranking.ParseWindow("year")
+	assert.Equal(t, ranking.ErrUnknownWindow, err)
+}
+
+func TestHotScoreDecaysWithAge(t *testing.T) {
+	now := time.Now()
+	fresh := ranking.HotScore(10, 0, 0, now.Add(-time.Hour), now)
+	old := ranking.HotScore(10, 0, 0, now.Add(-30*24*time.Hour), now)
+	assert.Greater(t, fresh, old)
+
+	// a fresh post with little engagement beats an old post with a lot
+	assert.Greater(t, ranking.HotScore(5, 1, 20, now.Add(-2*time.Hour), now), ranking.HotScore(100, 0, 0, now.AddDate(-1, 0, 0), now))
+
+	// comments and views count too
+	assert.Greater(t, ranking.HotScore(1, 1, 1, now, now), ranking.HotScore(1, 0, 0, now, now))
+}
+
+func TestTopRanksRecentEngagementFirst(t *testing.T) {
+	db, _, ranker := setup(t)
+	old := createBlog(t, db, 90*24*time.Hour, 20)
+	recent := createBlog(t, db, 3*time.Hour, 3)
+	quiet := createBlog(t, db, time.Hour, 0)
+
+	ids, err := ranker.Top(ranking.All, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{recent, old, quiet}, ids)
+
+	ids, err = ranker.Top(ranking.Week, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{recent, quiet}, ids)
+
+	ids, err = ranker.Top(ranking.All, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{recent}, ids)
+}
+
+func TestTopReadsStoredLeaderboard(t *testing.T) {
+	db, mr, ranker := setup(t)
+	first := createBlog(t, db, time.Hour, 1)
+
+	assert.NoError(t, ranker.Refresh())
+	assert.True(t, mr.Exists("leaderboard:hot:day"))
+
+	// posts published after the refresh only show up after the next one
+	second := createBlog(t, db, time.Minute, 5)
+	ids, _ := ranker.Top(ranking.Day, 5)
+	assert.Equal(t, []uint{first}, ids)
+
+	assert.NoError(t, ranker.Refresh())
+	ids, _ = ranker.Top(ranking.Day, 5)
+	assert.Equal(t, []uint{second, first}, ids)
+}
+
+func TestTopWithoutRedis(t *testing.T) {
+	db, _, _ := setup(t)
+	ranker := ranking.NewRanker(db, redis.NewStore(nil))
+	blog := createBlog(t, db, time.Hour, 1)
+
+	ids, err := ranker.Top(ranking.Month, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{blog}, ids)
+}
+
+func TestTopFallsBackToCounters(t *testing.T) {
+	db, mr, ranker := setup(t)
+	old := model.Blog{Title: "Old", UserName: "author", CreatedAt: time.Now().AddDate(0, 0, -90), LikesCount: 20}
+	recent := model.Blog{Title: "Recent", UserName: "author", CreatedAt: time.Now().Add(-3 * time.Hour), LikesCount: 3, ViewsCount: 10}
+	quiet := model.Blog{Title: "Quiet", UserName: "author", CreatedAt: time.Now().Add(-time.Hour)}
+	for _, blog := range []*model.Blog{&old, &recent, &quiet} {
+		db.Create(blog)
+	}
+	mr.Close()
+
+	ids, err := ranker.Top(ranking.All, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{recent.ID, old.ID, quiet.ID}, ids)
+
+	ids, err = ranker.Top(ranking.Week, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{recent.ID}, ids)
+}
+
+// Test that the database picks the posts with the most engagement and only
+// those are scored and stored
+func TestScoresKeepTheBestPosts(t *testing.T) {
+	db, mr, ranker := setup(t)
+	liked := createBlog(t, db, 2*time.Hour, 2)
+	commented := createBlog(t, db, 2*time.Hour, 0)
+	db.Create(&model.Comment{Content: "Nice", UserID: 1, BlogID: commented})
+	quiet := make([]model.Blog, 1000)
+	for i := range quiet {
+		quiet[i] = model.Blog{Title: "Quiet", Post: "content", UserName: "author", CreatedAt: time.Now().Add(-time.Hour)}
+	}
+	assert.NoError(t, db.CreateInBatches(quiet, 100).Error)
+
+	scores, err := ranker.Scores(ranking.Day, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, scores, 1000)
+	assert.Contains(t, scores, liked)
+	assert.Contains(t, scores, commented)
+	assert.Greater(t, scores[liked], scores[commented])
+	assert.NotContains(t, scores, quiet[0].ID, "the oldest quiet post is left out on ties")
+
+	assert.NoError(t, ranker.Refresh())
+	members, err := mr.ZMembers("leaderboard:hot:day")
+	assert.NoError(t, err)
+	assert.Len(t, members, 1000)
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrCircuitOpen is returned instead of calling Redis while it is considered
+// down. Callers treat it like any other Redis failure and use the database.
+var ErrCircuitOpen = errors.New("redis: circuit open")
+
+// CircuitBreaker stops calling Redis after Threshold failures in a row and
+// lets a single call through every Cooldown to find out whether it is back.
+// It is installed on a client as a hook, so every command goes through it.
+type CircuitBreaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{Threshold: threshold, Cooldown: cooldown}
+}
+
+// Open reports whether Redis is considered down
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.Threshold
+}
+
+// Allow returns ErrCircuitOpen when the call must not reach Redis
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.Threshold {
+		return nil
+	}
+	if b.probing || time.Since(b.openedAt) < b.Cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// Record counts the outcome of a call that Allow let through. Replies Redis
+// sent, misses and errors included, show it is up.
+func (b *CircuitBreaker) Record(err error) {
+	if errors.Is(err, ErrCircuitOpen) {
+		return
+	}
+	var reply redis.Error
+	up := err == nil || err == redis.Nil || errors.As(err, &reply)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if up {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.Threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *CircuitBreaker) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return ctx, b.Allow()
+}
+
+func (b *CircuitBreaker) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	b.Record(cmd.Err())
+	return nil
+}
+
+func (b *CircuitBreaker) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, b.Allow()
+}
+
+// a pipeline fails as a whole, its first error tells whether Redis answered
+func (b *CircuitBreaker) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if err = cmd.Err(); err != nil {
+			break
+		}
+	}
+	b.Record(err)
+	return nil
+}
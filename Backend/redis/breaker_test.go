@@ -0,0 +1,62 @@
+package redis_test
+
+import (
+	"Gator_blog/redis"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test that the breaker opens after repeated failures, fails fast while
+// open and closes again once a probe reaches Redis
+func TestCircuitBreaker(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	breaker := redis.NewCircuitBreaker(2, 50*time.Millisecond)
+	client.AddHook(breaker)
+	ctx := redis.Ctx
+
+	// misses are answers, they keep the circuit closed
+	assert.Equal(t, goredis.Nil, client.Get(ctx, "missing").Err())
+	assert.False(t, breaker.Open())
+
+	addr := mr.Addr()
+	mr.Close()
+	for i := 0; i < 2; i++ {
+		assert.Error(t, client.Get(ctx, "key").Err())
+	}
+	assert.True(t, breaker.Open())
+	assert.ErrorIs(t, client.Get(ctx, "key").Err(), redis.ErrCircuitOpen)
+	_, err := client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Get(ctx, "key")
+		return nil
+	})
+	assert.ErrorIs(t, err, redis.ErrCircuitOpen)
+
+	assert.NoError(t, mr.StartAddr(addr))
+	assert.ErrorIs(t, client.Set(ctx, "key", "v", 0).Err(), redis.ErrCircuitOpen)
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, client.Set(ctx, "key", "v", 0).Err())
+	assert.False(t, breaker.Open())
+	assert.Equal(t, "v", client.Get(ctx, "key").Val())
+}
+
+// Test that a server that stays down keeps the circuit open after a probe
+func TestCircuitBreakerFailedProbe(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	breaker := redis.NewCircuitBreaker(1, 20*time.Millisecond)
+	client.AddHook(breaker)
+	mr.Close()
+
+	client.Ping(redis.Ctx)
+	assert.True(t, breaker.Open())
+	time.Sleep(30 * time.Millisecond)
+	assert.NotErrorIs(t, client.Ping(redis.Ctx).Err(), redis.ErrCircuitOpen)
+	assert.ErrorIs(t, client.Ping(redis.Ctx).Err(), redis.ErrCircuitOpen)
+}
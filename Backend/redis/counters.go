@@ -0,0 +1,175 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// names of the per blog counters kept in Redis
+const (
+	CounterLikes    = "likes"
+	CounterComments = "comments"
+	CounterViews    = "views"
+)
+
+// set of blog ids whose counters changed since the last write-back
+const dirtyCountersKey = "counters:dirty"
+
+var ErrNotInitialized = errors.New("redis client not initialised")
+
+// only increments counters that have already been seeded, so a missing key
+// is never mistaken for a count of zero
+var incrIfExists = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return {1, redis.call("INCRBY", KEYS[1], ARGV[1])}
+end
+return {0, 0}
+`)
+
+// function to build the key of a blog counter
+func CounterKey(blogID uint, name string) string {
+	return fmt.Sprintf("blog:%d:%s", blogID, name)
+}
+
+// function to read a blog counter, found is false when it was never seeded
+func (s *Store) GetCounter(blogID uint, name string) (int64, bool, error) {
+	if s.client == nil {
+		return 0, false, ErrNotInitialized
+	}
+	val, err := s.client.Get(Ctx, CounterKey(blogID, name)).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	return val, true, nil
+}
+
+// function to seed a blog counter unless another request already did
+func (s *Store) SeedCounter(blogID uint, name string, value int64) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	return s.client.SetNX(Ctx, CounterKey(blogID, name), value, 0).Err()
+}
+
+// function to overwrite a blog counter, used when reconciling with the database
+func (s *Store) SetCounter(blogID uint, name string, value int64) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	return s.client.Set(Ctx, CounterKey(blogID, name), value, 0).Err()
+}
+
+// function to atomically add delta to a seeded blog counter and mark the blog
+// for write-back, applied is false when the counter was not seeded yet
+func (s *Store) IncrCounter(blogID uint, name string, delta int64) (bool, error) {
+	if s.client == nil {
+		return false, ErrNotInitialized
+	}
+	res, err := incrIfExists.Run(Ctx, s.client, []string{CounterKey(blogID, name)}, delta).Slice()
+	if err != nil {
+		return false, err
+	}
+	if applied, _ := res[0].(int64); applied == 0 {
+		return false, nil
+	}
+	return true, s.client.SAdd(Ctx, dirtyCountersKey, blogID).Err()
+}
+
+// function to drop every counter of a deleted blog
+func (s *Store) DeleteCounters(blogID uint) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	s.client.SRem(Ctx, dirtyCountersKey, blogID)
+	return s.client.Del(Ctx,
+		CounterKey(blogID, CounterLikes),
+		CounterKey(blogID, CounterComments),
+		CounterKey(blogID, CounterViews),
+	).Err()
+}
+
+// function to pop up to count blog ids whose counters need writing back
+func (s *Store) PopDirtyCounters(count int64) ([]uint, error) {
+	if s.client == nil {
+		return nil, ErrNotInitialized
+	}
+	members, err := s.client.SPopN(Ctx, dirtyCountersKey, count).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		id, err := strconv.ParseUint(m, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
+
+// function to put blog ids back on the write-back set after a failed flush
+func (s *Store) MarkCountersDirty(blogIDs ...uint) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	if len(blogIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(blogIDs))
+	for i, id := range blogIDs {
+		members[i] = id
+	}
+	return s.client.SAdd(Ctx, dirtyCountersKey, members...).Err()
+}
+
+// function to read one counter of many blogs in a single round trip, blogs
+// whose counter was never seeded are missing from the result
+func (s *Store) GetCounters(blogIDs []uint, name string) (map[uint]int64, error) {
+	if s.client == nil {
+		return nil, ErrNotInitialized
+	}
+	counts := make(map[uint]int64, len(blogIDs))
+	if len(blogIDs) == 0 {
+		return counts, nil
+	}
+	keys := make([]string, len(blogIDs))
+	for i, id := range blogIDs {
+		keys[i] = CounterKey(id, name)
+	}
+	vals, err := s.client.MGet(Ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vals {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			counts[blogIDs[i]] = n
+		}
+	}
+	return counts, nil
+}
+
+// function to seed many blog counters in a single round trip
+func (s *Store) SeedCounters(name string, values map[uint]int64) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for id, val := range values {
+		pipe.SetNX(Ctx, CounterKey(id, name), val, 0)
+	}
+	_, err := pipe.Exec(Ctx)
+	return err
+}
@@ -0,0 +1,59 @@
+package redis
+
+import (
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// function to replace the sorted set at key with scores, readers see either
+// the old or the new leaderboard and never a partially written one
+func (s *Store) StoreLeaderboard(key string, scores map[uint]float64) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	tmp := key + ":building"
+	pipe := s.client.TxPipeline()
+	pipe.Del(Ctx, tmp)
+	if len(scores) == 0 {
+		pipe.Del(Ctx, key)
+		// an empty marker tells readers the leaderboard was computed
+		pipe.Set(Ctx, key+":empty", 1, 0)
+	} else {
+		members := make([]*redis.Z, 0, len(scores))
+		for id, score := range scores {
+			members = append(members, &redis.Z{Score: score, Member: id})
+		}
+		pipe.ZAdd(Ctx, tmp, members...)
+		pipe.Rename(Ctx, tmp, key)
+		pipe.Del(Ctx, key+":empty")
+	}
+	_, err := pipe.Exec(Ctx)
+	return err
+}
+
+// function to read the ids of the n best ranked members of a leaderboard,
+// found is false when the leaderboard was never stored
+func (s *Store) LeaderboardTop(key string, n int64) ([]uint, bool, error) {
+	if s.client == nil {
+		return nil, false, ErrNotInitialized
+	}
+	members, err := s.client.ZRevRange(Ctx, key, 0, n-1).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(members) == 0 {
+		empty, err := s.client.Exists(Ctx, key+":empty").Result()
+		if err != nil {
+			return nil, false, err
+		}
+		return nil, empty == 1, nil
+	}
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, true, nil
+}
@@ -1,61 +1,78 @@
 package redis
 
 import (
+	"Gator_blog/config"
 	"context"
 	"log"
 	"time"
 
-	"encoding/json"
-	// "time"
-
 	"github.com/go-redis/redis/v8"
 )
 
 var (
+	// RedisClient is the client InitRedis connects, for the stores and
+	// caches of the server
 	RedisClient *redis.Client
 	Ctx         = context.Background()
+
+	// Breaker guards RedisClient, see InitRedis
+	Breaker *CircuitBreaker
 )
 
-// function to initialise redis connection
-func InitRedis() {
+// failures in a row after which Redis is left alone, and how long for
+var (
+	BreakerThreshold = 5
+	BreakerCooldown  = 10 * time.Second
+)
+
+// function to initialise redis connection. The server starts even when Redis
+// is down: the breaker fails calls fast and every caller falls back to the
+// database until Redis answers again.
+func InitRedis(cfg config.RedisConfig) {
 	RedisClient = redis.NewClient(&redis.Options{
-		Addr:     "localhost:6379",
-		Password: "",
-		DB:       0,
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  time.Second,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		MaxRetries:   1,
 	})
+	Breaker = NewCircuitBreaker(BreakerThreshold, BreakerCooldown)
+	RedisClient.AddHook(Breaker)
 	_, err := RedisClient.Ping(Ctx).Result()
 	if err != nil {
-		panic("Failed to connect to Redis: " + err.Error())
+		log.Println("Redis unavailable, serving from the database:", err)
+		return
 	}
 	log.Println("Redis successfull")
 }
 
-// function to store data in Redis
-func SetCache(key string, value interface{}, expiration time.Duration) error {
-	json, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-	return RedisClient.Set(Ctx, key, json, expiration).Err()
+// Store keeps the blog counters, home timelines, leaderboards and view
+// markers in one Redis database. Every call on a Store without a client
+// fails with ErrNotInitialized, so callers fall back to the database.
+type Store struct {
+	client *redis.Client
 }
 
-// function to retrieve data from Redis
-func GetCache(key string, dest interface{}) (bool, error) {
-	val, err := RedisClient.Get(Ctx, key).Result()
-	if err == redis.Nil {
-		return false, nil
-	} else if err != nil {
-		return false, err
-	}
-	err = json.Unmarshal([]byte(val), dest)
-	if err != nil {
-		return false, err
-	}
-	return true, nil
+// NewStore returns a Store on client, which may be nil
+func NewStore(client *redis.Client) *Store {
+	return &Store{client: client}
+}
 
+// function to delete a key
+func (s *Store) DeleteCache(key string) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	return s.client.Del(Ctx, key).Err()
 }
 
-// function to delete a key from cache
-func DeleteCache(key string) error {
-	return RedisClient.Del(Ctx, key).Err()
+// function to set a marker key unless it already exists, first is true for
+// the call that created it
+func (s *Store) SetOnce(key string, expiration time.Duration) (bool, error) {
+	if s.client == nil {
+		return false, ErrNotInitialized
+	}
+	return s.client.SetNX(Ctx, key, 1, expiration).Result()
 }
@@ -0,0 +1,122 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// how many posts a home timeline keeps and how long an unread one lives
+const (
+	TimelineSize = 800
+	timelineTTL  = 7 * 24 * time.Hour
+)
+
+// adds a post to a timeline only if the timeline is materialised, keeping
+// the newest TimelineSize entries
+var timelinePush = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	redis.call("ZADD", KEYS[1], ARGV[1], ARGV[1])
+	redis.call("ZREMRANGEBYRANK", KEYS[1], 0, -(tonumber(ARGV[2]) + 1))
+	return 1
+end
+return 0
+`)
+
+// function to build the key of a user's home timeline
+func TimelineKey(userID uint) string {
+	return fmt.Sprintf("user:%d:timeline", userID)
+}
+
+// function to replace a user's timeline with the given blog ids. Blog ids
+// double as scores since they grow with publication time. A placeholder
+// member keeps timelines of users whose authors never posted materialised.
+func (s *Store) StoreTimeline(userID uint, blogIDs []uint) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	key := TimelineKey(userID)
+	members := []*redis.Z{{Score: 0, Member: 0}}
+	for _, id := range blogIDs {
+		members = append(members, &redis.Z{Score: float64(id), Member: id})
+	}
+	pipe := s.client.TxPipeline()
+	pipe.Del(Ctx, key)
+	pipe.ZAdd(Ctx, key, members...)
+	pipe.Expire(Ctx, key, timelineTTL)
+	_, err := pipe.Exec(Ctx)
+	return err
+}
+
+// function to add a new post to the timelines of the given users, users
+// without a materialised timeline are skipped
+func (s *Store) PushToTimelines(userIDs []uint, blogID uint) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	// EVALSHA cannot fall back to EVAL inside a pipeline, so send the script
+	pipe := s.client.Pipeline()
+	for _, id := range userIDs {
+		timelinePush.Eval(Ctx, pipe, []string{TimelineKey(id)}, blogID, TimelineSize)
+	}
+	_, err := pipe.Exec(Ctx)
+	return err
+}
+
+// function to take a deleted post off the timelines of the given users
+func (s *Store) RemoveFromTimelines(userIDs []uint, blogID uint) error {
+	if s.client == nil {
+		return ErrNotInitialized
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	pipe := s.client.Pipeline()
+	for _, id := range userIDs {
+		pipe.ZRem(Ctx, TimelineKey(id), blogID)
+	}
+	_, err := pipe.Exec(Ctx)
+	return err
+}
+
+// function to read up to limit blog ids older than before from a timeline,
+// newest first. before of 0 starts from the newest post. found is false when
+// the timeline is not materialised.
+func (s *Store) TimelinePage(userID uint, before uint, limit int64) ([]uint, bool, error) {
+	if s.client == nil {
+		return nil, false, ErrNotInitialized
+	}
+	key := TimelineKey(userID)
+	max := "+inf"
+	if before != 0 {
+		max = "(" + strconv.FormatUint(uint64(before), 10)
+	}
+	members, err := s.client.ZRevRangeByScore(Ctx, key, &redis.ZRangeBy{
+		Max:   max,
+		Min:   "(0",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(members) == 0 {
+		exists, err := s.client.Exists(Ctx, key).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		return nil, exists == 1, nil
+	}
+	s.client.Expire(Ctx, key, timelineTTL)
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, true, nil
+}
@@ -0,0 +1,731 @@
+package repository
+
+import (
+	"Gator_blog/database"
+	"Gator_blog/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// translates the GORM miss into ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type gormUsers struct{ db *gorm.DB }
+
+// NewUsers returns a UserRepo backed by db
+func NewUsers(db *gorm.DB) UserRepo {
+	return &gormUsers{db: db}
+}
+
+func (r *gormUsers) ByID(id uint) (model.User, error) {
+	var user model.User
+	err := r.db.First(&user, id).Error
+	return user, notFound(err)
+}
+
+func (r *gormUsers) ByEmail(email string) (model.User, error) {
+	var user model.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	return user, notFound(err)
+}
+
+func (r *gormUsers) ByUsername(username string) (model.User, error) {
+	var user model.User
+	err := r.db.Where("username = ?", username).First(&user).Error
+	return user, notFound(err)
+}
+
+func (r *gormUsers) Create(user *model.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *gormUsers) Save(user *model.User) error {
+	return r.db.Omit("avatar_media_id").Save(user).Error
+}
+
+func (r *gormUsers) SetAvatar(user *model.User, mediaID *uint) error {
+	if err := r.db.Model(&model.User{ID: user.ID}).Update("avatar_media_id", mediaID).Error; err != nil {
+		return err
+	}
+	user.AvatarMediaID = mediaID
+	return nil
+}
+
+type gormBlogs struct{ db *gorm.DB }
+
+// NewBlogs returns a BlogRepo backed by db
+func NewBlogs(db *gorm.DB) BlogRepo {
+	return &gormBlogs{db: db}
+}
+
+func (r *gormBlogs) ByID(id uint) (model.Blog, error) {
+	var blog model.Blog
+	err := r.db.Preload("Tags").Where("id = ?", id).First(&blog).Error
+	return blog, notFound(err)
+}
+
+func (r *gormBlogs) ByIDs(ids []uint) ([]model.Blog, error) {
+	var blogs []model.Blog
+	err := r.db.Where("id IN ?", ids).Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormBlogs) Owned(id, userID uint) (model.Blog, error) {
+	var blog model.Blog
+	err := r.db.Preload("Tags").Where("id = ? AND user_id = ?", id, userID).First(&blog).Error
+	return blog, notFound(err)
+}
+
+func (r *gormBlogs) List(filter BlogFilter) ([]model.Blog, error) {
+	query := r.db
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Title != "" {
+		condition, args := database.Contains(filter.Title, "title")
+		query = query.Where(condition, args...)
+	}
+	if filter.Search != "" {
+		condition, args := database.Contains(filter.Search, "title", "post")
+		query = query.Where(condition, args...)
+	}
+	var blogs []model.Blog
+	err := query.Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormBlogs) ByAuthors(authorIDs []uint, before uint, limit int) ([]model.Blog, error) {
+	blogs := []model.Blog{}
+	query := r.db.Where("user_id IN ?", authorIDs)
+	if before != 0 {
+		query = query.Where("id < ?", before)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormBlogs) LatestIDs(authorIDs []uint, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Blog{}).
+		Where("user_id IN ?", authorIDs).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *gormBlogs) Tags(names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tag := model.Tag{Name: name}
+		if err := r.db.Where("name = ?", name).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *gormBlogs) Create(blog *model.Blog) error {
+	if blog.Version == 0 {
+		blog.Version = 1
+	}
+	return r.db.Create(blog).Error
+}
+
+func (r *gormBlogs) Update(blog *model.Blog, tags []model.Tag) error {
+	version := blog.Version
+	blog.Version++
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(blog).Where("version = ?", version).
+			Select("title", "post", "version", "updated_at").Updates(blog)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrConflict
+		}
+		if tags == nil {
+			return nil
+		}
+		return tx.Model(blog).Association("Tags").Replace(tags)
+	})
+	if err != nil {
+		blog.Version = version
+	}
+	return err
+}
+
+func (r *gormBlogs) SetCover(blog *model.Blog, mediaID *uint) error {
+	if err := r.db.Model(&model.Blog{ID: blog.ID}).Update("cover_media_id", mediaID).Error; err != nil {
+		return err
+	}
+	blog.CoverMediaID = mediaID
+	return nil
+}
+
+func (r *gormBlogs) Delete(blog *model.Blog) error {
+	if err := r.db.Model(blog).Association("Tags").Clear(); err != nil {
+		return err
+	}
+	return r.db.Delete(blog).Error
+}
+
+type gormComments struct{ db *gorm.DB }
+
+// NewComments returns a CommentRepo backed by db
+func NewComments(db *gorm.DB) CommentRepo {
+	return &gormComments{db: db}
+}
+
+func (r *gormComments) Create(comment *model.Comment) error {
+	if comment.Version == 0 {
+		comment.Version = 1
+	}
+	return r.db.Create(comment).Error
+}
+
+func (r *gormComments) ByBlog(blogID uint) ([]model.Comment, error) {
+	comments := []model.Comment{}
+	err := r.db.Where("blog_id = ?", blogID).Find(&comments).Error
+	return comments, err
+}
+
+func (r *gormComments) Owned(id, blogID, userID uint) (model.Comment, error) {
+	var comment model.Comment
+	err := r.db.Where("id = ? AND blog_id = ? AND user_id = ?", id, blogID, userID).First(&comment).Error
+	return comment, notFound(err)
+}
+
+func (r *gormComments) Update(comment *model.Comment) error {
+	version := comment.Version
+	comment.Version++
+	result := r.db.Model(comment).Where("version = ?", version).
+		Updates(map[string]interface{}{"content": comment.Content, "version": comment.Version})
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrConflict
+	}
+	if result.Error != nil {
+		comment.Version = version
+		return result.Error
+	}
+	return nil
+}
+
+func (r *gormComments) Delete(comment *model.Comment) error {
+	return r.db.Delete(comment).Error
+}
+
+func (r *gormComments) Latest(blogIDs []uint, limit int) ([]model.Comment, error) {
+	ranked := r.db.Model(&model.Comment{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY blog_id ORDER BY created_at DESC, id DESC) AS preview_rank").
+		Where("blog_id IN ?", blogIDs)
+
+	var comments []model.Comment
+	err := r.db.Table("(?) AS ranked", ranked).
+		Where("preview_rank <= ?", limit).
+		Order("blog_id, created_at, id").
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *gormComments) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	return database.CountByBlog(r.db, &model.Comment{}, blogIDs)
+}
+
+type gormLikes struct{ db *gorm.DB }
+
+// NewLikes returns a LikeRepo backed by db
+func NewLikes(db *gorm.DB) LikeRepo {
+	return &gormLikes{db: db}
+}
+
+func (r *gormLikes) Create(like *model.Like) error {
+	return r.db.Create(like).Error
+}
+
+func (r *gormLikes) Delete(userID, blogID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND blog_id = ?", userID, blogID).Delete(&model.Like{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormLikes) Count(blogID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Like{}).Where("blog_id = ?", blogID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormLikes) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	return database.CountByBlog(r.db, &model.Like{}, blogIDs)
+}
+
+type gormFollows struct{ db *gorm.DB }
+
+// NewFollows returns a FollowRepo backed by db
+func NewFollows(db *gorm.DB) FollowRepo {
+	return &gormFollows{db: db}
+}
+
+func (r *gormFollows) Exists(followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormFollows) Create(follow *model.Follow) error {
+	return r.db.Create(follow).Error
+}
+
+func (r *gormFollows) Delete(followerID, followeeID uint) (bool, error) {
+	result := r.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&model.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormFollows) Followees(followerID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Follow{}).Where("follower_id = ?", followerID).Pluck("followee_id", &ids).Error
+	return ids, err
+}
+
+func (r *gormFollows) Followers(followeeID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Follow{}).Where("followee_id = ?", followeeID).Pluck("follower_id", &ids).Error
+	return ids, err
+}
+
+func (r *gormFollows) CountFollowers(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).Where("followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormFollows) CountFollowing(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Follow{}).Where("follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormFollows) ListFollowers(userID, cursor uint, limit int) ([]FollowedUser, error) {
+	return r.list("followee_id", "follower_id", userID, cursor, limit)
+}
+
+func (r *gormFollows) ListFollowing(userID, cursor uint, limit int) ([]FollowedUser, error) {
+	return r.list("follower_id", "followee_id", userID, cursor, limit)
+}
+
+// pages through the follows whose match column is userID, returning the
+// users in the other column
+func (r *gormFollows) list(match, other string, userID, cursor uint, limit int) ([]FollowedUser, error) {
+	users := []FollowedUser{}
+	query := r.db.Table("follows").
+		Select("follows.id as follow_id, users.id, users.username").
+		Joins("JOIN users ON users.id = follows."+other).
+		Where("follows."+match+" = ?", userID).
+		Order("follows.id DESC").
+		Limit(limit)
+	if cursor != 0 {
+		query = query.Where("follows.id < ?", cursor)
+	}
+	err := query.Scan(&users).Error
+	return users, err
+}
+
+type gormBookmarks struct{ db *gorm.DB }
+
+// NewBookmarks returns a BookmarkRepo backed by db
+func NewBookmarks(db *gorm.DB) BookmarkRepo {
+	return &gormBookmarks{db: db}
+}
+
+func (r *gormBookmarks) Find(userID, blogID uint) (model.Bookmark, error) {
+	var bookmark model.Bookmark
+	err := r.db.Where("user_id = ? AND blog_id = ?", userID, blogID).First(&bookmark).Error
+	return bookmark, notFound(err)
+}
+
+func (r *gormBookmarks) Create(bookmark *model.Bookmark) error {
+	return r.db.Create(bookmark).Error
+}
+
+func (r *gormBookmarks) Delete(userID, blogID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND blog_id = ?", userID, blogID).Delete(&model.Bookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormBookmarks) Blogs(userID uint) ([]model.Blog, error) {
+	blogs := []model.Blog{}
+	err := r.db.Joins("JOIN bookmarks ON bookmarks.blog_id = blogs.id").
+		Where("bookmarks.user_id = ?", userID).
+		Order("bookmarks.id DESC").
+		Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormBookmarks) Bookmarked(userID uint, blogIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&model.Bookmark{}).
+		Where("user_id = ? AND blog_id IN ?", userID, blogIDs).
+		Pluck("blog_id", &ids).Error
+	return ids, err
+}
+
+type gormReadingLists struct{ db *gorm.DB }
+
+// NewReadingLists returns a ReadingListRepo backed by db
+func NewReadingLists(db *gorm.DB) ReadingListRepo {
+	return &gormReadingLists{db: db}
+}
+
+func (r *gormReadingLists) ByUser(userID uint) ([]model.ReadingList, error) {
+	lists := []model.ReadingList{}
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&lists).Error
+	return lists, err
+}
+
+func (r *gormReadingLists) Count(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ReadingList{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormReadingLists) Owned(id, userID uint) (model.ReadingList, error) {
+	var list model.ReadingList
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&list).Error
+	return list, notFound(err)
+}
+
+func (r *gormReadingLists) Shared(token string) (model.ReadingList, error) {
+	var list model.ReadingList
+	err := r.db.Where("share_token = ? AND public = ?", token, true).First(&list).Error
+	return list, notFound(err)
+}
+
+func (r *gormReadingLists) Create(list *model.ReadingList) error {
+	return r.db.Create(list).Error
+}
+
+func (r *gormReadingLists) Save(list *model.ReadingList) error {
+	return r.db.Save(list).Error
+}
+
+func (r *gormReadingLists) Delete(list *model.ReadingList) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("reading_list_id = ?", list.ID).Delete(&model.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(list).Error
+	})
+}
+
+func (r *gormReadingLists) Blogs(listID uint) ([]model.Blog, error) {
+	blogs := []model.Blog{}
+	err := r.db.Joins("JOIN reading_list_items ON reading_list_items.blog_id = blogs.id").
+		Where("reading_list_items.reading_list_id = ?", listID).
+		Order("reading_list_items.position, reading_list_items.id").
+		Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormReadingLists) Items(listID uint) ([]model.ReadingListItem, error) {
+	var items []model.ReadingListItem
+	err := r.db.Where("reading_list_id = ?", listID).Find(&items).Error
+	return items, err
+}
+
+func (r *gormReadingLists) Item(listID, blogID uint) (model.ReadingListItem, error) {
+	var item model.ReadingListItem
+	err := r.db.Where("reading_list_id = ? AND blog_id = ?", listID, blogID).First(&item).Error
+	return item, notFound(err)
+}
+
+func (r *gormReadingLists) Append(item *model.ReadingListItem) error {
+	var last struct{ Max int }
+	err := r.db.Model(&model.ReadingListItem{}).
+		Select("COALESCE(MAX(position), 0) AS max").
+		Where("reading_list_id = ?", item.ReadingListID).
+		Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	item.Position = last.Max + 1
+	return r.db.Create(item).Error
+}
+
+func (r *gormReadingLists) Remove(listID, blogID uint) (bool, error) {
+	result := r.db.Where("reading_list_id = ? AND blog_id = ?", listID, blogID).Delete(&model.ReadingListItem{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormReadingLists) Reorder(items []model.ReadingListItem, positions map[uint]int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Model(&item).Update("position", positions[item.BlogID]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type gormMedia struct{ db *gorm.DB }
+
+// NewMedia returns a MediaRepo backed by db
+func NewMedia(db *gorm.DB) MediaRepo {
+	return &gormMedia{db: db}
+}
+
+func (r *gormMedia) ByID(id uint) (model.Media, error) {
+	var media model.Media
+	err := r.db.Preload("Variants").First(&media, id).Error
+	return media, notFound(err)
+}
+
+func (r *gormMedia) Owned(id, userID uint, purpose string) (model.Media, error) {
+	var media model.Media
+	err := r.db.Preload("Variants").Where("id = ? AND user_id = ? AND purpose = ?", id, userID, purpose).First(&media).Error
+	return media, notFound(err)
+}
+
+func (r *gormMedia) List(userID uint, purpose string, cursor uint, limit int) ([]model.Media, error) {
+	media := []model.Media{}
+	query := r.db.Preload("Variants").Where("user_id = ? AND purpose = ?", userID, purpose)
+	if cursor != 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&media).Error
+	return media, err
+}
+
+func (r *gormMedia) Create(media *model.Media) error {
+	return r.db.Create(media).Error
+}
+
+func (r *gormMedia) Delete(media *model.Media) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", media.ID).Delete(&model.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(media).Error
+	})
+}
+
+func (r *gormMedia) Variants(mediaIDs []uint) ([]model.MediaVariant, error) {
+	var variants []model.MediaVariant
+	err := r.db.Where("media_id IN ?", mediaIDs).Find(&variants).Error
+	return variants, err
+}
+
+type gormAnalytics struct{ db *gorm.DB }
+
+// NewAnalytics returns an AnalyticsRepo backed by db
+func NewAnalytics(db *gorm.DB) AnalyticsRepo {
+	return &gormAnalytics{db: db}
+}
+
+func (r *gormAnalytics) Posts(userID uint) ([]model.Blog, error) {
+	blogs := []model.Blog{}
+	err := r.db.Select("id", "title").Where("user_id = ?", userID).Order("id").Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormAnalytics) DailyStats(blogIDs []uint, from, to string) ([]model.BlogDailyStat, error) {
+	var stats []model.BlogDailyStat
+	err := r.db.Where("blog_id IN ? AND day >= ? AND day <= ?", blogIDs, from, to).
+		Order("day").
+		Find(&stats).Error
+	return stats, err
+}
+
+func (r *gormAnalytics) Readers(blogIDs []uint, start, end time.Time) (map[uint]int64, error) {
+	type row struct {
+		BlogID  uint
+		Readers int64
+	}
+	var rows []row
+	err := r.db.Model(&model.BlogView{}).
+		Select("blog_id, COUNT(DISTINCT viewer) as readers").
+		Where("blog_id IN ? AND created_at >= ? AND created_at < ?", blogIDs, start, end).
+		Group("blog_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	readers := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		readers[row.BlogID] = row.Readers
+	}
+	return readers, nil
+}
+
+func (r *gormAnalytics) AuthorReaders(userID uint, start, end time.Time) (int64, error) {
+	var readers int64
+	err := r.db.Model(&model.BlogView{}).
+		Select("COUNT(DISTINCT blog_views.viewer)").
+		Joins("JOIN blogs ON blogs.id = blog_views.blog_id").
+		Where("blogs.user_id = ? AND blog_views.created_at >= ? AND blog_views.created_at < ?", userID, start, end).
+		Scan(&readers).Error
+	return readers, err
+}
+
+func (r *gormAnalytics) Viewed(blogID uint, viewer string, since time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.BlogView{}).
+		Where("blog_id = ? AND viewer = ? AND created_at >= ?", blogID, viewer, since).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormAnalytics) RecordView(view *model.BlogView) error {
+	return r.db.Create(view).Error
+}
+
+type gormProfiles struct{ db *gorm.DB }
+
+// NewProfiles returns a ProfileRepo backed by db
+func NewProfiles(db *gorm.DB) ProfileRepo {
+	return &gormProfiles{db: db}
+}
+
+func (r *gormProfiles) Counts(userID uint) (ProfileCounts, error) {
+	var counts ProfileCounts
+	if err := r.db.Model(&model.Blog{}).Where("user_id = ?", userID).Count(&counts.Posts).Error; err != nil {
+		return counts, err
+	}
+	err := r.db.Model(&model.Like{}).
+		Joins("JOIN blogs ON blogs.id = likes.blog_id").
+		Where("blogs.user_id = ?", userID).
+		Count(&counts.LikesReceived).Error
+	if err != nil {
+		return counts, err
+	}
+	if err := r.db.Model(&model.Follow{}).Where("followee_id = ?", userID).Count(&counts.Followers).Error; err != nil {
+		return counts, err
+	}
+	err = r.db.Model(&model.Follow{}).Where("follower_id = ?", userID).Count(&counts.Following).Error
+	return counts, err
+}
+
+type gormSite struct{ db *gorm.DB }
+
+// NewSite returns a SiteRepo backed by db
+func NewSite(db *gorm.DB) SiteRepo {
+	return &gormSite{db: db}
+}
+
+func (r *gormSite) Counts() (posts, authors int64, err error) {
+	if err = r.db.Model(&model.Blog{}).Count(&posts).Error; err != nil {
+		return
+	}
+	err = r.db.Model(&model.Blog{}).Distinct("user_id").Count(&authors).Error
+	return
+}
+
+func (r *gormSite) Posts(offset, limit int) ([]model.Blog, error) {
+	var blogs []model.Blog
+	err := r.db.Select("id", "updated_at").Order("id").Offset(offset).Limit(limit).Find(&blogs).Error
+	return blogs, err
+}
+
+func (r *gormSite) Authors(offset, limit int) ([]SitemapAuthor, error) {
+	// Aggregating ids rather than timestamps keeps the query portable
+	// across drivers
+	latest := r.db.Model(&model.Blog{}).
+		Select("user_id, MAX(id) AS latest_id").
+		Group("user_id").
+		Order("user_id").
+		Offset(offset).
+		Limit(limit)
+	var authors []SitemapAuthor
+	err := r.db.Table("(?) AS latest", latest).
+		Select("users.username, blogs.updated_at").
+		Joins("JOIN users ON users.id = latest.user_id").
+		Joins("JOIN blogs ON blogs.id = latest.latest_id").
+		Order("latest.user_id").
+		Scan(&authors).Error
+	return authors, err
+}
+
+func (r *gormSite) Tag(name string) (model.Tag, error) {
+	var tag model.Tag
+	err := r.db.Where("name = ?", name).First(&tag).Error
+	return tag, notFound(err)
+}
+
+func (r *gormSite) Newest(filter FeedFilter, limit int) ([]model.Blog, error) {
+	query := r.db
+	if filter.UserID != 0 {
+		query = query.Where("blogs.user_id = ?", filter.UserID)
+	}
+	if filter.TagID != 0 {
+		query = query.Joins("JOIN blog_tags ON blog_tags.blog_id = blogs.id").Where("blog_tags.tag_id = ?", filter.TagID)
+	}
+	var blogs []model.Blog
+	err := query.Preload("Tags").Order("blogs.id DESC").Limit(limit).Find(&blogs).Error
+	return blogs, err
+}
+
+type gormAccounts struct{ db *gorm.DB }
+
+// NewAccounts returns an AccountRepo backed by db
+func NewAccounts(db *gorm.DB) AccountRepo {
+	return &gormAccounts{db: db}
+}
+
+func (r *gormAccounts) Delete(user *model.User) (DeletedAccount, error) {
+	var deleted DeletedAccount
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Blog{}).Where("user_id = ?", user.ID).Pluck("id", &deleted.BlogIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Preload("Variants").Where("user_id = ?", user.ID).Find(&deleted.Media).Error; err != nil {
+			return err
+		}
+		var listIDs []uint
+		if err := tx.Model(&model.ReadingList{}).Where("user_id = ?", user.ID).Pluck("id", &listIDs).Error; err != nil {
+			return err
+		}
+		mediaIDs := make([]uint, len(deleted.Media))
+		for i, m := range deleted.Media {
+			mediaIDs[i] = m.ID
+		}
+		blogIDs := deleted.BlogIDs
+
+		deletes := []struct {
+			model interface{}
+			where string
+			args  []interface{}
+		}{
+			{&model.Comment{}, "user_id = ? OR blog_id IN ?", []interface{}{user.ID, blogIDs}},
+			{&model.Like{}, "user_id = ? OR blog_id IN ?", []interface{}{user.ID, blogIDs}},
+			{&model.BlogView{}, "user_id = ? OR blog_id IN ?", []interface{}{user.ID, blogIDs}},
+			{&model.BlogDailyStat{}, "blog_id IN ?", []interface{}{blogIDs}},
+			{&model.Bookmark{}, "user_id = ? OR blog_id IN ?", []interface{}{user.ID, blogIDs}},
+			{&model.ReadingListItem{}, "reading_list_id IN ? OR blog_id IN ?", []interface{}{listIDs, blogIDs}},
+			{&model.ReadingList{}, "user_id = ?", []interface{}{user.ID}},
+			{&model.Follow{}, "follower_id = ? OR followee_id = ?", []interface{}{user.ID, user.ID}},
+			{&model.Blog{}, "user_id = ?", []interface{}{user.ID}},
+			{&model.MediaVariant{}, "media_id IN ?", []interface{}{mediaIDs}},
+			{&model.Media{}, "user_id = ?", []interface{}{user.ID}},
+		}
+		if err := tx.Exec("DELETE FROM blog_tags WHERE blog_id IN ?", blogIDs).Error; err != nil {
+			return err
+		}
+		for _, d := range deletes {
+			if err := tx.Where(d.where, d.args...).Delete(d.model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(user).Error
+	})
+	if err != nil {
+		return DeletedAccount{}, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,458 @@
+// Package memory has in-memory implementations of the repositories for
+// tests. They are safe for concurrent use and keep no relations between
+// each other, deleting a blog leaves its comments and likes behind.
+package memory
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDuplicate is returned when a create breaks a unique constraint
+var ErrDuplicate = errors.New("duplicate key")
+
+var (
+	_ repository.UserRepo    = (*Users)(nil)
+	_ repository.BlogRepo    = (*Blogs)(nil)
+	_ repository.CommentRepo = (*Comments)(nil)
+	_ repository.LikeRepo    = (*Likes)(nil)
+)
+
+func containsFold(s, part string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(part))
+}
+
+type Users struct {
+	mu     sync.Mutex
+	nextID uint
+	users  map[uint]model.User
+}
+
+func NewUsers() *Users {
+	return &Users{users: map[uint]model.User{}}
+}
+
+// finds the first user matching match, in id order
+func (r *Users) find(match func(model.User) bool) (model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found *model.User
+	for _, user := range r.users {
+		if match(user) && (found == nil || user.ID < found.ID) {
+			user := user
+			found = &user
+		}
+	}
+	if found == nil {
+		return model.User{}, repository.ErrNotFound
+	}
+	return *found, nil
+}
+
+func (r *Users) ByID(id uint) (model.User, error) {
+	return r.find(func(u model.User) bool { return u.ID == id })
+}
+
+func (r *Users) ByEmail(email string) (model.User, error) {
+	return r.find(func(u model.User) bool { return u.Email == email })
+}
+
+func (r *Users) ByUsername(username string) (model.User, error) {
+	return r.find(func(u model.User) bool { return u.Username == username })
+}
+
+func (r *Users) Create(user *model.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, other := range r.users {
+		if other.Email == user.Email || other.Username == user.Username {
+			return ErrDuplicate
+		}
+	}
+	r.nextID++
+	user.ID = r.nextID
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *Users) Save(user *model.User) error {
+	if user.ID == 0 {
+		return r.Create(user)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *user
+	stored.AvatarMediaID = r.users[user.ID].AvatarMediaID
+	r.users[user.ID] = stored
+	return nil
+}
+
+func (r *Users) SetAvatar(user *model.User, mediaID *uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	stored.AvatarMediaID = mediaID
+	r.users[user.ID] = stored
+	user.AvatarMediaID = mediaID
+	return nil
+}
+
+type Blogs struct {
+	mu        sync.Mutex
+	nextID    uint
+	nextTagID uint
+	blogs     map[uint]model.Blog
+	tags      map[string]model.Tag
+	blogTags  map[uint][]model.Tag
+}
+
+func NewBlogs() *Blogs {
+	return &Blogs{blogs: map[uint]model.Blog{}, tags: map[string]model.Tag{}, blogTags: map[uint][]model.Tag{}}
+}
+
+func (r *Blogs) ByID(id uint) (model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blog, ok := r.blogs[id]
+	if !ok {
+		return model.Blog{}, repository.ErrNotFound
+	}
+	blog.Tags = append([]model.Tag{}, r.blogTags[id]...)
+	return blog, nil
+}
+
+func (r *Blogs) ByIDs(ids []uint) ([]model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blogs := []model.Blog{}
+	for _, id := range ids {
+		if blog, ok := r.blogs[id]; ok {
+			blogs = append(blogs, blog)
+		}
+	}
+	return blogs, nil
+}
+
+func (r *Blogs) Owned(id, userID uint) (model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blog, ok := r.blogs[id]
+	if !ok || blog.UserID != userID {
+		return model.Blog{}, repository.ErrNotFound
+	}
+	blog.Tags = append([]model.Tag{}, r.blogTags[id]...)
+	return blog, nil
+}
+
+func (r *Blogs) List(filter repository.BlogFilter) ([]model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blogs := []model.Blog{}
+	for _, blog := range r.blogs {
+		if filter.UserID != 0 && blog.UserID != filter.UserID {
+			continue
+		}
+		if filter.Title != "" && !containsFold(blog.Title, filter.Title) {
+			continue
+		}
+		if filter.Search != "" && !containsFold(blog.Title, filter.Search) && !containsFold(blog.Post, filter.Search) {
+			continue
+		}
+		blogs = append(blogs, blog)
+	}
+	sort.Slice(blogs, func(i, j int) bool { return blogs[i].ID < blogs[j].ID })
+	return blogs, nil
+}
+
+func (r *Blogs) ByAuthors(authorIDs []uint, before uint, limit int) ([]model.Blog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	authors := idSet(authorIDs)
+	blogs := []model.Blog{}
+	for _, blog := range r.blogs {
+		if authors[blog.UserID] && (before == 0 || blog.ID < before) {
+			blogs = append(blogs, blog)
+		}
+	}
+	sort.Slice(blogs, func(i, j int) bool { return blogs[i].ID > blogs[j].ID })
+	if len(blogs) > limit {
+		blogs = blogs[:limit]
+	}
+	return blogs, nil
+}
+
+func (r *Blogs) LatestIDs(authorIDs []uint, limit int) ([]uint, error) {
+	blogs, err := r.ByAuthors(authorIDs, 0, limit)
+	ids := make([]uint, len(blogs))
+	for i, blog := range blogs {
+		ids[i] = blog.ID
+	}
+	return ids, err
+}
+
+func (r *Blogs) Tags(names []string) ([]model.Tag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tag, ok := r.tags[name]
+		if !ok {
+			r.nextTagID++
+			tag = model.Tag{ID: r.nextTagID, Name: name}
+			r.tags[name] = tag
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *Blogs) Create(blog *model.Blog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	blog.ID = r.nextID
+	now := time.Now()
+	if blog.CreatedAt.IsZero() {
+		blog.CreatedAt = now
+	}
+	blog.UpdatedAt = now
+	if blog.Version == 0 {
+		blog.Version = 1
+	}
+	r.blogTags[blog.ID] = append([]model.Tag{}, blog.Tags...)
+	stored := *blog
+	stored.Tags = nil
+	r.blogs[blog.ID] = stored
+	return nil
+}
+
+func (r *Blogs) Update(blog *model.Blog, tags []model.Tag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.blogs[blog.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if old.Version != blog.Version {
+		return repository.ErrConflict
+	}
+	blog.UpdatedAt = time.Now()
+	blog.Version++
+	old.Title, old.Post, old.Version, old.UpdatedAt = blog.Title, blog.Post, blog.Version, blog.UpdatedAt
+	r.blogs[blog.ID] = old
+	if tags != nil {
+		r.blogTags[blog.ID] = append([]model.Tag{}, tags...)
+	}
+	return nil
+}
+
+func (r *Blogs) SetCover(blog *model.Blog, mediaID *uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.blogs[blog.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	stored.CoverMediaID = mediaID
+	stored.UpdatedAt = time.Now()
+	r.blogs[blog.ID] = stored
+	blog.CoverMediaID = mediaID
+	return nil
+}
+
+func (r *Blogs) Delete(blog *model.Blog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.blogs, blog.ID)
+	delete(r.blogTags, blog.ID)
+	return nil
+}
+
+type Comments struct {
+	mu       sync.Mutex
+	nextID   uint
+	comments map[uint]model.Comment
+}
+
+func NewComments() *Comments {
+	return &Comments{comments: map[uint]model.Comment{}}
+}
+
+func (r *Comments) Create(comment *model.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	comment.ID = r.nextID
+	now := time.Now()
+	comment.CreatedAt, comment.UpdatedAt = now, now
+	if comment.Version == 0 {
+		comment.Version = 1
+	}
+	r.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *Comments) ByBlog(blogID uint) ([]model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comments := []model.Comment{}
+	for _, comment := range r.comments {
+		if comment.BlogID == blogID {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments, nil
+}
+
+func (r *Comments) Owned(id, blogID, userID uint) (model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment, ok := r.comments[id]
+	if !ok || comment.BlogID != blogID || comment.UserID != userID {
+		return model.Comment{}, repository.ErrNotFound
+	}
+	return comment, nil
+}
+
+func (r *Comments) Update(comment *model.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old, ok := r.comments[comment.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if old.Version != comment.Version {
+		return repository.ErrConflict
+	}
+	old.Content = comment.Content
+	old.UpdatedAt = time.Now()
+	old.Version++
+	r.comments[comment.ID] = old
+	*comment = old
+	return nil
+}
+
+func (r *Comments) Delete(comment *model.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.comments, comment.ID)
+	return nil
+}
+
+func (r *Comments) Latest(blogIDs []uint, limit int) ([]model.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := idSet(blogIDs)
+	byBlog := map[uint][]model.Comment{}
+	for _, comment := range r.comments {
+		if wanted[comment.BlogID] {
+			byBlog[comment.BlogID] = append(byBlog[comment.BlogID], comment)
+		}
+	}
+	latest := []model.Comment{}
+	for _, comments := range byBlog {
+		sort.Slice(comments, func(i, j int) bool {
+			if !comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+				return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+			}
+			return comments[i].ID < comments[j].ID
+		})
+		if len(comments) > limit {
+			comments = comments[len(comments)-limit:]
+		}
+		latest = append(latest, comments...)
+	}
+	sort.SliceStable(latest, func(i, j int) bool { return latest[i].BlogID < latest[j].BlogID })
+	return latest, nil
+}
+
+func (r *Comments) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := idSet(blogIDs)
+	counts := map[uint]int64{}
+	for _, comment := range r.comments {
+		if wanted[comment.BlogID] {
+			counts[comment.BlogID]++
+		}
+	}
+	return counts, nil
+}
+
+func idSet(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+type Likes struct {
+	mu     sync.Mutex
+	nextID uint
+	likes  map[uint]model.Like
+}
+
+func NewLikes() *Likes {
+	return &Likes{likes: map[uint]model.Like{}}
+}
+
+func (r *Likes) Create(like *model.Like) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	like.ID = r.nextID
+	like.CreatedAt = time.Now()
+	r.likes[like.ID] = *like
+	return nil
+}
+
+func (r *Likes) Delete(userID, blogID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deleted := false
+	for id, like := range r.likes {
+		if like.UserID == userID && like.BlogID == blogID {
+			delete(r.likes, id)
+			deleted = true
+		}
+	}
+	return deleted, nil
+}
+
+func (r *Likes) Count(blogID uint) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, like := range r.likes {
+		if like.BlogID == blogID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *Likes) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wanted := idSet(blogIDs)
+	counts := map[uint]int64{}
+	for _, like := range r.likes {
+		if wanted[like.BlogID] {
+			counts[like.BlogID]++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,242 @@
+// Package repository defines the storage the services are built on. The
+// GORM implementations live here, package memory has in-memory fakes.
+package repository
+
+import (
+	"Gator_blog/model"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a lookup matches no record
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a record changed since the version that
+	// was updated
+	ErrConflict = errors.New("record changed concurrently")
+)
+
+type UserRepo interface {
+	ByID(id uint) (model.User, error)
+	ByEmail(email string) (model.User, error)
+	ByUsername(username string) (model.User, error)
+	Create(user *model.User) error
+	// Save writes user except its avatar, which only SetAvatar changes
+	Save(user *model.User) error
+	// SetAvatar points the avatar of user at the upload mediaID, nil
+	// removes it
+	SetAvatar(user *model.User, mediaID *uint) error
+}
+
+// BlogFilter narrows a blog listing, zero fields match every blog
+type BlogFilter struct {
+	UserID uint
+	Title  string // case-insensitive part of the title
+	Search string // case-insensitive part of the title or the post
+}
+
+type BlogRepo interface {
+	// ByID returns the blog with its tags
+	ByID(id uint) (model.Blog, error)
+	// ByIDs returns the blogs that exist out of ids, in no particular order
+	ByIDs(ids []uint) ([]model.Blog, error)
+	// Owned returns the blog with its tags if userID wrote it
+	Owned(id, userID uint) (model.Blog, error)
+	List(filter BlogFilter) ([]model.Blog, error)
+	// ByAuthors returns up to limit blogs of the authors with an id below
+	// before, newest first. A before of 0 starts at the newest blog.
+	ByAuthors(authorIDs []uint, before uint, limit int) ([]model.Blog, error)
+	// LatestIDs returns the ids of the newest limit blogs of the authors,
+	// newest first
+	LatestIDs(authorIDs []uint, limit int) ([]uint, error)
+	// Tags looks up the tags with the given names, creating missing ones
+	Tags(names []string) ([]model.Tag, error)
+	Create(blog *model.Blog) error
+	// Update saves the title and post of blog and replaces its tags when
+	// tags is not nil, in one transaction. Its counters and cover are left
+	// alone. It fails with ErrConflict unless the stored blog is still at
+	// blog.Version, which is counted up on success.
+	Update(blog *model.Blog, tags []model.Tag) error
+	// SetCover points the cover of blog at the upload mediaID, nil removes
+	// it. Like the counters, the cover does not change the version.
+	SetCover(blog *model.Blog, mediaID *uint) error
+	Delete(blog *model.Blog) error
+}
+
+type CommentRepo interface {
+	Create(comment *model.Comment) error
+	ByBlog(blogID uint) ([]model.Comment, error)
+	// Owned returns the comment on blogID if userID wrote it
+	Owned(id, blogID, userID uint) (model.Comment, error)
+	// Update saves the content of comment, versioned like BlogRepo.Update
+	Update(comment *model.Comment) error
+	Delete(comment *model.Comment) error
+	// Latest returns the latest limit comments of each of blogIDs, oldest
+	// first within a blog
+	Latest(blogIDs []uint, limit int) ([]model.Comment, error)
+	// CountByBlog counts the comments of each of blogIDs, blogs without
+	// comments are missing from the result
+	CountByBlog(blogIDs []uint) (map[uint]int64, error)
+}
+
+type LikeRepo interface {
+	Create(like *model.Like) error
+	// Delete removes the like of userID on blogID and reports whether
+	// there was one
+	Delete(userID, blogID uint) (bool, error)
+	Count(blogID uint) (int64, error)
+	// CountByBlog counts the likes of each of blogIDs like
+	// CommentRepo.CountByBlog
+	CountByBlog(blogIDs []uint) (map[uint]int64, error)
+}
+
+// FollowedUser is a user on the other side of a follow, FollowID pages
+// through follower and following lists
+type FollowedUser struct {
+	FollowID uint
+	ID       uint
+	Username string
+}
+
+type FollowRepo interface {
+	Exists(followerID, followeeID uint) (bool, error)
+	Create(follow *model.Follow) error
+	// Delete removes the follow and reports whether there was one
+	Delete(followerID, followeeID uint) (bool, error)
+	// Followees returns the ids of the users followerID follows
+	Followees(followerID uint) ([]uint, error)
+	// Followers returns the ids of the users following followeeID
+	Followers(followeeID uint) ([]uint, error)
+	CountFollowers(userID uint) (int64, error)
+	CountFollowing(userID uint) (int64, error)
+	// ListFollowers pages through the users following userID, the latest
+	// follow first. cursor is the FollowID of the last user of the
+	// previous page, 0 for the first page.
+	ListFollowers(userID, cursor uint, limit int) ([]FollowedUser, error)
+	// ListFollowing pages through the users userID follows likewise
+	ListFollowing(userID, cursor uint, limit int) ([]FollowedUser, error)
+}
+
+type BookmarkRepo interface {
+	// Find returns the bookmark of userID on blogID
+	Find(userID, blogID uint) (model.Bookmark, error)
+	Create(bookmark *model.Bookmark) error
+	// Delete removes the bookmark and reports whether there was one
+	Delete(userID, blogID uint) (bool, error)
+	// Blogs returns the blogs userID bookmarked, the latest bookmark first
+	Blogs(userID uint) ([]model.Blog, error)
+	// Bookmarked returns the ids out of blogIDs that userID bookmarked
+	Bookmarked(userID uint, blogIDs []uint) ([]uint, error)
+}
+
+type ReadingListRepo interface {
+	// ByUser returns the lists of userID in the order they were made
+	ByUser(userID uint) ([]model.ReadingList, error)
+	Count(userID uint) (int64, error)
+	// Owned returns the list if userID made it
+	Owned(id, userID uint) (model.ReadingList, error)
+	// Shared returns the public list with the share token
+	Shared(token string) (model.ReadingList, error)
+	Create(list *model.ReadingList) error
+	Save(list *model.ReadingList) error
+	// Delete removes the list with its items
+	Delete(list *model.ReadingList) error
+	// Blogs returns the blogs of the list in list order
+	Blogs(listID uint) ([]model.Blog, error)
+	Items(listID uint) ([]model.ReadingListItem, error)
+	// Item returns the entry of blogID in the list
+	Item(listID, blogID uint) (model.ReadingListItem, error)
+	// Append adds item at the end of its list
+	Append(item *model.ReadingListItem) error
+	// Remove takes blogID off the list and reports whether it was on it
+	Remove(listID, blogID uint) (bool, error)
+	// Reorder moves every item to positions[item.BlogID], all or none
+	Reorder(items []model.ReadingListItem, positions map[uint]int) error
+}
+
+type MediaRepo interface {
+	// ByID returns the upload with its variants
+	ByID(id uint) (model.Media, error)
+	// Owned returns the upload with its variants if userID made it for
+	// purpose
+	Owned(id, userID uint, purpose string) (model.Media, error)
+	// List pages through the uploads of userID for purpose with their
+	// variants, newest first. cursor is the id of the last upload of the
+	// previous page, 0 for the first page.
+	List(userID uint, purpose string, cursor uint, limit int) ([]model.Media, error)
+	// Create stores the upload with its variants
+	Create(media *model.Media) error
+	// Delete removes the upload with its variants
+	Delete(media *model.Media) error
+	// Variants returns the variants of the uploads mediaIDs
+	Variants(mediaIDs []uint) ([]model.MediaVariant, error)
+}
+
+type AnalyticsRepo interface {
+	// Posts returns the id and title of every blog of userID, in id order
+	Posts(userID uint) ([]model.Blog, error)
+	// DailyStats returns the rollups of blogIDs from the day from to the
+	// day to, both as model.DayLayout, by day
+	DailyStats(blogIDs []uint, from, to string) ([]model.BlogDailyStat, error)
+	// Readers counts the distinct viewers of each of blogIDs from start
+	// until end
+	Readers(blogIDs []uint, start, end time.Time) (map[uint]int64, error)
+	// AuthorReaders counts the distinct viewers of any blog of userID from
+	// start until end
+	AuthorReaders(userID uint, start, end time.Time) (int64, error)
+	// Viewed reports whether viewer read blogID since the given time
+	Viewed(blogID uint, viewer string, since time.Time) (bool, error)
+	RecordView(view *model.BlogView) error
+}
+
+// ProfileCounts are the numbers on the public profile of an author
+type ProfileCounts struct {
+	Posts         int64
+	LikesReceived int64
+	Followers     int64
+	Following     int64
+}
+
+type ProfileRepo interface {
+	Counts(userID uint) (ProfileCounts, error)
+}
+
+// SitemapAuthor is the page of an author, which changes with their newest
+// post
+type SitemapAuthor struct {
+	Username  string
+	UpdatedAt time.Time
+}
+
+// FeedFilter narrows a syndication feed, zero fields match every blog
+type FeedFilter struct {
+	UserID uint
+	TagID  uint
+}
+
+// SiteRepo reads the public site for crawlers and feed readers
+type SiteRepo interface {
+	// Counts returns the number of posts and of authors with posts
+	Counts() (posts, authors int64, err error)
+	// Posts returns the id and update time of limit posts from offset, in
+	// id order
+	Posts(offset, limit int) ([]model.Blog, error)
+	// Authors returns limit authors with posts from offset, in id order
+	Authors(offset, limit int) ([]SitemapAuthor, error)
+	// Tag returns the tag called name
+	Tag(name string) (model.Tag, error)
+	// Newest returns the newest limit blogs of the filter with their tags
+	Newest(filter FeedFilter, limit int) ([]model.Blog, error)
+}
+
+// DeletedAccount is what remains of an account outside the database
+type DeletedAccount struct {
+	BlogIDs []uint
+	Media   []model.Media
+}
+
+type AccountRepo interface {
+	// Delete removes user with their posts, comments, likes, views,
+	// bookmarks, reading lists, follows and uploads, all or none
+	Delete(user *model.User) (DeletedAccount, error)
+}
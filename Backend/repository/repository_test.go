@@ -0,0 +1,208 @@
+package repository_test
+
+import (
+	"Gator_blog/database/dbtest"
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"Gator_blog/repository/memory"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type repos struct {
+	users    repository.UserRepo
+	blogs    repository.BlogRepo
+	comments repository.CommentRepo
+	likes    repository.LikeRepo
+}
+
+// runs test against the GORM repositories and the in-memory fakes, so the
+// fakes stay faithful to the real thing
+func forEach(t *testing.T, test func(t *testing.T, r repos)) {
+	t.Run("gorm", func(t *testing.T) {
+		db := dbtest.Open(t, &model.User{}, &model.Tag{}, &model.Blog{}, &model.Comment{}, &model.Like{})
+		test(t, repos{repository.NewUsers(db), repository.NewBlogs(db), repository.NewComments(db), repository.NewLikes(db)})
+	})
+	t.Run("memory", func(t *testing.T) {
+		test(t, repos{memory.NewUsers(), memory.NewBlogs(), memory.NewComments(), memory.NewLikes()})
+	})
+}
+
+func TestUsers(t *testing.T) {
+	forEach(t, func(t *testing.T, r repos) {
+		user := model.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+		assert.NoError(t, r.users.Create(&user))
+		assert.NotZero(t, user.ID)
+		assert.Error(t, r.users.Create(&model.User{Username: "alice", Email: "other@example.com", Password: "x"}))
+
+		found, err := r.users.ByEmail("alice@example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		found.ResetCode = "123456"
+		assert.NoError(t, r.users.Save(&found))
+		found, err = r.users.ByUsername("alice")
+		assert.NoError(t, err)
+		assert.Equal(t, "123456", found.ResetCode)
+
+		_, err = r.users.ByID(user.ID + 1)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		avatar := uint(3)
+		stale := found
+		assert.NoError(t, r.users.SetAvatar(&found, &avatar))
+		found, _ = r.users.ByID(user.ID)
+		assert.Equal(t, avatar, *found.AvatarMediaID)
+
+		// saving a copy read before the avatar changed keeps the avatar
+		stale.ResetCode = ""
+		assert.NoError(t, r.users.Save(&stale))
+		found, _ = r.users.ByID(user.ID)
+		assert.Equal(t, avatar, *found.AvatarMediaID)
+		assert.Empty(t, found.ResetCode)
+		assert.NoError(t, r.users.SetAvatar(&found, nil))
+		found, _ = r.users.ByID(user.ID)
+		assert.Nil(t, found.AvatarMediaID)
+	})
+}
+
+func TestBlogs(t *testing.T) {
+	forEach(t, func(t *testing.T, r repos) {
+		tags, err := r.blogs.Tags([]string{"go", "web"})
+		assert.NoError(t, err)
+		again, _ := r.blogs.Tags([]string{"go"})
+		assert.Equal(t, tags[0].ID, again[0].ID)
+
+		first := model.Blog{Title: "Learning Go", Post: "post", UserID: 1, UserName: "alice", Tags: tags}
+		second := model.Blog{Title: "Other", Post: "about go", UserID: 2, UserName: "bob"}
+		assert.NoError(t, r.blogs.Create(&first))
+		assert.NoError(t, r.blogs.Create(&second))
+
+		found, err := r.blogs.ByID(first.ID)
+		assert.NoError(t, err)
+		assert.Len(t, found.Tags, 2)
+		_, err = r.blogs.Owned(first.ID, 2)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+
+		mine, _ := r.blogs.List(repository.BlogFilter{UserID: 1})
+		assert.Len(t, mine, 1)
+		byTitle, _ := r.blogs.List(repository.BlogFilter{Title: "go"})
+		assert.Len(t, byTitle, 1)
+		searched, _ := r.blogs.List(repository.BlogFilter{Search: "GO"})
+		assert.Len(t, searched, 2)
+		some, _ := r.blogs.ByIDs([]uint{second.ID, 999})
+		assert.Len(t, some, 1)
+
+		// pages of authors run newest first
+		newest, _ := r.blogs.ByAuthors([]uint{1, 2}, 0, 1)
+		assert.Len(t, newest, 1)
+		assert.Equal(t, second.ID, newest[0].ID)
+		older, _ := r.blogs.ByAuthors([]uint{1, 2}, second.ID, 10)
+		assert.Len(t, older, 1)
+		assert.Equal(t, first.ID, older[0].ID)
+		latest, _ := r.blogs.LatestIDs([]uint{1, 2}, 10)
+		assert.Equal(t, []uint{second.ID, first.ID}, latest)
+
+		// like the counters, the cover leaves the version alone
+		uncovered, _ := r.blogs.Owned(second.ID, 2)
+		cover := uint(7)
+		assert.NoError(t, r.blogs.SetCover(&second, &cover))
+		covered, _ := r.blogs.ByID(second.ID)
+		assert.Equal(t, cover, *covered.CoverMediaID)
+		assert.Equal(t, int64(1), covered.Version)
+
+		// and an update made on a copy read before the cover was set keeps it
+		uncovered.Title = "Other again"
+		assert.NoError(t, r.blogs.Update(&uncovered, nil))
+		covered, _ = r.blogs.ByID(second.ID)
+		assert.Equal(t, "Other again", covered.Title)
+		assert.Equal(t, cover, *covered.CoverMediaID)
+
+		// counters belong to the write-back job, nil tags are left alone
+		found, _ = r.blogs.Owned(first.ID, 1)
+		assert.Len(t, found.Tags, 2)
+		found.Title = "Learning Go again"
+		found.LikesCount = 42
+		assert.NoError(t, r.blogs.Update(&found, nil))
+		found, _ = r.blogs.ByID(first.ID)
+		assert.Equal(t, "Learning Go again", found.Title)
+		assert.Zero(t, found.LikesCount)
+		assert.Len(t, found.Tags, 2)
+
+		assert.NoError(t, r.blogs.Update(&found, tags[:1]))
+		found, _ = r.blogs.ByID(first.ID)
+		assert.Len(t, found.Tags, 1)
+
+		// every update counts the version up, stale copies no longer apply
+		assert.Equal(t, int64(1), first.Version)
+		assert.Equal(t, int64(3), found.Version)
+		stale := found
+		stale.Version = 2
+		stale.Title = "Lost update"
+		assert.ErrorIs(t, r.blogs.Update(&stale, nil), repository.ErrConflict)
+		assert.Equal(t, int64(2), stale.Version)
+		found, _ = r.blogs.ByID(first.ID)
+		assert.Equal(t, "Learning Go again", found.Title)
+
+		assert.NoError(t, r.blogs.Delete(&found))
+		_, err = r.blogs.ByID(first.ID)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestCommentsAndLikes(t *testing.T) {
+	forEach(t, func(t *testing.T, r repos) {
+		none, err := r.comments.ByBlog(1)
+		assert.NoError(t, err)
+		assert.NotNil(t, none)
+
+		comment := model.Comment{Content: "Nice", UserID: 2, UserName: "bob", BlogID: 1}
+		assert.NoError(t, r.comments.Create(&comment))
+		assert.Equal(t, int64(1), comment.Version)
+
+		edited := comment
+		edited.Content = "Very nice"
+		assert.NoError(t, r.comments.Update(&edited))
+		assert.Equal(t, int64(2), edited.Version)
+		comment.Content = "Lost edit"
+		assert.ErrorIs(t, r.comments.Update(&comment), repository.ErrConflict)
+		stored, _ := r.comments.ByBlog(1)
+		assert.Equal(t, "Very nice", stored[0].Content)
+		assert.Equal(t, int64(2), stored[0].Version)
+		_, err = r.comments.Owned(comment.ID, 1, 3)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		owned, err := r.comments.Owned(comment.ID, 1, 2)
+		assert.NoError(t, err)
+		assert.NoError(t, r.comments.Delete(&owned))
+		none, _ = r.comments.ByBlog(1)
+		assert.Empty(t, none)
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, r.comments.Create(&model.Comment{Content: fmt.Sprint("Comment ", i), UserID: 2, BlogID: 2}))
+		}
+		latest, err := r.comments.Latest([]uint{1, 2}, 2)
+		assert.NoError(t, err)
+		assert.Len(t, latest, 2)
+		assert.Equal(t, "Comment 1", latest[0].Content)
+		assert.Equal(t, "Comment 2", latest[1].Content)
+		counts, _ := r.comments.CountByBlog([]uint{1, 2})
+		assert.Equal(t, map[uint]int64{2: 3}, counts)
+
+		assert.NoError(t, r.likes.Create(&model.Like{UserID: 2, BlogID: 1}))
+		assert.NoError(t, r.likes.Create(&model.Like{UserID: 3, BlogID: 1}))
+		count, _ := r.likes.Count(1)
+		assert.Equal(t, int64(2), count)
+
+		removed, err := r.likes.Delete(2, 1)
+		assert.NoError(t, err)
+		assert.True(t, removed)
+		removed, _ = r.likes.Delete(2, 1)
+		assert.False(t, removed)
+		count, _ = r.likes.Count(1)
+		assert.Equal(t, int64(1), count)
+		likes, _ := r.likes.CountByBlog([]uint{1, 2})
+		assert.Equal(t, map[uint]int64{1: 1}, likes)
+	})
+}
@@ -1,40 +1,119 @@
 package router
 
 import (
+	"Gator_blog/container"
 	"Gator_blog/controller"
 	"Gator_blog/middleware"
+	"Gator_blog/openapi"
+	"Gator_blog/storage"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
 )
 
-// setup routing information
-func SetupRoutes(app *fiber.App) {
+// setup routing information, the routes are served by the handlers of deps
+func SetupRoutes(app *fiber.App, deps *container.Container) {
 
-	api := app.Group("/api")
-	api.Post("/signin", controller.SignIn)
-	api.Post("/signup", controller.SignUp)
-	api.Post("/request-reset-code", controller.RequestResetCode)
-	api.Post("/verify-reset-code", controller.VerifyResetCode)
-	api.Post("/reset-password", controller.ResetPasswordWithEmail)
+	// Runtime and cache hit/miss/stale counters as JSON at /debug/vars
+	app.Use(expvar.New())
 
-	api.Get("/all-blogs-with-meta", controller.AllBlogsWithMeta)
-	api.Get("/top-popular-blogs", controller.Top5PopularBlogs)
+	// Search engine entry points
+	app.Get("/robots.txt", controller.Robots)
+	app.Get("/sitemap.xml", deps.Site.Sitemap)
+	app.Get("/sitemaps/:page.xml", deps.Site.SitemapPage)
+
+	// Uploads kept on the local filesystem are served by the app itself
+	if local, ok := deps.Infra.Storage.(*storage.Local); ok {
+		app.Static(local.BaseURL, local.Root)
+	}
+
+	// The OpenAPI document of both API versions and a page browsing it
+	app.Get("/api/openapi.json", openapi.Serve(controller.APISpec()))
+	app.Get("/api/docs", openapi.Docs("/api/openapi.json"))
+
+	// v2 serves the same handlers in its cleaned-up shapes, see
+	// controller.V2. It goes first, as the /api group would take its paths.
+	apiRoutes(app.Group("/api/v2", controller.V2), deps)
+	// v1 keeps the shapes the frontend reads until its sunset
+	apiRoutes(app.Group("/api", middleware.Deprecated("/api", "/api/v2")), deps)
+}
+
+// the routes of an API version
+func apiRoutes(api fiber.Router, deps *container.Container) {
+	api.Post("/signin", deps.Users.SignIn)
+	api.Post("/signup", deps.Users.SignUp)
+	api.Post("/request-reset-code", deps.Users.RequestResetCode)
+	api.Post("/verify-reset-code", deps.Users.VerifyResetCode)
+	api.Post("/reset-password", deps.Users.ResetPassword)
+
+	// Public listings, personalised when a token is sent
+	api.Get("/all-blogs-with-meta", middleware.OptionalJWTMiddleware(), deps.Blogs.AllWithMeta)
+	api.Get("/top-popular-blogs", middleware.OptionalJWTMiddleware(), deps.Blogs.Popular)
+
+	api.Get("/blogs/:id/meta", deps.Site.BlogMeta)
+
+	api.Get("/users/:username", middleware.OptionalJWTMiddleware(), deps.Profiles.Show)
+	api.Get("/users/:username/avatar", deps.Media.Avatar)
+	api.Get("/users/:username/followers", deps.Follows.Followers)
+	api.Get("/users/:username/following", deps.Follows.Following)
+
+	// Syndication feeds, :format is rss, atom or json
+	api.Get("/feeds/authors/:username/:format", deps.Site.AuthorFeed)
+	api.Get("/feeds/tags/:tag/:format", deps.Site.TagFeed)
+	api.Get("/feeds/:format", deps.Site.SiteFeed)
+
+	api.Get("/reading-lists/shared/:token", middleware.OptionalJWTMiddleware(), deps.ReadingLists.Shared)
 
 	// Protect blog routes with JWT middleware
 	protected := api.Group("/", middleware.JWTMiddleware())
 
-	protected.Get("/blogs", controller.BlogList)
-	protected.Get("/blogs/:id", controller.BlogFetch)
-	protected.Post("/blogs", controller.BlogCreate)
-	protected.Put("/blogs/:id", controller.BlogUpdate)
-	protected.Delete("/blogs/:id", controller.BlogDelete)
+	protected.Get("/blogs", deps.Blogs.List)
+	protected.Get("/blogs/:id", deps.Blogs.Fetch)
+	protected.Post("/blogs", deps.Blogs.Create)
+	protected.Put("/blogs/:id", deps.Blogs.Update)
+	protected.Patch("/blogs/:id", deps.Blogs.Patch)
+	protected.Delete("/blogs/:id", deps.Blogs.Delete)
 
 	// Blog comment and like routes
-	protected.Post("/blogs/:id/comments", controller.AddComment)
-	protected.Get("/blogs/:id/comments", controller.GetCommentsByBlogID)
+	protected.Post("/blogs/:id/comments", deps.Comments.Add)
+	protected.Get("/blogs/:id/comments", deps.Comments.List)
+	protected.Put("/blogs/:id/comments/:commentId", deps.Comments.Edit)
+	protected.Delete("/blogs/:id/comments/:commentId", deps.Comments.Delete)
+
+	protected.Post("/blogs/:id/likes", deps.Likes.Toggle)
+	protected.Get("/blogs/:id/likes", deps.Likes.Count)
+
+	protected.Get("/blogs-with-meta", deps.Blogs.ListWithMeta)
+
+	protected.Get("/me/analytics", deps.Analytics.Mine)
+
+	// Social graph and personalised feed
+	protected.Post("/users/:username/follow", deps.Follows.Follow)
+	protected.Delete("/users/:username/follow", deps.Follows.Unfollow)
+	protected.Get("/feed", deps.Feed.Feed)
+
+	// Media library
+	protected.Post("/media", deps.Media.Upload)
+	protected.Get("/me/media", deps.Media.List)
+	protected.Delete("/media/:id", deps.Media.Delete)
+
+	protected.Put("/blogs/:id/cover", deps.Media.SetCover)
+	protected.Delete("/blogs/:id/cover", deps.Media.RemoveCover)
+	protected.Put("/me/avatar", deps.Media.SetAvatar)
+	protected.Delete("/me/avatar", deps.Media.RemoveAvatar)
+	protected.Delete("/me", deps.Accounts.Delete)
 
-	protected.Post("/blogs/:id/likes", controller.LikeBlog)
-	protected.Get("/blogs/:id/likes", controller.GetLikesByBlogID)
+	// Bookmarks and reading lists
+	protected.Post("/blogs/:id/bookmark", deps.Bookmarks.Add)
+	protected.Delete("/blogs/:id/bookmark", deps.Bookmarks.Remove)
+	protected.Get("/me/bookmarks", deps.Bookmarks.List)
 
-	protected.Get("/blogs-with-meta", controller.BlogListWithMeta)
+	protected.Get("/me/reading-lists", deps.ReadingLists.List)
+	protected.Post("/me/reading-lists", deps.ReadingLists.Create)
+	protected.Get("/me/reading-lists/:listId", deps.ReadingLists.Get)
+	protected.Put("/me/reading-lists/:listId", deps.ReadingLists.Update)
+	protected.Delete("/me/reading-lists/:listId", deps.ReadingLists.Delete)
+	protected.Post("/me/reading-lists/:listId/items", deps.ReadingLists.AddItem)
+	protected.Delete("/me/reading-lists/:listId/items/:blogId", deps.ReadingLists.RemoveItem)
+	protected.Put("/me/reading-lists/:listId/order", deps.ReadingLists.Reorder)
 }
@@ -0,0 +1,22 @@
+#!/bin/sh
+# Runs the test suites against every supported database. SQLite needs
+# nothing; PostgreSQL and MySQL run against the servers in
+# TEST_POSTGRES_DSN and TEST_MYSQL_DSN, whose tables are dropped, and are
+# skipped when those are unset.
+#
+#   TEST_POSTGRES_DSN="host=localhost user=postgres dbname=gator_test sslmode=disable" \
+#   TEST_MYSQL_DSN="root:@tcp(localhost:3306)/gator_test?parseTime=True" \
+#   ./scripts/test_matrix.sh
+set -u
+cd "$(dirname "$0")/.."
+status=0
+
+run() {
+	echo "== $1"
+	TEST_DATABASE_DRIVER=$1 TEST_DATABASE_DSN=$2 go test -p 1 -count 1 ./... || status=1
+}
+
+run sqlite ""
+if [ -n "${TEST_POSTGRES_DSN:-}" ]; then run postgres "$TEST_POSTGRES_DSN"; else echo "== postgres skipped, TEST_POSTGRES_DSN is unset"; fi
+if [ -n "${TEST_MYSQL_DSN:-}" ]; then run mysql "$TEST_MYSQL_DSN"; else echo "== mysql skipped, TEST_MYSQL_DSN is unset"; fi
+exit $status
@@ -1,20 +1,48 @@
 package main
 
 import (
+	"Gator_blog/cache"
+	"Gator_blog/config"
+	"Gator_blog/container"
+	"Gator_blog/controller"
 	"Gator_blog/database"
+	"Gator_blog/middleware"
+	"Gator_blog/openapi"
+	"Gator_blog/problem"
 	"Gator_blog/redis"
 	"Gator_blog/router"
+	"Gator_blog/storage"
+	"Gator_blog/utils"
+	"Gator_blog/validate"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
-func init() {
-	database.ConnectDB()
-	redis.InitRedis()
-}
 func main() {
+	cfg, err := config.Load(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid configuration:", err)
+		os.Exit(2)
+	}
+	log.Printf("Configuration:\n%s", cfg)
+
+	database.ConnectDB(cfg.Database)
+	redis.InitRedis(cfg.Redis)
+	cache.InitCache(cfg.Cache, redis.RedisClient)
+	storage.InitStorage(cfg.Storage)
+	utils.InitEmail(cfg.SMTP)
+	middleware.InitJWT(cfg.JWT)
+	middleware.InitDeprecation(cfg.API)
+	validate.Init(cfg.Validation)
+	controller.InitViews(cfg.Analytics)
+	controller.SiteURL = cfg.Server.BaseURL
 
 	sqlDb, err := database.DBConn.DB()
 	if err != nil {
@@ -22,11 +50,34 @@ func main() {
 	}
 	defer sqlDb.Close()
 
-	app := fiber.New()
+	deps := container.New(database.DBConn, container.Infra{
+		Store:   redis.NewStore(redis.RedisClient),
+		Cache:   cache.Default,
+		Storage: storage.Default,
+	})
 
-	app.Use(cors.New())
+	// keep the like/comment/view counters in sync with the database
+	deps.Jobs.StartCounterSync(time.Minute, time.Hour)
+	deps.Jobs.StartLeaderboardRefresh(5 * time.Minute)
+	deps.Jobs.StartAnalyticsRollup(10 * time.Minute)
+	deps.Jobs.StartViewPruning(time.Hour, cfg.Analytics.ViewRetention)
+
+	// leave room for the multipart framing around the largest upload. Errors
+	// returned by handlers are answered as problem+json.
+	app := fiber.New(fiber.Config{
+		BodyLimit:    controller.MaxUploadSize + 1<<20,
+		ErrorHandler: problem.Handler,
+	})
+
+	// let the frontend read when v1 retires
+	app.Use(cors.New(cors.Config{ExposeHeaders: "Deprecation, Sunset, Link"}))
 	app.Use(logger.New())
-	router.SetupRoutes(app)
+	if cfg.Env == config.Development {
+		// refuse requests and log responses that depart from the OpenAPI
+		// document served at /api/openapi.json
+		app.Use(openapi.Validator(controller.APISpec(), nil))
+	}
+	router.SetupRoutes(app, deps)
 
-	app.Listen(":8000")
+	app.Listen(cfg.Server.Addr)
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AccountService struct {
+	accounts repository.AccountRepo
+}
+
+func NewAccountService(accounts repository.AccountRepo) *AccountService {
+	return &AccountService{accounts: accounts}
+}
+
+// Delete removes the account of user once password confirms it, see
+// repository.AccountRepo.Delete. What lives outside the database is
+// returned for the caller to clean up.
+func (s *AccountService) Delete(user *model.User, password string) (repository.DeletedAccount, error) {
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return repository.DeletedAccount{}, ErrIncorrectPassword
+	}
+	return s.accounts.Delete(user)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"time"
+)
+
+type AnalyticsService struct {
+	analytics repository.AnalyticsRepo
+}
+
+func NewAnalyticsService(analytics repository.AnalyticsRepo) *AnalyticsService {
+	return &AnalyticsService{analytics: analytics}
+}
+
+// Posts returns the id and title of every blog of userID, in id order
+func (s *AnalyticsService) Posts(userID uint) ([]model.Blog, error) {
+	return s.analytics.Posts(userID)
+}
+
+// DailyStats returns the rollups of blogIDs for the days from through to, by
+// day
+func (s *AnalyticsService) DailyStats(blogIDs []uint, from, to time.Time) ([]model.BlogDailyStat, error) {
+	if len(blogIDs) == 0 {
+		return nil, nil
+	}
+	return s.analytics.DailyStats(blogIDs, from.Format(model.DayLayout), to.Format(model.DayLayout))
+}
+
+// Readers counts the distinct readers of each of blogIDs from start until end
+func (s *AnalyticsService) Readers(blogIDs []uint, start, end time.Time) (map[uint]int64, error) {
+	if len(blogIDs) == 0 {
+		return map[uint]int64{}, nil
+	}
+	return s.analytics.Readers(blogIDs, start, end)
+}
+
+// AuthorReaders counts the distinct readers of any post of userID from start
+// until end, each once however many posts they read
+func (s *AnalyticsService) AuthorReaders(userID uint, start, end time.Time) (int64, error) {
+	return s.analytics.AuthorReaders(userID, start, end)
+}
+
+// Viewed reports whether viewer read blogID since the given time
+func (s *AnalyticsService) Viewed(blogID uint, viewer string, since time.Time) (bool, error) {
+	return s.analytics.Viewed(blogID, viewer, since)
+}
+
+// RecordView stores a view of a blog
+func (s *AnalyticsService) RecordView(view *model.BlogView) error {
+	return s.analytics.RecordView(view)
+}
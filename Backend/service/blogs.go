@@ -0,0 +1,172 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"errors"
+	"strings"
+)
+
+// maximum number of tags on a blog
+const MaxBlogTags = 10
+
+var (
+	ErrTooManyTags  = errors.New("too many tags")
+	ErrInvalidInput = errors.New("invalid input")
+)
+
+// BlogChanges are the fields of a blog its author may change, nil fields
+// are left as they are. Tags replace the current ones, an empty list
+// removes them all.
+type BlogChanges struct {
+	Title *string
+	Post  *string
+	Tags  []model.Tag
+}
+
+type BlogService struct {
+	blogs repository.BlogRepo
+}
+
+func NewBlogService(blogs repository.BlogRepo) *BlogService {
+	return &BlogService{blogs: blogs}
+}
+
+func (s *BlogService) List(filter repository.BlogFilter) ([]model.Blog, error) {
+	return s.blogs.List(filter)
+}
+
+// Get returns the blog with its tags
+func (s *BlogService) Get(id uint) (model.Blog, error) {
+	return s.blogs.ByID(id)
+}
+
+// Owned returns the blog id if userID wrote it
+func (s *BlogService) Owned(id, userID uint) (model.Blog, error) {
+	return s.blogs.Owned(id, userID)
+}
+
+// ByAuthors returns up to limit blogs of the authors older than the blog
+// before, newest first. A before of 0 starts at the newest blog.
+func (s *BlogService) ByAuthors(authorIDs []uint, before uint, limit int) ([]model.Blog, error) {
+	return s.blogs.ByAuthors(authorIDs, before, limit)
+}
+
+// LatestIDs returns the ids of the newest limit blogs of the authors
+func (s *BlogService) LatestIDs(authorIDs []uint, limit int) ([]uint, error) {
+	return s.blogs.LatestIDs(authorIDs, limit)
+}
+
+// Ranked returns the blogs out of ids that still exist, in the order of ids
+func (s *BlogService) Ranked(ids []uint) ([]model.Blog, error) {
+	found, err := s.blogs.ByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]model.Blog, len(found))
+	for _, blog := range found {
+		byID[blog.ID] = blog
+	}
+	blogs := make([]model.Blog, 0, len(found))
+	for _, id := range ids {
+		if blog, ok := byID[id]; ok {
+			blogs = append(blogs, blog)
+		}
+	}
+	return blogs, nil
+}
+
+// Create stores blog as written by author. Counters start at zero and the
+// cover is only set through its own endpoint.
+func (s *BlogService) Create(author model.User, blog *model.Blog) error {
+	blog.UserID = author.ID
+	blog.UserName = author.Username
+	blog.LikesCount, blog.CommentsCount, blog.ViewsCount = 0, 0, 0
+	blog.CoverMediaID = nil
+	tags, err := s.resolveTags(blog.Tags)
+	if err != nil {
+		return err
+	}
+	blog.Tags = tags
+	return s.blogs.Create(blog)
+}
+
+// Update applies changes to the blog id of userID and saves it. Unless
+// version is 0 the blog must still be at that version. When it is not, or
+// another update wins the race, Update fails with repository.ErrConflict
+// and returns the current blog.
+func (s *BlogService) Update(userID, id uint, version int64, changes BlogChanges) (model.Blog, error) {
+	blog, err := s.blogs.Owned(id, userID)
+	if err != nil {
+		return blog, err
+	}
+	if version != 0 && blog.Version != version {
+		return s.current(id)
+	}
+	if changes.Title != nil {
+		blog.Title = *changes.Title
+	}
+	if changes.Post != nil {
+		blog.Post = *changes.Post
+	}
+
+	var tags []model.Tag
+	if changes.Tags != nil {
+		if tags, err = s.resolveTags(changes.Tags); err != nil {
+			return blog, err
+		}
+	}
+	err = s.blogs.Update(&blog, tags)
+	if errors.Is(err, repository.ErrConflict) {
+		return s.current(id)
+	}
+	if err != nil {
+		return blog, err
+	}
+	if changes.Tags != nil {
+		blog.Tags = tags
+	}
+	return blog, nil
+}
+
+// returns the stored blog id along with repository.ErrConflict
+func (s *BlogService) current(id uint) (model.Blog, error) {
+	blog, err := s.blogs.ByID(id)
+	if err != nil {
+		return blog, err
+	}
+	return blog, repository.ErrConflict
+}
+
+// SetCover points the cover of blog at the upload mediaID, nil removes it
+func (s *BlogService) SetCover(blog *model.Blog, mediaID *uint) error {
+	return s.blogs.SetCover(blog, mediaID)
+}
+
+// Delete removes the blog id of userID and returns it
+func (s *BlogService) Delete(userID, id uint) (model.Blog, error) {
+	blog, err := s.blogs.Owned(id, userID)
+	if err != nil {
+		return blog, err
+	}
+	return blog, s.blogs.Delete(&blog)
+}
+
+// normalises tag names to lowercase, drops blanks and duplicates and looks
+// the tags up, creating the ones that do not exist yet
+func (s *BlogService) resolveTags(tags []model.Tag) ([]model.Tag, error) {
+	names := make([]string, 0, len(tags))
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		name := strings.ToLower(strings.TrimSpace(tag.Name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if len(seen) > MaxBlogTags {
+			return nil, ErrTooManyTags
+		}
+		names = append(names, name)
+	}
+	return s.blogs.Tags(names)
+}
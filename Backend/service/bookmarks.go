@@ -0,0 +1,58 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"errors"
+)
+
+type BookmarkService struct {
+	bookmarks repository.BookmarkRepo
+	blogs     repository.BlogRepo
+}
+
+func NewBookmarkService(bookmarks repository.BookmarkRepo, blogs repository.BlogRepo) *BookmarkService {
+	return &BookmarkService{bookmarks: bookmarks, blogs: blogs}
+}
+
+// Add bookmarks blogID for userID, failing with repository.ErrNotFound when
+// the blog does not exist. created is false when the bookmark was there.
+func (s *BookmarkService) Add(userID, blogID uint) (bookmark model.Bookmark, created bool, err error) {
+	if _, err := s.blogs.ByID(blogID); err != nil {
+		return bookmark, false, err
+	}
+	bookmark, err = s.bookmarks.Find(userID, blogID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return bookmark, false, err
+	}
+	bookmark = model.Bookmark{UserID: userID, BlogID: blogID}
+	return bookmark, true, s.bookmarks.Create(&bookmark)
+}
+
+// Remove takes the bookmark of userID off blogID and reports whether there
+// was one
+func (s *BookmarkService) Remove(userID, blogID uint) (bool, error) {
+	return s.bookmarks.Delete(userID, blogID)
+}
+
+// Blogs returns the blogs userID bookmarked, the latest bookmark first
+func (s *BookmarkService) Blogs(userID uint) ([]model.Blog, error) {
+	return s.bookmarks.Blogs(userID)
+}
+
+// Bookmarked returns the set of blogIDs that userID bookmarked, empty for
+// the anonymous user 0
+func (s *BookmarkService) Bookmarked(userID uint, blogIDs []uint) (map[uint]bool, error) {
+	bookmarked := map[uint]bool{}
+	if userID == 0 {
+		return bookmarked, nil
+	}
+	ids, err := s.bookmarks.Bookmarked(userID, blogIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
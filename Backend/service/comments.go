@@ -0,0 +1,81 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"errors"
+	"strings"
+)
+
+type CommentService struct {
+	comments repository.CommentRepo
+	blogs    repository.BlogRepo
+}
+
+func NewCommentService(comments repository.CommentRepo, blogs repository.BlogRepo) *CommentService {
+	return &CommentService{comments: comments, blogs: blogs}
+}
+
+// Add stores comment on its blog, failing with repository.ErrNotFound when
+// the blog does not exist
+func (s *CommentService) Add(comment *model.Comment) error {
+	if strings.TrimSpace(comment.Content) == "" {
+		return ErrInvalidInput
+	}
+	if _, err := s.blogs.ByID(comment.BlogID); err != nil {
+		return err
+	}
+	return s.comments.Create(comment)
+}
+
+// ByBlog returns the comments on a blog, never nil
+func (s *CommentService) ByBlog(blogID uint) ([]model.Comment, error) {
+	return s.comments.ByBlog(blogID)
+}
+
+// Latest returns the latest limit comments of each of blogIDs, oldest first
+// within a blog
+func (s *CommentService) Latest(blogIDs []uint, limit int) ([]model.Comment, error) {
+	return s.comments.Latest(blogIDs, limit)
+}
+
+// CountByBlog counts the comments of each of blogIDs
+func (s *CommentService) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	return s.comments.CountByBlog(blogIDs)
+}
+
+// Edit replaces the content of the comment id on blogID if userID wrote it.
+// Unless version is 0 the comment must still be at that version, otherwise
+// Edit fails with repository.ErrConflict and returns the current comment.
+func (s *CommentService) Edit(userID, blogID, id uint, version int64, content string) (model.Comment, error) {
+	if strings.TrimSpace(content) == "" {
+		return model.Comment{}, ErrInvalidInput
+	}
+	comment, err := s.comments.Owned(id, blogID, userID)
+	if err != nil {
+		return comment, err
+	}
+	if version != 0 && comment.Version != version {
+		return comment, repository.ErrConflict
+	}
+	edited := comment
+	edited.Content = content
+	err = s.comments.Update(&edited)
+	if errors.Is(err, repository.ErrConflict) {
+		comment, err = s.comments.Owned(id, blogID, userID)
+		if err != nil {
+			return comment, err
+		}
+		return comment, repository.ErrConflict
+	}
+	return edited, err
+}
+
+// Delete removes the comment id on blogID if userID wrote it and returns it
+func (s *CommentService) Delete(userID, blogID, id uint) (model.Comment, error) {
+	comment, err := s.comments.Owned(id, blogID, userID)
+	if err != nil {
+		return comment, err
+	}
+	return comment, s.comments.Delete(&comment)
+}
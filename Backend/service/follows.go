@@ -0,0 +1,86 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"errors"
+)
+
+var ErrFollowSelf = errors.New("cannot follow yourself")
+
+type FollowService struct {
+	follows repository.FollowRepo
+	users   repository.UserRepo
+}
+
+func NewFollowService(follows repository.FollowRepo, users repository.UserRepo) *FollowService {
+	return &FollowService{follows: follows, users: users}
+}
+
+// Follow makes follower follow the user called username. created is false
+// when follower already did, the returned follow is empty then.
+func (s *FollowService) Follow(follower model.User, username string) (follow model.Follow, created bool, err error) {
+	followee, err := s.users.ByUsername(username)
+	if err != nil {
+		return follow, false, err
+	}
+	if followee.ID == follower.ID {
+		return follow, false, ErrFollowSelf
+	}
+	exists, err := s.follows.Exists(follower.ID, followee.ID)
+	if err != nil || exists {
+		return follow, false, err
+	}
+	follow = model.Follow{FollowerID: follower.ID, FolloweeID: followee.ID}
+	return follow, true, s.follows.Create(&follow)
+}
+
+// Unfollow stops follower following the user called username and reports
+// whether they did
+func (s *FollowService) Unfollow(follower model.User, username string) (bool, error) {
+	followee, err := s.users.ByUsername(username)
+	if err != nil {
+		return false, err
+	}
+	return s.follows.Delete(follower.ID, followee.ID)
+}
+
+// Followers returns how many users follow the user called username and a
+// page of them, see repository.FollowRepo.ListFollowers
+func (s *FollowService) Followers(username string, cursor uint, limit int) (int64, []repository.FollowedUser, error) {
+	user, err := s.users.ByUsername(username)
+	if err != nil {
+		return 0, nil, err
+	}
+	count, err := s.follows.CountFollowers(user.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	users, err := s.follows.ListFollowers(user.ID, cursor, limit)
+	return count, users, err
+}
+
+// Following returns how many users the user called username follows and a
+// page of them
+func (s *FollowService) Following(username string, cursor uint, limit int) (int64, []repository.FollowedUser, error) {
+	user, err := s.users.ByUsername(username)
+	if err != nil {
+		return 0, nil, err
+	}
+	count, err := s.follows.CountFollowing(user.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	users, err := s.follows.ListFollowing(user.ID, cursor, limit)
+	return count, users, err
+}
+
+// Followees returns the ids of the users userID follows
+func (s *FollowService) Followees(userID uint) ([]uint, error) {
+	return s.follows.Followees(userID)
+}
+
+// FollowerIDs returns the ids of the users following userID
+func (s *FollowService) FollowerIDs(userID uint) ([]uint, error) {
+	return s.follows.Followers(userID)
+}
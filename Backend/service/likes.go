@@ -0,0 +1,43 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+)
+
+type LikeService struct {
+	likes repository.LikeRepo
+	blogs repository.BlogRepo
+}
+
+func NewLikeService(likes repository.LikeRepo, blogs repository.BlogRepo) *LikeService {
+	return &LikeService{likes: likes, blogs: blogs}
+}
+
+// Toggle likes blogID for userID, or takes the like back when there is one.
+// liked reports which of the two happened.
+func (s *LikeService) Toggle(userID, blogID uint) (like model.Like, liked bool, err error) {
+	if _, err := s.blogs.ByID(blogID); err != nil {
+		return like, false, err
+	}
+	removed, err := s.likes.Delete(userID, blogID)
+	if err != nil || removed {
+		return like, false, err
+	}
+	like = model.Like{UserID: userID, BlogID: blogID}
+	return like, true, s.likes.Create(&like)
+}
+
+// Count returns the number of likes of blogID, ErrNotFound when there is no
+// such blog
+func (s *LikeService) Count(blogID uint) (int64, error) {
+	if _, err := s.blogs.ByID(blogID); err != nil {
+		return 0, err
+	}
+	return s.likes.Count(blogID)
+}
+
+// CountByBlog counts the likes of each of blogIDs
+func (s *LikeService) CountByBlog(blogIDs []uint) (map[uint]int64, error) {
+	return s.likes.CountByBlog(blogIDs)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+)
+
+// MediaService keeps the records of uploads, the stored files are left to
+// the caller
+type MediaService struct {
+	media repository.MediaRepo
+}
+
+func NewMediaService(media repository.MediaRepo) *MediaService {
+	return &MediaService{media: media}
+}
+
+// ByID returns the upload with its variants
+func (s *MediaService) ByID(id uint) (model.Media, error) {
+	return s.media.ByID(id)
+}
+
+// Owned returns the upload with its variants if userID made it for purpose
+func (s *MediaService) Owned(id, userID uint, purpose string) (model.Media, error) {
+	return s.media.Owned(id, userID, purpose)
+}
+
+// List pages through the uploads of userID for purpose, newest first
+func (s *MediaService) List(userID uint, purpose string, cursor uint, limit int) ([]model.Media, error) {
+	return s.media.List(userID, purpose, cursor, limit)
+}
+
+// Create records the upload with its variants
+func (s *MediaService) Create(media *model.Media) error {
+	return s.media.Create(media)
+}
+
+// Delete removes the records of the upload and its variants
+func (s *MediaService) Delete(media *model.Media) error {
+	return s.media.Delete(media)
+}
+
+// Variants returns the variants of the uploads mediaIDs grouped by upload
+func (s *MediaService) Variants(mediaIDs []uint) (map[uint][]model.MediaVariant, error) {
+	grouped := map[uint][]model.MediaVariant{}
+	if len(mediaIDs) == 0 {
+		return grouped, nil
+	}
+	variants, err := s.media.Variants(mediaIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, variant := range variants {
+		grouped[variant.MediaID] = append(grouped[variant.MediaID], variant)
+	}
+	return grouped, nil
+}
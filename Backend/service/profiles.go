@@ -0,0 +1,16 @@
+package service
+
+import "Gator_blog/repository"
+
+type ProfileService struct {
+	profiles repository.ProfileRepo
+}
+
+func NewProfileService(profiles repository.ProfileRepo) *ProfileService {
+	return &ProfileService{profiles: profiles}
+}
+
+// Counts returns the posts, likes received and follows of userID
+func (s *ProfileService) Counts(userID uint) (repository.ProfileCounts, error) {
+	return s.profiles.Counts(userID)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// maximum number of reading lists per user
+const MaxReadingLists = 100
+
+var (
+	ErrTooManyLists = errors.New("too many reading lists")
+	ErrInvalidOrder = errors.New("order must list every blog of the reading list once")
+)
+
+type ReadingListService struct {
+	lists repository.ReadingListRepo
+	blogs repository.BlogRepo
+}
+
+func NewReadingListService(lists repository.ReadingListRepo, blogs repository.BlogRepo) *ReadingListService {
+	return &ReadingListService{lists: lists, blogs: blogs}
+}
+
+// ByUser returns the lists of userID in the order they were made
+func (s *ReadingListService) ByUser(userID uint) ([]model.ReadingList, error) {
+	return s.lists.ByUser(userID)
+}
+
+// Owned returns the list id if userID made it
+func (s *ReadingListService) Owned(id, userID uint) (model.ReadingList, error) {
+	return s.lists.Owned(id, userID)
+}
+
+// Shared returns the public list with the share token
+func (s *ReadingListService) Shared(token string) (model.ReadingList, error) {
+	return s.lists.Shared(token)
+}
+
+// Create makes a list for userID, public with a share token if asked.
+// Nobody has more than MaxReadingLists.
+func (s *ReadingListService) Create(userID uint, name string, public bool) (model.ReadingList, error) {
+	count, err := s.lists.Count(userID)
+	if err != nil {
+		return model.ReadingList{}, err
+	}
+	if count >= MaxReadingLists {
+		return model.ReadingList{}, ErrTooManyLists
+	}
+	list := model.ReadingList{UserID: userID, Name: strings.TrimSpace(name)}
+	if public {
+		if err := share(&list); err != nil {
+			return list, err
+		}
+	}
+	return list, s.lists.Create(&list)
+}
+
+// Update renames list or changes whether it is shared, nil fields are left
+// as they are. Making a list private revokes its share token, sharing it
+// again issues a new one.
+func (s *ReadingListService) Update(list *model.ReadingList, name *string, public *bool) error {
+	if name != nil {
+		list.Name = strings.TrimSpace(*name)
+	}
+	if public != nil && *public != list.Public {
+		if *public {
+			if err := share(list); err != nil {
+				return err
+			}
+		} else {
+			list.Public = false
+			list.ShareToken = nil
+		}
+	}
+	return s.lists.Save(list)
+}
+
+// Delete removes list with its items
+func (s *ReadingListService) Delete(list *model.ReadingList) error {
+	return s.lists.Delete(list)
+}
+
+// Blogs returns the blogs of the list in list order
+func (s *ReadingListService) Blogs(listID uint) ([]model.Blog, error) {
+	return s.lists.Blogs(listID)
+}
+
+// Add puts blogID at the end of list, failing with repository.ErrNotFound
+// when the blog does not exist. created is false when it was on the list.
+func (s *ReadingListService) Add(list model.ReadingList, blogID uint) (item model.ReadingListItem, created bool, err error) {
+	if _, err := s.blogs.ByID(blogID); err != nil {
+		return item, false, err
+	}
+	item, err = s.lists.Item(list.ID, blogID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return item, false, err
+	}
+	item = model.ReadingListItem{ReadingListID: list.ID, BlogID: blogID}
+	return item, true, s.lists.Append(&item)
+}
+
+// Remove takes blogID off list and reports whether it was on it
+func (s *ReadingListService) Remove(list model.ReadingList, blogID uint) (bool, error) {
+	return s.lists.Remove(list.ID, blogID)
+}
+
+// Reorder puts the blogs of list in the order of blogIDs, which must name
+// each of them exactly once
+func (s *ReadingListService) Reorder(list model.ReadingList, blogIDs []uint) error {
+	items, err := s.lists.Items(list.ID)
+	if err != nil {
+		return err
+	}
+	positions := make(map[uint]int, len(blogIDs))
+	for i, id := range blogIDs {
+		positions[id] = i + 1
+	}
+	if len(positions) != len(items) || len(blogIDs) != len(items) {
+		return ErrInvalidOrder
+	}
+	for _, item := range items {
+		if _, ok := positions[item.BlogID]; !ok {
+			return ErrInvalidOrder
+		}
+	}
+	return s.lists.Reorder(items, positions)
+}
+
+// marks a list public with a fresh, unguessable share token
+func share(list *model.ReadingList) error {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+	list.Public = true
+	list.ShareToken = &token
+	return nil
+}
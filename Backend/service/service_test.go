@@ -0,0 +1,201 @@
+package service_test
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"Gator_blog/repository/memory"
+	"Gator_blog/service"
+	"Gator_blog/validate"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignUpAndSignIn(t *testing.T) {
+	users := service.NewUserService(memory.NewUsers(), nil)
+
+	user := model.User{Username: "alice", Email: "alice@example.com", Password: "secret"}
+	assert.NoError(t, users.SignUp(&user))
+	assert.NotEqual(t, "secret", user.Password)
+
+	assert.ErrorIs(t, users.SignUp(&model.User{Username: "other", Email: "alice@example.com", Password: "x"}), service.ErrEmailTaken)
+	assert.ErrorIs(t, users.SignUp(&model.User{Username: "alice", Email: "other@example.com", Password: "x"}), service.ErrUsernameTaken)
+
+	signedIn, err := users.SignIn("alice@example.com", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, signedIn.ID)
+
+	_, err = users.SignIn("alice@example.com", "wrong")
+	assert.ErrorIs(t, err, service.ErrIncorrectPassword)
+	_, err = users.SignIn("bob@example.com", "secret")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestPasswordsTooLongToHash(t *testing.T) {
+	users := service.NewUserService(memory.NewUsers(), nil)
+	long := strings.Repeat("é", 40) + "1"
+
+	err := users.SignUp(&model.User{Username: "alice", Email: "alice@example.com", Password: long})
+	var errs validate.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, validate.Errors{{Field: "password", Rule: "max", Message: "password must be at most 72 bytes"}}, errs)
+	_, err = users.SignIn("alice@example.com", long)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.NoError(t, users.SignUp(&model.User{Username: "alice", Email: "alice@example.com", Password: "secret"}))
+	err = users.ResetPassword("alice@example.com", long)
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "new_password", errs[0].Field)
+}
+
+func TestPasswordReset(t *testing.T) {
+	sent := map[string]string{}
+	users := service.NewUserService(memory.NewUsers(), func(to, code string) error {
+		sent[to] = code
+		return nil
+	})
+	assert.NoError(t, users.SignUp(&model.User{Username: "alice", Email: "alice@example.com", Password: "old"}))
+
+	assert.NoError(t, users.RequestResetCode("alice@example.com"))
+	code := sent["alice@example.com"]
+	assert.Len(t, code, 6)
+
+	assert.ErrorIs(t, users.VerifyResetCode("alice@example.com", "nope"), service.ErrInvalidCode)
+	assert.NoError(t, users.VerifyResetCode("alice@example.com", code))
+	// codes only work once
+	assert.ErrorIs(t, users.VerifyResetCode("alice@example.com", code), service.ErrInvalidCode)
+
+	assert.NoError(t, users.ResetPassword("alice@example.com", "new"))
+	_, err := users.SignIn("alice@example.com", "new")
+	assert.NoError(t, err)
+
+	failing := service.NewUserService(memory.NewUsers(), func(to, code string) error { return errors.New("smtp down") })
+	assert.NoError(t, failing.SignUp(&model.User{Username: "bob", Email: "bob@example.com", Password: "pw"}))
+	assert.Error(t, failing.RequestResetCode("bob@example.com"))
+	assert.ErrorIs(t, failing.RequestResetCode("carol@example.com"), repository.ErrNotFound)
+}
+
+func TestBlogLifecycle(t *testing.T) {
+	blogs := service.NewBlogService(memory.NewBlogs())
+	author := model.User{ID: 1, Username: "alice"}
+	cover := uint(7)
+
+	blog := model.Blog{Title: "Hello", Post: "World", LikesCount: 5, CoverMediaID: &cover,
+		Tags: []model.Tag{{Name: " Go "}, {Name: "go"}, {Name: ""}, {Name: "Web"}}}
+	assert.NoError(t, blogs.Create(author, &blog))
+	assert.Equal(t, "alice", blog.UserName)
+	assert.Zero(t, blog.LikesCount)
+	assert.Nil(t, blog.CoverMediaID)
+	assert.Equal(t, []string{"go", "web"}, []string{blog.Tags[0].Name, blog.Tags[1].Name})
+
+	// only the author may change it, the tags stay unless the update sets them
+	title := "Hello again"
+	_, err := blogs.Update(2, blog.ID, 0, service.BlogChanges{Title: &title})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	updated, err := blogs.Update(author.ID, blog.ID, 0, service.BlogChanges{Title: &title})
+	assert.NoError(t, err)
+	assert.Equal(t, author.ID, updated.UserID)
+	assert.Equal(t, "World", updated.Post)
+	assert.Len(t, updated.Tags, 2)
+	stored, _ := blogs.Get(blog.ID)
+	assert.Equal(t, "Hello again", stored.Title)
+	assert.Len(t, stored.Tags, 2)
+
+	// an update made on an older version gets the current blog back
+	lost := "Lost update"
+	current, err := blogs.Update(author.ID, blog.ID, 1, service.BlogChanges{Title: &lost})
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	assert.Equal(t, "Hello again", current.Title)
+	assert.Equal(t, int64(2), current.Version)
+	_, err = blogs.Update(author.ID, blog.ID, 2, service.BlogChanges{})
+	assert.NoError(t, err)
+	stored, _ = blogs.Get(blog.ID)
+	assert.Equal(t, int64(3), stored.Version)
+
+	many := make([]model.Tag, service.MaxBlogTags+1)
+	for i := range many {
+		many[i].Name = fmt.Sprint("tag", i)
+	}
+	_, err = blogs.Update(author.ID, blog.ID, 0, service.BlogChanges{Tags: many})
+	assert.ErrorIs(t, err, service.ErrTooManyTags)
+
+	// an empty list removes the tags
+	_, err = blogs.Update(author.ID, blog.ID, 0, service.BlogChanges{Tags: []model.Tag{}})
+	assert.NoError(t, err)
+	stored, _ = blogs.Get(blog.ID)
+	assert.Empty(t, stored.Tags)
+
+	_, err = blogs.Delete(2, blog.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = blogs.Delete(author.ID, blog.ID)
+	assert.NoError(t, err)
+	_, err = blogs.Get(blog.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestRankedKeepsOrder(t *testing.T) {
+	blogs := service.NewBlogService(memory.NewBlogs())
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		blog := model.Blog{Title: fmt.Sprint("Blog ", i)}
+		blogs.Create(model.User{ID: 1}, &blog)
+		ids = append(ids, blog.ID)
+	}
+
+	ranked, err := blogs.Ranked([]uint{ids[2], 99, ids[0]})
+	assert.NoError(t, err)
+	assert.Len(t, ranked, 2)
+	assert.Equal(t, ids[2], ranked[0].ID)
+	assert.Equal(t, ids[0], ranked[1].ID)
+}
+
+func TestCommentsAndLikes(t *testing.T) {
+	blogRepo := memory.NewBlogs()
+	blog := model.Blog{Title: "Hello", UserID: 1}
+	blogRepo.Create(&blog)
+
+	comments := service.NewCommentService(memory.NewComments(), blogRepo)
+	comment := model.Comment{Content: "Nice", UserID: 2, BlogID: blog.ID}
+	assert.NoError(t, comments.Add(&comment))
+	assert.ErrorIs(t, comments.Add(&model.Comment{Content: "Nowhere", UserID: 2, BlogID: 99}), repository.ErrNotFound)
+	assert.ErrorIs(t, comments.Add(&model.Comment{Content: " ", UserID: 2, BlogID: blog.ID}), service.ErrInvalidInput)
+	edited, err := comments.Edit(2, blog.ID, comment.ID, 1, "Very nice")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), edited.Version)
+	current, err := comments.Edit(2, blog.ID, comment.ID, 1, "Lost edit")
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	assert.Equal(t, "Very nice", current.Content)
+	_, err = comments.Edit(2, blog.ID, comment.ID, 0, " ")
+	assert.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = comments.Edit(3, blog.ID, comment.ID, 0, "Not mine")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	_, err = comments.Delete(3, blog.ID, comment.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = comments.Delete(2, blog.ID, comment.ID)
+	assert.NoError(t, err)
+	left, _ := comments.ByBlog(blog.ID)
+	assert.Empty(t, left)
+	assert.NotNil(t, left)
+
+	likes := service.NewLikeService(memory.NewLikes(), blogRepo)
+	_, liked, err := likes.Toggle(2, blog.ID)
+	assert.NoError(t, err)
+	assert.True(t, liked)
+	count, _ := likes.Count(blog.ID)
+	assert.Equal(t, int64(1), count)
+
+	_, liked, err = likes.Toggle(2, blog.ID)
+	assert.NoError(t, err)
+	assert.False(t, liked)
+	count, _ = likes.Count(blog.ID)
+	assert.Zero(t, count)
+
+	_, _, err = likes.Toggle(2, 99)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = likes.Count(99)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+)
+
+// SiteService reads the public site for sitemaps and syndication feeds
+type SiteService struct {
+	site repository.SiteRepo
+}
+
+func NewSiteService(site repository.SiteRepo) *SiteService {
+	return &SiteService{site: site}
+}
+
+// Counts returns the number of posts and of authors with posts
+func (s *SiteService) Counts() (posts, authors int64, err error) {
+	return s.site.Counts()
+}
+
+// Posts returns the id and update time of limit posts from offset
+func (s *SiteService) Posts(offset, limit int) ([]model.Blog, error) {
+	return s.site.Posts(offset, limit)
+}
+
+// Authors returns limit authors with posts from offset with the time of
+// their newest post
+func (s *SiteService) Authors(offset, limit int) ([]repository.SitemapAuthor, error) {
+	return s.site.Authors(offset, limit)
+}
+
+// Tag returns the tag called name
+func (s *SiteService) Tag(name string) (model.Tag, error) {
+	return s.site.Tag(name)
+}
+
+// Newest returns the newest limit blogs of the filter with their tags
+func (s *SiteService) Newest(filter repository.FeedFilter, limit int) ([]model.Blog, error) {
+	return s.site.Newest(filter, limit)
+}
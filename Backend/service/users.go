@@ -0,0 +1,145 @@
+// Package service holds the business rules of the blog on top of the
+// repositories, free of HTTP and of package level connections.
+package service
+
+import (
+	"Gator_blog/model"
+	"Gator_blog/repository"
+	"Gator_blog/validate"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/exp/rand"
+)
+
+// how long a password reset code stays valid
+const ResetCodeTTL = 10 * time.Minute
+
+var (
+	ErrEmailTaken        = errors.New("email already registered")
+	ErrUsernameTaken     = errors.New("username already taken")
+	ErrIncorrectPassword = errors.New("incorrect password")
+	ErrInvalidCode       = errors.New("invalid or expired code")
+)
+
+// Mailer delivers a password reset code to an email address
+type Mailer func(to, code string) error
+
+type UserService struct {
+	users repository.UserRepo
+	mail  Mailer
+}
+
+func NewUserService(users repository.UserRepo, mail Mailer) *UserService {
+	return &UserService{users: users, mail: mail}
+}
+
+// ByEmail returns the user signed in with email
+func (s *UserService) ByEmail(email string) (model.User, error) {
+	return s.users.ByEmail(email)
+}
+
+// ByUsername returns the user called username
+func (s *UserService) ByUsername(username string) (model.User, error) {
+	return s.users.ByUsername(username)
+}
+
+// SetAvatar points the avatar of user at the upload mediaID, nil removes it
+func (s *UserService) SetAvatar(user *model.User, mediaID *uint) error {
+	return s.users.SetAvatar(user, mediaID)
+}
+
+// SignIn returns the user if password matches the stored hash
+func (s *UserService) SignIn(email, password string) (model.User, error) {
+	user, err := s.users.ByEmail(email)
+	if err != nil {
+		return user, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return user, ErrIncorrectPassword
+	}
+	return user, nil
+}
+
+// SignUp stores user with its password hashed
+func (s *UserService) SignUp(user *model.User) error {
+	if _, err := s.users.ByEmail(user.Email); !errors.Is(err, repository.ErrNotFound) {
+		if err == nil {
+			return ErrEmailTaken
+		}
+		return err
+	}
+	if _, err := s.users.ByUsername(user.Username); !errors.Is(err, repository.ErrNotFound) {
+		if err == nil {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+
+	hashed, err := hashPassword(user.Password, "password")
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	user.ResetCodeExpiry = time.Now()
+	user.AvatarMediaID = nil
+	return s.users.Create(user)
+}
+
+// RequestResetCode stores a new reset code for the user and mails it
+func (s *UserService) RequestResetCode(email string) error {
+	user, err := s.users.ByEmail(email)
+	if err != nil {
+		return err
+	}
+	code := fmt.Sprintf("%06d", rand.Intn(1000000))
+	user.ResetCode = code
+	user.ResetCodeExpiry = time.Now().Add(ResetCodeTTL)
+	if err := s.users.Save(&user); err != nil {
+		return err
+	}
+	return s.mail(user.Email, code)
+}
+
+// VerifyResetCode checks code against the one last sent and clears it
+func (s *UserService) VerifyResetCode(email, code string) error {
+	user, err := s.users.ByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user.ResetCode != code || time.Now().After(user.ResetCodeExpiry) {
+		return ErrInvalidCode
+	}
+	user.ResetCode = ""
+	return s.users.Save(&user)
+}
+
+// ResetPassword replaces the password of the user
+func (s *UserService) ResetPassword(email, password string) error {
+	user, err := s.users.ByEmail(email)
+	if err != nil {
+		return err
+	}
+	hashed, err := hashPassword(password, "new_password")
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	return s.users.Save(&user)
+}
+
+// hashes password, a password bcrypt cannot take being the fault of the
+// request field it came in
+func hashPassword(password, field string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return "", validate.Errors{{Field: field, Rule: "max",
+			Message: field + " must be at most 72 bytes"}}
+	}
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hashed), nil
+}
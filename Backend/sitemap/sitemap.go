@@ -0,0 +1,75 @@
+// Package sitemap renders sitemaps and sitemap indexes following the
+// sitemaps.org protocol.
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the most URLs a single sitemap may list, larger sites are split
+// into several sitemaps referenced from an index
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is one page of the site
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// Pages returns how many sitemaps total URLs are split into
+func Pages(total int64) int {
+	if total <= 0 {
+		return 1
+	}
+	return int((total + MaxURLs - 1) / MaxURLs)
+}
+
+type urlSet struct {
+	XMLName xml.Name  `xml:"urlset"`
+	NS      string    `xml:"xmlns,attr"`
+	URLs    []xmlLink `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name  `xml:"sitemapindex"`
+	NS       string    `xml:"xmlns,attr"`
+	Sitemaps []xmlLink `xml:"sitemap"`
+}
+
+type xmlLink struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// RenderURLSet renders a sitemap listing urls
+func RenderURLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{NS: namespace, URLs: links(urls)})
+}
+
+// RenderIndex renders a sitemap index pointing at the given sitemaps, LastMod
+// being the newest change within each
+func RenderIndex(sitemaps []URL) ([]byte, error) {
+	return marshal(sitemapIndex{NS: namespace, Sitemaps: links(sitemaps)})
+}
+
+func links(urls []URL) []xmlLink {
+	out := make([]xmlLink, len(urls))
+	for i, u := range urls {
+		out[i] = xmlLink{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			out[i].LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+	}
+	return out
+}
+
+func marshal(doc interface{}) ([]byte, error) {
+	body, err := xml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,34 @@
+package sitemap_test
+
+import (
+	"Gator_blog/sitemap"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPages(t *testing.T) {
+	assert.Equal(t, 1, sitemap.Pages(0))
+	assert.Equal(t, 1, sitemap.Pages(sitemap.MaxURLs))
+	assert.Equal(t, 2, sitemap.Pages(sitemap.MaxURLs+1))
+	assert.Equal(t, 3, sitemap.Pages(2*sitemap.MaxURLs+1))
+}
+
+func TestRender(t *testing.T) {
+	body, err := sitemap.RenderURLSet([]sitemap.URL{
+		{Loc: "http://example.com/post/1?a=1&b=2", LastMod: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Loc: "http://example.com/users/alice"},
+	})
+	assert.NoError(t, err)
+	doc := string(body)
+	assert.True(t, strings.HasPrefix(doc, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, doc, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+	assert.Contains(t, doc, `<loc>http://example.com/post/1?a=1&amp;b=2</loc><lastmod>2024-03-01T12:00:00Z</lastmod>`)
+	assert.Contains(t, doc, `<url><loc>http://example.com/users/alice</loc></url>`)
+
+	body, err = sitemap.RenderIndex([]sitemap.URL{{Loc: "http://example.com/sitemaps/1.xml"}})
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>http://example.com/sitemaps/1.xml</loc></sitemap></sitemapindex>`)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores objects as files below Root, served under the BaseURL path
+type Local struct {
+	Root    string
+	BaseURL string
+}
+
+// NewLocal returns a storage writing below root whose files are served from
+// the baseURL path
+func NewLocal(root, baseURL string) *Local {
+	return &Local{Root: root, BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (l *Local) path(key string) string {
+	return filepath.Join(l.Root, filepath.FromSlash(key))
+}
+
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+	path := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// write to a temporary file first so readers never see a partial object
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !validKey(key) {
+		return nil, errInvalidKey
+	}
+	f, err := os.Open(l.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+	err := os.Remove(l.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) URL(key string) string {
+	return l.BaseURL + "/" + key
+}
@@ -0,0 +1,70 @@
+// Package storage keeps uploaded files on the local filesystem or in an
+// S3-compatible object store behind a common interface.
+package storage
+
+import (
+	"Gator_blog/config"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+)
+
+// ErrNotFound is returned when a key does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores objects under slash separated keys
+type Storage interface {
+	// Put stores size bytes from r under key
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object under key, deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// URL returns the address clients fetch the object from
+	URL(key string) string
+}
+
+// Default is the storage uploads are written to
+var Default Storage
+
+// function to initialise the upload storage, S3 when the driver is s3 and
+// the local filesystem otherwise
+func InitStorage(cfg config.StorageConfig) {
+	if cfg.Driver != "s3" {
+		Default = NewLocal(cfg.Root, "/uploads")
+		log.Println("Storing uploads in", cfg.Root)
+		return
+	}
+
+	s3, err := NewS3(S3Config{
+		Endpoint:  cfg.S3.Endpoint,
+		Region:    cfg.S3.Region,
+		Bucket:    cfg.S3.Bucket,
+		AccessKey: cfg.S3.AccessKey,
+		SecretKey: cfg.S3.SecretKey,
+		UseSSL:    cfg.S3.UseSSL,
+		PublicURL: cfg.S3.PublicURL,
+	})
+	if err != nil {
+		panic("Failed to set up S3 storage: " + err.Error())
+	}
+	Default = s3
+	log.Println("Storing uploads in bucket", cfg.S3.Bucket)
+}
+
+// rejects keys that could escape the storage root
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+var errInvalidKey = errors.New("invalid storage key")
@@ -0,0 +1,63 @@
+package storage_test
+
+import (
+	"Gator_blog/storage"
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/assert"
+)
+
+// runs the same checks against every backend
+func testStorage(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	data := []byte("hello storage")
+
+	assert.NoError(t, s.Put(ctx, "media/1/a.txt", bytes.NewReader(data), int64(len(data)), "text/plain"))
+	r, err := s.Get(ctx, "media/1/a.txt")
+	assert.NoError(t, err)
+	got, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, data, got)
+
+	assert.NoError(t, s.Delete(ctx, "media/1/a.txt"))
+	_, err = s.Get(ctx, "media/1/a.txt")
+	assert.Equal(t, storage.ErrNotFound, err)
+	assert.NoError(t, s.Delete(ctx, "media/1/a.txt"))
+
+	for _, key := range []string{"", "/abs", "../escape", "media/../../escape", "media//x"} {
+		assert.Error(t, s.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "text/plain"), key)
+	}
+	assert.True(t, strings.HasSuffix(s.URL("media/1/a.txt"), "/media/1/a.txt"))
+}
+
+func TestLocal(t *testing.T) {
+	s := storage.NewLocal(t.TempDir(), "/uploads/")
+	testStorage(t, s)
+	assert.Equal(t, "/uploads/media/1/a.txt", s.URL("media/1/a.txt"))
+}
+
+func TestS3(t *testing.T) {
+	backend := s3mem.New()
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+	assert.NoError(t, backend.CreateBucket("gator"))
+
+	s, err := storage.NewS3(storage.S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Region:    "us-east-1",
+		Bucket:    "gator",
+		AccessKey: "key",
+		SecretKey: "secret",
+		PublicURL: "https://cdn.example.com/",
+	})
+	assert.NoError(t, err)
+	testStorage(t, s)
+	assert.Equal(t, "https://cdn.example.com/media/1/a.txt", s.URL("media/1/a.txt"))
+}
@@ -0,0 +1,280 @@
+// Package syndication renders lists of posts as RSS 2.0, Atom 1.0 and
+// JSON Feed 1.1 documents.
+package syndication
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"time"
+)
+
+// Format is one of the supported feed formats
+type Format string
+
+const (
+	RSS      Format = "rss"
+	Atom     Format = "atom"
+	JSONFeed Format = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// ParseFormat validates a format name from a request
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case RSS, Atom, JSONFeed:
+		return f, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// ContentType is the media type a feed of this format is served with
+func (f Format) ContentType() string {
+	return f.mediaType() + "; charset=utf-8"
+}
+
+// Feed is the format independent description of a feed
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // page the feed belongs to
+	FeedURL     string // URL the feed itself is served from
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is one post of a feed
+type Item struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Summary   string
+	Content   string
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Render encodes the feed in the given format
+func Render(f Feed, format Format) ([]byte, error) {
+	switch format {
+	case RSS:
+		return renderRSS(f)
+	case Atom:
+		return renderAtom(f)
+	case JSONFeed:
+		return renderJSON(f)
+	}
+	return nil, ErrUnknownFormat
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func renderRSS(f Feed) ([]byte, error) {
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Self:        atomLink{Href: f.FeedURL, Rel: "self", Type: RSS.mediaType()},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range f.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: item.ID == item.Link, Value: item.ID},
+			Description: item.Summary,
+			Creator:     item.Author,
+			Categories:  item.Tags,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return marshalXML(doc)
+}
+
+type atomDoc struct {
+	XMLName  xml.Name    `xml:"feed"`
+	NS       string      `xml:"xmlns,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    atomText       `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func renderAtom(f Feed) ([]byte, error) {
+	doc := atomDoc{
+		NS:       "http://www.w3.org/2005/Atom",
+		Title:    f.Title,
+		Subtitle: f.Description,
+		ID:       f.FeedURL,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.FeedURL, Rel: "self", Type: Atom.mediaType()},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+	}
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Title:     item.Title,
+			ID:        item.ID,
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+			Summary:   item.Summary,
+			Content:   atomText{Type: "text", Value: item.Content},
+		}
+		if item.Author != "" {
+			entry.Author = &atomPerson{Name: item.Author}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return marshalXML(doc)
+}
+
+type jsonFeedDoc struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func renderJSON(f Feed) ([]byte, error) {
+	doc := jsonFeedDoc{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: f.Link,
+		FeedURL:     f.FeedURL,
+		Description: f.Description,
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range f.Items {
+		out := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		if item.Author != "" {
+			out.Authors = []jsonFeedAuthor{{Name: item.Author}}
+		}
+		doc.Items = append(doc.Items, out)
+	}
+	return json.Marshal(doc)
+}
+
+// media type without parameters, as used in link elements
+func (f Format) mediaType() string {
+	switch f {
+	case RSS:
+		return "application/rss+xml"
+	case Atom:
+		return "application/atom+xml"
+	default:
+		return "application/feed+json"
+	}
+}
+
+func marshalXML(doc interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,107 @@
+package syndication_test
+
+import (
+	"Gator_blog/syndication"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleFeed() syndication.Feed {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return syndication.Feed{
+		Title:       "Gator Blog",
+		Description: "Newest posts",
+		Link:        "http://example.com/home",
+		FeedURL:     "http://example.com/api/feeds/rss",
+		Updated:     published.Add(time.Hour),
+		Items: []syndication.Item{{
+			ID:        "http://example.com/post/1",
+			Title:     "Fish & <Chips>",
+			Link:      "http://example.com/post/1",
+			Author:    "alice",
+			Summary:   "Short",
+			Content:   "Long content",
+			Tags:      []string{"food", "uk"},
+			Published: published,
+			Updated:   published.Add(time.Hour),
+		}},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"rss", "atom", "json"} {
+		format, err := syndication.ParseFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, syndication.Format(name), format)
+	}
+	_, err := syndication.ParseFormat("xml")
+	assert.Equal(t, syndication.ErrUnknownFormat, err)
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := syndication.Render(sampleFeed(), syndication.RSS)
+	assert.NoError(t, err)
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title string `xml:"title"`
+			Items []struct {
+				Title      string   `xml:"title"`
+				GUID       string   `xml:"guid"`
+				PubDate    string   `xml:"pubDate"`
+				Categories []string `xml:"category"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	assert.NoError(t, xml.Unmarshal(body, &doc))
+	assert.Equal(t, "2.0", doc.Version)
+	assert.Len(t, doc.Channel.Items, 1)
+	assert.Equal(t, "Fish & <Chips>", doc.Channel.Items[0].Title)
+	assert.Equal(t, "Fri, 01 Mar 2024 12:00:00 +0000", doc.Channel.Items[0].PubDate)
+	assert.Equal(t, []string{"food", "uk"}, doc.Channel.Items[0].Categories)
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := syndication.Render(sampleFeed(), syndication.Atom)
+	assert.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Published string `xml:"published"`
+			Author    struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+		} `xml:"entry"`
+	}
+	assert.NoError(t, xml.Unmarshal(body, &doc))
+	assert.Equal(t, "http://www.w3.org/2005/Atom", doc.XMLName.Space)
+	assert.Equal(t, "2024-03-01T13:00:00Z", doc.Updated)
+	assert.Equal(t, "http://example.com/post/1", doc.Entries[0].ID)
+	assert.Equal(t, "2024-03-01T12:00:00Z", doc.Entries[0].Published)
+	assert.Equal(t, "alice", doc.Entries[0].Author.Name)
+}
+
+func TestRenderJSONFeed(t *testing.T) {
+	body, err := syndication.Render(sampleFeed(), syndication.JSONFeed)
+	assert.NoError(t, err)
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &doc))
+	assert.Equal(t, "https://jsonfeed.org/version/1.1", doc["version"])
+	item := doc["items"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Long content", item["content_text"])
+	assert.Equal(t, "alice", item["authors"].([]interface{})[0].(map[string]interface{})["name"])
+
+	empty := sampleFeed()
+	empty.Items = nil
+	body, _ = syndication.Render(empty, syndication.JSONFeed)
+	assert.Contains(t, string(body), `"items":[]`)
+}
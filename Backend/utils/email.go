@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"Gator_blog/config"
 	"fmt"
 	"log"
 	"net/smtp"
@@ -13,11 +14,16 @@ type EmailConfig struct {
 	Port     string
 }
 
-var Config = EmailConfig{
-	Sender:   "gatorblog.help@gmail.com",
-	Password: "vuky tvmy ojhj nksr",
-	Host:     "smtp.gmail.com",
-	Port:     "587",
+var Config EmailConfig
+
+// function to set the SMTP account password reset mails are sent with
+func InitEmail(cfg config.SMTPConfig) {
+	Config = EmailConfig{
+		Sender:   cfg.Sender,
+		Password: cfg.Password,
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+	}
 }
 
 func SendResetCodeEmail(toEmail, code string) error {
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Excerpt collapses whitespace in text and shortens it to at most max runes,
+// cutting at a word boundary and marking the cut with an ellipsis
+func Excerpt(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	runes := []rune(text)
+	cut := string(runes[:max-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
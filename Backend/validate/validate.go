@@ -0,0 +1,369 @@
+// Package validate checks request bodies against the rules in their
+// validate tags, e.g.
+//
+//	Title string `json:"title" validate:"required,max=title"`
+//
+// Rules are separated by commas:
+//
+//	omitempty   skip the other rules when the field is left out (nil)
+//	required    not left out, blank or zero
+//	min=N       at least N characters, items or as a number
+//	max=N       at most N characters, items or as a number
+//	email       a bare email address
+//	username    the characters allowed in usernames
+//	password    the password policy
+//	tag         the characters allowed in tag names
+//	dive        check each item of a slice, against the rules after dive
+//	            or, for structs, against their own rules
+//
+// N is a number or the name of a configurable limit: title, post,
+// username, password, comment or list_name. max=password counts bytes,
+// since bcrypt reads no more than 72 of them. Pointer fields are left out
+// when nil, which only breaks required, so "omitempty,required" on a
+// partial update means the field may be left out but not sent blank.
+// Errors on items name them like tags[1].name.
+package validate
+
+import (
+	"Gator_blog/config"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError is a rule a field of a request breaks
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors lists every rule a request breaks, in field order
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// TagPattern is the characters tag names may use
+const TagPattern = `^[\p{L}\p{N} ._+#-]*$`
+
+var tagName = regexp.MustCompile(TagPattern)
+
+// limits in effect, set from the configuration by Init
+var (
+	mu       sync.RWMutex
+	limits   = config.Defaults(config.Development).Validation
+	username = regexp.MustCompile(limits.UsernamePattern)
+)
+
+// Init applies the configured limits. The username pattern has been
+// checked by config.Validate.
+func Init(cfg config.ValidationConfig) {
+	mu.Lock()
+	defer mu.Unlock()
+	limits = cfg
+	username = regexp.MustCompile(cfg.UsernamePattern)
+}
+
+// Limits returns the limits in effect
+func Limits() config.ValidationConfig {
+	mu.RLock()
+	defer mu.RUnlock()
+	return limits
+}
+
+// value of a named limit, with the other end of its range for min
+func named(name string, l config.ValidationConfig, min bool) (int, bool) {
+	switch name {
+	case "title":
+		return l.TitleMax, true
+	case "post":
+		return l.PostMax, true
+	case "comment":
+		return l.CommentMax, true
+	case "list_name":
+		return l.ListNameMax, true
+	case "username":
+		if min {
+			return l.UsernameMin, true
+		}
+		return l.UsernameMax, true
+	case "password":
+		if min {
+			return l.PasswordMin, true
+		}
+		return l.PasswordMax, true
+	}
+	return 0, false
+}
+
+// a rule of a field as written in its tag
+type rule struct {
+	name string
+	arg  string
+}
+
+type field struct {
+	index int
+	name  string
+	rules []rule
+	// after dive, the rules of each item
+	dive bool
+	each []rule
+}
+
+// fields with rules of each struct type seen so far
+var types sync.Map
+
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := types.Load(t); ok {
+		return cached.([]field)
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			name = sf.Name
+		}
+		f := field{index: i, name: name}
+		for _, part := range strings.Split(tag, ",") {
+			ruleName, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch ruleName {
+			case "dive":
+				slice := sf.Type
+				if slice.Kind() == reflect.Ptr {
+					slice = slice.Elem()
+				}
+				if f.dive || slice.Kind() != reflect.Slice {
+					panic(fmt.Sprintf("validate: dive on %s.%s needs a slice", t.Name(), sf.Name))
+				}
+				f.dive = true
+				continue
+			case "omitempty", "required", "email", "username", "password", "tag":
+			case "min", "max":
+				if _, err := strconv.Atoi(arg); err != nil {
+					if _, ok := named(arg, config.ValidationConfig{}, false); !ok {
+						panic(fmt.Sprintf("validate: unknown limit %q on %s.%s", arg, t.Name(), sf.Name))
+					}
+				}
+			default:
+				panic(fmt.Sprintf("validate: unknown rule %q on %s.%s", ruleName, t.Name(), sf.Name))
+			}
+			if f.dive {
+				f.each = append(f.each, rule{ruleName, arg})
+			} else {
+				f.rules = append(f.rules, rule{ruleName, arg})
+			}
+		}
+		fields = append(fields, f)
+	}
+	types.Store(t, fields)
+	return fields
+}
+
+// Struct checks the struct v, or the struct v points to, and returns
+// Errors listing every broken rule, or nil
+func Struct(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic("validate: Struct needs a struct, not " + rv.Kind().String())
+	}
+	mu.RLock()
+	l, pattern := limits, username
+	mu.RUnlock()
+
+	if errs := structErrors(rv, "", l, pattern); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// the broken rules of the struct rv, with field names after prefix
+func structErrors(rv reflect.Value, prefix string, l config.ValidationConfig, pattern *regexp.Regexp) Errors {
+	var errs Errors
+	for _, f := range fieldsOf(rv.Type()) {
+		name := prefix + f.name
+		value := rv.Field(f.index)
+		if value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !hasRule(f.rules, "omitempty") && hasRule(f.rules, "required") {
+					errs = append(errs, FieldError{name, "required", name + " is required"})
+				}
+				continue
+			}
+			value = value.Elem()
+		}
+		if fe, ok := checkAll(name, f.rules, value, l, pattern); !ok {
+			errs = append(errs, fe)
+			continue
+		}
+		if !f.dive {
+			continue
+		}
+		for i := 0; i < value.Len(); i++ {
+			item, itemName := value.Index(i), fmt.Sprintf("%s[%d]", name, i)
+			if item.Kind() == reflect.Ptr {
+				if item.IsNil() {
+					continue
+				}
+				item = item.Elem()
+			}
+			if item.Kind() == reflect.Struct {
+				errs = append(errs, structErrors(item, itemName+".", l, pattern)...)
+			} else if fe, ok := checkAll(itemName, f.each, item, l, pattern); !ok {
+				errs = append(errs, fe)
+			}
+		}
+	}
+	return errs
+}
+
+// checks value against rules in order, stopping at the first it breaks
+// since later rules would only repeat what is wrong
+func checkAll(name string, rules []rule, value reflect.Value, l config.ValidationConfig, pattern *regexp.Regexp) (FieldError, bool) {
+	for _, r := range rules {
+		if fe, ok := check(name, r, value, l, pattern); !ok {
+			return fe, false
+		}
+	}
+	return FieldError{}, true
+}
+
+// Rule is a rule of a field with its limit resolved, for describing the
+// rules elsewhere, such as in the API specification
+type Rule struct {
+	Name  string
+	Limit int // bound of min and max
+}
+
+// Rules returns the rules of the fields of the struct type t in the limits
+// now in effect, keyed by JSON name. The rules of items after dive are not
+// included.
+func Rules(t reflect.Type) map[string][]Rule {
+	mu.RLock()
+	l := limits
+	mu.RUnlock()
+
+	out := map[string][]Rule{}
+	for _, f := range fieldsOf(t) {
+		for _, r := range f.rules {
+			resolved := Rule{Name: r.name}
+			if r.name == "min" || r.name == "max" {
+				bound, err := strconv.Atoi(r.arg)
+				if err != nil {
+					bound, _ = named(r.arg, l, r.name == "min")
+				}
+				resolved.Limit = bound
+			}
+			out[f.name] = append(out[f.name], resolved)
+		}
+	}
+	return out
+}
+
+func hasRule(rules []rule, name string) bool {
+	for _, r := range rules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checks value against r, returning the error when it breaks it
+func check(name string, r rule, value reflect.Value, l config.ValidationConfig, pattern *regexp.Regexp) (FieldError, bool) {
+	fail := func(format string, args ...interface{}) (FieldError, bool) {
+		return FieldError{Field: name, Rule: r.name, Message: name + " " + fmt.Sprintf(format, args...)}, false
+	}
+	switch r.name {
+	case "required":
+		if value.IsZero() || (value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "") ||
+			(value.Kind() == reflect.Slice && value.Len() == 0) {
+			return fail("is required")
+		}
+	case "min", "max":
+		bound, err := strconv.Atoi(r.arg)
+		if err != nil {
+			bound, _ = named(r.arg, l, r.name == "min")
+		}
+		size, unit := measure(value)
+		if r.arg == "password" && r.name == "max" && value.Kind() == reflect.String {
+			size, unit = len(value.String()), " bytes"
+		}
+		if r.name == "min" && size < bound {
+			return fail("must be at least %d%s", bound, unit)
+		}
+		if r.name == "max" && size > bound {
+			return fail("must be at most %d%s", bound, unit)
+		}
+	case "email":
+		s := value.String()
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s || !strings.Contains(s[strings.LastIndex(s, "@"):], ".") {
+			return fail("must be an email address")
+		}
+	case "username":
+		if !pattern.MatchString(value.String()) {
+			return fail("contains characters usernames may not use")
+		}
+	case "tag":
+		if !tagName.MatchString(value.String()) {
+			return fail("contains characters tags may not use")
+		}
+	case "password":
+		if classes := characterClasses(value.String()); classes < l.PasswordClasses {
+			return fail("must mix at least %d of lower case letters, upper case letters, digits and symbols", l.PasswordClasses)
+		}
+	}
+	return FieldError{}, true
+}
+
+// size of value for min and max, with the unit to report it in
+func measure(value reflect.Value) (int, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), " characters"
+	case reflect.Slice, reflect.Map:
+		return value.Len(), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint()), ""
+	}
+	panic("validate: min and max do not apply to " + value.Kind().String())
+}
+
+// counts the kinds of characters in s: lower case, upper case, digits and
+// everything else
+func characterClasses(s string) int {
+	var lower, upper, digit, other int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower + upper + digit + other
+}
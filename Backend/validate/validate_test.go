@@ -0,0 +1,139 @@
+package validate_test
+
+import (
+	"Gator_blog/config"
+	"Gator_blog/validate"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type signUp struct {
+	Username string `json:"username" validate:"required,min=username,max=username,username"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=password,max=password,password"`
+}
+
+type update struct {
+	Title *string  `json:"title" validate:"omitempty,required,max=title"`
+	Name  *string  `json:"name" validate:"required,max=5"`
+	Tags  []string `json:"tags" validate:"max=2"`
+	Count int      `json:"count" validate:"min=1"`
+}
+
+// fields and rules of the errors err lists
+func broken(err error) map[string]string {
+	out := map[string]string{}
+	if errs, ok := err.(validate.Errors); ok {
+		for _, fe := range errs {
+			out[fe.Field] = fe.Rule
+		}
+	}
+	return out
+}
+
+func TestStruct(t *testing.T) {
+	assert.NoError(t, validate.Struct(signUp{Username: "gator_42", Email: "al@example.com", Password: "swamp1234"}))
+
+	err := validate.Struct(&signUp{Username: "a b", Email: "Al <al@example.com>", Password: "short"})
+	assert.Equal(t, map[string]string{"username": "username", "email": "email", "password": "min"}, broken(err))
+	assert.Contains(t, err.Error(), "password must be at least 8 characters")
+
+	err = validate.Struct(signUp{Username: " ", Email: "al@localhost", Password: "onlyletters"})
+	assert.Equal(t, map[string]string{"username": "required", "email": "email", "password": "password"}, broken(err))
+
+	// limits count characters, not bytes
+	err = validate.Struct(signUp{Username: strings.Repeat("é", 30), Email: "al@example.com", Password: "swamp1234"})
+	assert.Equal(t, map[string]string{"username": "username"}, broken(err))
+	err = validate.Struct(signUp{Username: "gator", Email: "al@example.com", Password: strings.Repeat("a1", 37)})
+	assert.Equal(t, map[string]string{"password": "max"}, broken(err))
+
+	// except the longest password, bcrypt reads 72 bytes
+	err = validate.Struct(signUp{Username: "gator", Email: "al@example.com", Password: strings.Repeat("é", 40) + "1"})
+	assert.Equal(t, map[string]string{"password": "max"}, broken(err))
+	assert.Contains(t, err.Error(), "password must be at most 72 bytes")
+	assert.NoError(t, validate.Struct(signUp{Username: "gator", Email: "al@example.com", Password: strings.Repeat("é", 8) + "1"}))
+}
+
+type label struct {
+	Name string `json:"name" validate:"max=5,tag"`
+}
+
+type labelled struct {
+	Labels []label   `json:"labels" validate:"max=3,dive"`
+	Words  *[]string `json:"words" validate:"dive,required,max=4"`
+}
+
+func TestDive(t *testing.T) {
+	words := []string{"ok", " ", "toolong"}
+	err := validate.Struct(labelled{Labels: []label{{"go"}, {"c++"}, {"golang"}}, Words: &words})
+	assert.Equal(t, map[string]string{"labels[2].name": "max", "words[1]": "required", "words[2]": "max"}, broken(err))
+
+	err = validate.Struct(labelled{Labels: []label{{"go!"}}})
+	assert.Equal(t, map[string]string{"labels[0].name": "tag"}, broken(err))
+	assert.Contains(t, err.Error(), "labels[0].name contains characters tags may not use")
+
+	// items are not checked when the slice itself breaks a rule
+	err = validate.Struct(labelled{Labels: []label{{"a"}, {"b"}, {"c"}, {"d!"}}})
+	assert.Equal(t, map[string]string{"labels": "max"}, broken(err))
+
+	assert.NoError(t, validate.Struct(labelled{Labels: []label{{"web"}, {"go 1"}}}))
+	// rules of items are not rules of the slice
+	assert.Equal(t, []validate.Rule{{Name: "max", Limit: 3}}, validate.Rules(reflect.TypeOf(labelled{}))["labels"])
+}
+
+func TestStructPointersAndSizes(t *testing.T) {
+	blank, long, name := " ", strings.Repeat("x", 201), "ok"
+
+	// left out fields only break required
+	err := validate.Struct(update{Count: 1})
+	assert.Equal(t, map[string]string{"name": "required"}, broken(err))
+	assert.NoError(t, validate.Struct(update{Name: &name, Count: 1}))
+
+	err = validate.Struct(update{Title: &blank, Name: &long, Tags: []string{"a", "b", "c"}})
+	assert.Equal(t, map[string]string{"title": "required", "name": "max", "tags": "max", "count": "min"}, broken(err))
+	err = validate.Struct(update{Title: &long, Name: &name, Count: 1})
+	assert.Equal(t, map[string]string{"title": "max"}, broken(err))
+}
+
+func TestInitChangesLimits(t *testing.T) {
+	defaults := validate.Limits()
+	t.Cleanup(func() { validate.Init(defaults) })
+
+	limits := defaults
+	limits.UsernamePattern = `^[a-z]+$`
+	limits.PasswordMin, limits.PasswordClasses = 4, 0
+	validate.Init(limits)
+
+	err := validate.Struct(signUp{Username: "Gator", Email: "al@example.com", Password: "abcd"})
+	assert.Equal(t, map[string]string{"username": "username"}, broken(err))
+	assert.NoError(t, validate.Struct(signUp{Username: "gator", Email: "al@example.com", Password: "abcd"}))
+	assert.Equal(t, config.Defaults(config.Development).Validation, defaults)
+}
+
+func TestRules(t *testing.T) {
+	rules := validate.Rules(reflect.TypeOf(update{}))
+	assert.Equal(t, []validate.Rule{{Name: "omitempty"}, {Name: "required"}, {Name: "max", Limit: 200}}, rules["title"])
+	assert.Equal(t, []validate.Rule{{Name: "required"}, {Name: "max", Limit: 5}}, rules["name"])
+	assert.Equal(t, []validate.Rule{{Name: "min", Limit: 1}}, rules["count"])
+
+	rules = validate.Rules(reflect.TypeOf(signUp{}))
+	assert.Equal(t, []validate.Rule{{Name: "required"}, {Name: "min", Limit: 3}, {Name: "max", Limit: 30}, {Name: "username"}}, rules["username"])
+}
+
+func TestUnknownRulesPanic(t *testing.T) {
+	type bad struct {
+		Name string `validate:"required,shiny"`
+	}
+	type badLimit struct {
+		Name string `validate:"max=names"`
+	}
+	assert.Panics(t, func() { validate.Struct(bad{}) })
+	type badDive struct {
+		Name string `validate:"dive"`
+	}
+	assert.Panics(t, func() { validate.Struct(badLimit{}) })
+	assert.Panics(t, func() { validate.Struct(badDive{}) })
+}
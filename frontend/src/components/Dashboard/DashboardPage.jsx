@@ -44,7 +44,8 @@ const Dashboard = () => {
         
         // Format comments correctly if available
         if (blog.comments && Array.isArray(blog.comments)) {
-          const commentsCount = blog.comments.length;
+          // comments only carries a preview, the total comes from comments_count
+          const commentsCount = blog.comments_count ?? blog.comments.length;
           
           return {
             id: blog.id,
@@ -60,14 +60,14 @@ const ForgotPassword = () => {
       
       // Check for JSON content type
       const contentType = response.headers.get('content-type');
-      if (!contentType || !contentType.includes('application/json')) {
+      if (!contentType || !contentType.includes('json')) {
         throw new Error('Server returned non-JSON response. Please try again later.');
       }
       
       const data = await response.json();
       
       if (!response.ok) {
-        throw new Error(data.msg || 'Something went wrong');
+        throw new Error(data.detail || 'Something went wrong');
       }
       
       setSuccess('Verification code sent! Please check your email.');
@@ -104,14 +104,14 @@ const ForgotPassword = () => {
       
       // Check for JSON content type
       const contentType = response.headers.get('content-type');
-      if (!contentType || !contentType.includes('application/json')) {
+      if (!contentType || !contentType.includes('json')) {
         throw new Error('Server returned non-JSON response. Please try again later.');
       }
       
       const data = await response.json();
       
       if (!response.ok) {
-        throw new Error(data.msg || 'Invalid or expired code');
+        throw new Error(data.detail || 'Invalid or expired code');
       }
       
       setSuccess('Code verified successfully!');
@@ -161,14 +161,14 @@ const ForgotPassword = () => {
       
       // Check for JSON content type
       const contentType = response.headers.get('content-type');
-      if (!contentType || !contentType.includes('application/json')) {
+      if (!contentType || !contentType.includes('json')) {
         throw new Error('Server returned non-JSON response. Please try again later.');
       }
       
       const data = await response.json();
       
       if (!response.ok) {
-        throw new Error(data.msg || 'Failed to reset password');
+        throw new Error(data.detail || 'Failed to reset password');
       }
       
       setSuccess('Password reset successful!');
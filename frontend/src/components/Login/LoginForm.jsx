@@ -59,8 +59,8 @@ const LoginForm = () => {
             });
 
             const data = await response.json();
-            if (data.statusText === 'error') {
-                setError(data.msg);
+            if (!response.ok) {
+                setError(data.detail);
                 return;
             }else{
             login(data.token, data);
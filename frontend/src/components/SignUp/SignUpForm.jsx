@@ -81,8 +81,8 @@ const SignUpForm = () => {
 
       const data = await response.json();
 
-      if (data.statusText === 'error') {
-        setError(data.msg);
+      if (!response.ok) {
+        setError(data.detail);
         return;
       }
 
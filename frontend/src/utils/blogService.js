@@ -33,7 +33,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to fetch blogs with meta (Status: ${response.status})`;
+          errorMessage = errorData.detail || `Failed to fetch blogs with meta (Status: ${response.status})`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -71,7 +71,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || 'Failed to fetch blogs';
+          errorMessage = errorData.detail || 'Failed to fetch blogs';
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -104,7 +104,7 @@ const blogService = {
         try {
           // Try to parse as JSON if possible
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to fetch blog with ID ${id}`;
+          errorMessage = errorData.detail || `Failed to fetch blog with ID ${id}`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -150,7 +150,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || 'Failed to create blog';
+          errorMessage = errorData.detail || 'Failed to create blog';
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -180,7 +180,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to update blog with ID ${id}`;
+          errorMessage = errorData.detail || `Failed to update blog with ID ${id}`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -208,7 +208,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to delete blog with ID ${id}`;
+          errorMessage = errorData.detail || `Failed to delete blog with ID ${id}`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -235,7 +235,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to fetch comments for blog ID ${blogId}`;
+          errorMessage = errorData.detail || `Failed to fetch comments for blog ID ${blogId}`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;
@@ -269,7 +269,7 @@ const blogService = {
         try {
           // Try to parse as JSON
           const errorData = JSON.parse(errorText);
-          errorMessage = errorData.msg || `Failed to add comment to blog ID ${blogId}`;
+          errorMessage = errorData.detail || `Failed to add comment to blog ID ${blogId}`;
         } catch (e) {
           // If not valid JSON, use the text directly
           errorMessage = errorText || `Error ${response.status}: ${response.statusText}`;